@@ -2,24 +2,44 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
-	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/chi/v5/middleware"
 	"github.com/joho/godotenv"
 
 	httpDriver "github.com/danielrios/product-service-go/internal/adapters/driver/http"
 
+	"github.com/danielrios/product-service-go/internal/adapters/driven/filesink"
+	"github.com/danielrios/product-service-go/internal/adapters/driven/jwtauth"
+	"github.com/danielrios/product-service-go/internal/adapters/driven/memdb"
 	"github.com/danielrios/product-service-go/internal/adapters/driven/postgresdb"
+	"github.com/danielrios/product-service-go/internal/adapters/driven/webhookclient"
 	"github.com/danielrios/product-service-go/internal/application"
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
 )
 
+// productStore reúne as portas implementadas pelos adaptadores de persistência de produtos.
+type productStore interface {
+	ports.ProductRepository
+	ports.AuditRepository
+	ports.OutboxRepository
+	ports.ProductEventSource
+	ports.ChangeFeedRepository
+	ports.ProductHistoryRepository
+	ports.ProductQueryRepository
+	ports.ProductImportRepository
+}
+
 func main() {
 	if err := godotenv.Load(); err != nil {
 		log.Println("Aviso: Não foi possível carregar o arquivo .env. Usando variáveis de ambiente do sistema.")
@@ -27,46 +47,243 @@ func main() {
 
 	log.Println("Iniciando o microsserviço de produtos com Arquitetura Hexagonal...")
 
-	dbConnectionString := os.Getenv("DB_CONNECTION_STRING")
-	if dbConnectionString == "" {
-		log.Fatal("A variável de ambiente DB_CONNECTION_STRING não está definida.")
+	// --- 1. Inicializa os Driven Adapters (Repositórios) ---
+	var (
+		productRepo     productStore
+		webhookRepo     ports.WebhookRepository
+		idempotencyRepo ports.IdempotencyRepository
+		jobRepo         ports.JobRepository
+		apiKeyRepo      ports.APIKeyRepository
+	)
+	switch storageDriver := os.Getenv("STORAGE_DRIVER"); storageDriver {
+	case "", "postgres":
+		dbConnectionString := os.Getenv("DB_CONNECTION_STRING")
+		if dbConnectionString == "" {
+			log.Fatal("A variável de ambiente DB_CONNECTION_STRING não está definida.")
+		}
+
+		db, err := postgresdb.Connect(dbConnectionString)
+		if err != nil {
+			log.Fatalf("Não foi possível conectar ao banco de dados: %v", err)
+		}
+		defer db.Close()
+
+		productRepo = postgresdb.NewPostgresProductRepository(db)
+		webhookRepo = postgresdb.NewPostgresWebhookRepository(db)
+		idempotencyRepo = postgresdb.NewPostgresIdempotencyRepository(db)
+		jobRepo = postgresdb.NewPostgresJobRepository(db)
+		apiKeyRepo = postgresdb.NewPostgresAPIKeyRepository(db)
+	case "memory":
+		log.Println("Aviso: usando armazenamento em memória. Os dados serão perdidos ao encerrar o serviço.")
+		productRepo = memdb.NewInMemoryProductRepository()
+		webhookRepo = memdb.NewInMemoryWebhookRepository()
+		idempotencyRepo = memdb.NewInMemoryIdempotencyRepository()
+		jobRepo = memdb.NewInMemoryJobRepository()
+		apiKeyRepo = memdb.NewInMemoryAPIKeyRepository()
+	default:
+		log.Fatalf("STORAGE_DRIVER inválido: %q (use \"postgres\" ou \"memory\").", storageDriver)
 	}
 
-	// --- 1. Inicializa o Driven Adapter (Repositório de Produtos) ---
-	productRepo, err := postgresdb.NewPostgresProductRepository(dbConnectionString)
+	// --- 2. Inicializa os Application Services (Core) ---
+	jobConfig := application.DefaultJobConfig()
+	if rawWorkers := os.Getenv("JOB_WORKERS"); rawWorkers != "" {
+		workers, err := strconv.Atoi(rawWorkers)
+		if err != nil || workers <= 0 {
+			log.Fatalf("JOB_WORKERS inválido: %q (use um inteiro positivo).", rawWorkers)
+		}
+		jobConfig.Workers = workers
+	}
+	jobDrainTimeout := 10 * time.Second
+	if rawTimeout := os.Getenv("JOB_DRAIN_TIMEOUT"); rawTimeout != "" {
+		timeout, err := time.ParseDuration(rawTimeout)
+		if err != nil || timeout < 0 {
+			log.Fatalf("JOB_DRAIN_TIMEOUT inválido: %q (use uma duração, como \"10s\").", rawTimeout)
+		}
+		jobDrainTimeout = timeout
+	}
+	rolePermissions := loadRolePermissions()
+	authorizer, err := application.NewAuthorizer(rolePermissions, loadProductFieldPolicy())
 	if err != nil {
-		log.Fatalf("Não foi possível conectar ao banco de dados: %v", err)
+		log.Fatalf("Matriz de papéis ou política de campos inválida: %v", err)
+	}
+	apiKeyConfig := application.DefaultAPIKeyConfig()
+	apiKeyConfig.Roles = rolePermissions
+	apiKeyService := application.NewAPIKeyService(apiKeyRepo, apiKeyConfig)
+
+	verifiers := map[string]ports.CredentialVerifier{}
+	if verifier := newJWTVerifier(); verifier != nil {
+		verifiers[httpDriver.AuthSchemeBearer] = verifier
+	}
+	if rawAPIKeyAuth := os.Getenv("API_KEY_AUTH"); rawAPIKeyAuth != "" {
+		enabled, err := strconv.ParseBool(rawAPIKeyAuth)
+		if err != nil {
+			log.Fatalf("API_KEY_AUTH inválido: %q (use \"true\" ou \"false\").", rawAPIKeyAuth)
+		}
+		if enabled {
+			verifiers[httpDriver.AuthSchemeAPIKey] = apiKeyService
+			log.Println("Autenticação por chaves de API habilitada.")
+			if _, jwtEnabled := verifiers[httpDriver.AuthSchemeBearer]; !jwtEnabled {
+				bootstrapAPIKey(apiKeyService)
+			}
+		}
+	}
+
+	// Sem autenticação não há principal nem papéis; a autorização só é aplicada às requisições autenticadas.
+	var jobServiceOpts []application.JobServiceOption
+	if len(verifiers) > 0 {
+		jobServiceOpts = append(jobServiceOpts, application.WithJobAuthorizer(authorizer))
+	}
+	jobService := application.NewJobService(jobRepo, jobConfig, jobServiceOpts...)
+
+	productServiceOpts := []application.ProductServiceOption{
+		application.WithHistory(productRepo),
+		application.WithQueries(productRepo),
+		application.WithImports(productRepo),
+		application.WithJobs(jobService),
+	}
+	if len(verifiers) > 0 {
+		productServiceOpts = append(productServiceOpts, application.WithAuthorizer(authorizer))
+	}
+	productService := application.NewProductService(productRepo, productServiceOpts...)
+	auditService := application.NewAuditService(productRepo)
+	changeFeedService := application.NewChangeFeedService(productRepo)
+	idempotencyConfig := application.DefaultIdempotencyConfig()
+	if rawTTL := os.Getenv("IDEMPOTENCY_TTL"); rawTTL != "" {
+		ttl, err := time.ParseDuration(rawTTL)
+		if err != nil || ttl <= 0 {
+			log.Fatalf("IDEMPOTENCY_TTL inválido: %q (use uma duração positiva, como \"24h\").", rawTTL)
+		}
+		idempotencyConfig.TTL = ttl
+	}
+	idempotencyService := application.NewIdempotencyService(idempotencyRepo, idempotencyConfig)
+	webhookService := application.NewWebhookService(webhookRepo, webhookclient.NewHTTPSender(10*time.Second), application.DefaultWebhookConfig())
+
+	// --- 2.1. Inicializa os workers: relay do outbox, entrega de webhooks, hub de alterações, limpeza das chaves de idempotência e jobs ---
+	sinks := application.MultiSink{webhookService}
+	if sinkFile := os.Getenv("OUTBOX_SINK_FILE"); sinkFile != "" {
+		sink, err := filesink.NewNDJSONSink(sinkFile)
+		if err != nil {
+			log.Fatalf("Não foi possível abrir o arquivo de eventos %s: %v", sinkFile, err)
+		}
+		defer sink.Close()
+
+		sinks = append(sinks, sink)
+		log.Printf("Relay do outbox entregando eventos também em %s.", sinkFile)
 	}
+	relay := application.NewOutboxRelay(productRepo, sinks, application.DefaultOutboxRelayConfig())
+	changeHub := application.NewChangeHub(productRepo, application.DefaultChangeHubConfig())
 
-	// --- 2. Inicializa o Application Service (Core) ---
-	productService := application.NewProductService(productRepo)
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	var workersWG sync.WaitGroup
+	for _, run := range []func(context.Context){relay.Run, webhookService.Run, changeHub.Run, idempotencyService.Run, jobService.Run} {
+		workersWG.Add(1)
+		go func() {
+			defer workersWG.Done()
+			run(workersCtx)
+		}()
+	}
 
-	// --- 3. Inicializa o Driving Adapter (Handler HTTP) ---
-	productHandler := httpDriver.NewProductHandler(productService)
+	// --- 3. Inicializa os Driving Adapters (Handlers HTTP) ---
+	var productHandlerOpts []httpDriver.ProductHandlerOption
+	if cacheControl, ok := os.LookupEnv("PRODUCT_CACHE_CONTROL"); ok {
+		productHandlerOpts = append(productHandlerOpts, httpDriver.WithCacheControl(cacheControl))
+	}
+	productHandler := httpDriver.NewProductHandler(productService, productHandlerOpts...)
+	productHandlerV2 := httpDriver.NewProductHandler(productService, append(productHandlerOpts, httpDriver.WithAPIVersion(httpDriver.APIVersion2))...)
+	webhookHandler := httpDriver.NewWebhookHandler(webhookService)
+	streamHandler := httpDriver.NewProductStreamHandler(changeHub, 15*time.Second)
+	changeFeedHandler := httpDriver.NewChangeFeedHandler(changeFeedService)
+	auditHandler := httpDriver.NewAuditHandler(auditService)
+	jobHandler := httpDriver.NewJobHandler(jobService)
+	apiKeyHandler := httpDriver.NewAPIKeyHandler(apiKeyService)
 
 	// --- 4. Configura as Rotas HTTP com chi ---
-	r := chi.NewRouter()
-	r.Use(middleware.Logger)
-	r.Use(middleware.Recoverer)
-	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
-
-	r.Route("/products", func(r chi.Router) {
-		r.Get("/", productHandler.GetAllProductsHandler)
-		r.Post("/", productHandler.CreateProductHandler)
-
-		r.Route("/{id}", func(r chi.Router) {
-			r.Get("/", productHandler.GetProductByIDHandler)
-			r.Put("/", productHandler.UpdateProductHandler)
-			r.Delete("/", productHandler.DeleteProductHandler)
-		})
-	})
+	var routerOpts []httpDriver.RouterOption
+	if rawValidation := os.Getenv("OPENAPI_VALIDATION"); rawValidation != "" {
+		enabled, err := strconv.ParseBool(rawValidation)
+		if err != nil {
+			log.Fatalf("OPENAPI_VALIDATION inválido: %q (use \"true\" ou \"false\").", rawValidation)
+		}
+		if enabled {
+			validator, err := httpDriver.NewOpenAPIValidator()
+			if err != nil {
+				log.Fatalf("Não foi possível carregar a especificação OpenAPI: %v", err)
+			}
+			routerOpts = append(routerOpts, httpDriver.WithOpenAPIValidation(validator))
+			log.Println("Validando requisições e respostas contra a especificação OpenAPI.")
+		}
+	}
+	if rawSunset := os.Getenv("API_V1_SUNSET"); rawSunset != "" {
+		sunset, err := time.Parse(time.RFC3339, rawSunset)
+		if err != nil {
+			log.Fatalf("API_V1_SUNSET inválido: %q (use uma data RFC 3339, como \"2027-04-30T00:00:00Z\").", rawSunset)
+		}
+		routerOpts = append(routerOpts, httpDriver.WithV1Sunset(sunset))
+	}
+	maxBodyBytes, maxImportBodyBytes := httpDriver.DefaultMaxBodyBytes, httpDriver.DefaultMaxImportBodyBytes
+	if rawLimit := os.Getenv("MAX_BODY_BYTES"); rawLimit != "" {
+		limit, err := strconv.ParseInt(rawLimit, 10, 64)
+		if err != nil {
+			log.Fatalf("MAX_BODY_BYTES inválido: %q (use um inteiro; zero ou negativo remove o limite).", rawLimit)
+		}
+		maxBodyBytes = limit
+	}
+	if rawLimit := os.Getenv("MAX_IMPORT_BODY_BYTES"); rawLimit != "" {
+		limit, err := strconv.ParseInt(rawLimit, 10, 64)
+		if err != nil {
+			log.Fatalf("MAX_IMPORT_BODY_BYTES inválido: %q (use um inteiro; zero ou negativo remove o limite).", rawLimit)
+		}
+		maxImportBodyBytes = limit
+	}
+	routerOpts = append(routerOpts, httpDriver.WithMaxBodyBytes(maxBodyBytes, maxImportBodyBytes))
+	compression := httpDriver.DefaultCompressionConfig()
+	if rawMinSize := os.Getenv("COMPRESSION_MIN_SIZE"); rawMinSize != "" {
+		minSize, err := strconv.Atoi(rawMinSize)
+		if err != nil || minSize < 0 {
+			log.Fatalf("COMPRESSION_MIN_SIZE inválido: %q (use um inteiro não negativo).", rawMinSize)
+		}
+		compression.MinSize = minSize
+	}
+	if rawContentTypes, ok := os.LookupEnv("COMPRESSION_CONTENT_TYPES"); ok {
+		compression.ContentTypes = nil
+		for _, contentType := range strings.Split(rawContentTypes, ",") {
+			if contentType = strings.TrimSpace(contentType); contentType != "" {
+				compression.ContentTypes = append(compression.ContentTypes, contentType)
+			}
+		}
+	}
+	routerOpts = append(routerOpts, httpDriver.WithCompression(compression))
+	if rawRatio := os.Getenv("MAX_DECOMPRESSION_RATIO"); rawRatio != "" {
+		ratio, err := strconv.ParseInt(rawRatio, 10, 64)
+		if err != nil {
+			log.Fatalf("MAX_DECOMPRESSION_RATIO inválido: %q (use um inteiro; zero ou negativo remove o limite).", rawRatio)
+		}
+		routerOpts = append(routerOpts, httpDriver.WithMaxDecompressionRatio(ratio))
+	}
+	if len(verifiers) > 0 {
+		routerOpts = append(routerOpts, httpDriver.WithAuthentication(verifiers), httpDriver.WithAuthorization(authorizer))
+	} else {
+		log.Println("Aviso: nem JWT nem chaves de API configurados; a API não exige autenticação.")
+	}
+	r := httpDriver.NewRouter(httpDriver.Handlers{
+		Products:    productHandler,
+		ProductsV2:  productHandlerV2,
+		Stream:      streamHandler,
+		ChangeFeed:  changeFeedHandler,
+		Audit:       auditHandler,
+		Jobs:        jobHandler,
+		Webhooks:    webhookHandler,
+		APIKeys:     apiKeyHandler,
+		Idempotency: idempotencyService,
+	}, routerOpts...)
 
 	// --- 5. Inicia o Servidor HTTP ---
 	server := &http.Server{
 		Addr:    ":8080",
 		Handler: r,
 	}
+	// Encerra as conexões de streaming, que do contrário impediriam o graceful shutdown.
+	server.RegisterOnShutdown(changeHub.Close)
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
@@ -89,5 +306,157 @@ func main() {
 		log.Fatalf("Servidor forçado a desligar: %v", err)
 	}
 
+	// Os jobs em execução têm um prazo próprio para terminar; os que não terminarem voltam para a fila.
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), jobDrainTimeout)
+	defer cancelDrain()
+	if err := jobService.Shutdown(drainCtx); err != nil {
+		log.Printf("Jobs em execução interrompidos no encerramento: %v", err)
+	}
+
+	stopWorkers()
+	workersWG.Wait()
+
 	log.Println("Servidor desligado graciosamente.")
 }
+
+// loadRolePermissions carrega a matriz de papéis e permissões do arquivo JSON em RBAC_ROLES_FILE, que associa
+// cada papel à lista das suas permissões. Sem o arquivo, usa a matriz padrão.
+func loadRolePermissions() models.RolePermissions {
+	path := os.Getenv("RBAC_ROLES_FILE")
+	if path == "" {
+		return models.DefaultRolePermissions()
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatalf("Não foi possível ler a matriz de papéis em %s: %v", path, err)
+	}
+	var roles models.RolePermissions
+	if err := json.Unmarshal(data, &roles); err != nil {
+		log.Fatalf("Matriz de papéis inválida em %s: %v", path, err)
+	}
+	log.Printf("Matriz de papéis carregada de %s: %s.", path, strings.Join(roles.Roles(), ", "))
+	return roles
+}
+
+// loadProductFieldPolicy carrega a política de campos de produtos do arquivo JSON em RBAC_FIELD_POLICY_FILE,
+// com os campos que cada papel pode alterar ("write") e ler ("read"). Sem o arquivo, usa a política padrão.
+func loadProductFieldPolicy() models.ProductFieldPolicy {
+	path := os.Getenv("RBAC_FIELD_POLICY_FILE")
+	if path == "" {
+		return models.DefaultProductFieldPolicy()
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatalf("Não foi possível ler a política de campos em %s: %v", path, err)
+	}
+	var policy models.ProductFieldPolicy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		log.Fatalf("Política de campos inválida em %s: %v", path, err)
+	}
+	log.Printf("Política de campos de produtos carregada de %s.", path)
+	return policy
+}
+
+// bootstrapAPIKey emite uma chave de API com o escopo e o papel admin quando não há nenhuma chave ativa, para que as
+// chaves das integrações possam ser criadas sem um provedor de tokens JWT. A chave expira em 24 horas e só é
+// emitida quando API_KEY_BOOTSTRAP_FILE indica onde gravá-la: o segredo vai para esse arquivo, legível apenas
+// pelo dono, e nunca para o log.
+func bootstrapAPIKey(service *application.APIKeyService) {
+	keys, err := service.ListAPIKeys()
+	if err != nil {
+		log.Fatalf("Não foi possível listar as chaves de API: %v", err)
+	}
+	for _, key := range keys {
+		if key.IsActive(time.Now()) {
+			return
+		}
+	}
+
+	path := os.Getenv("API_KEY_BOOTSTRAP_FILE")
+	if path == "" {
+		log.Println("Nenhuma chave de API ativa; defina API_KEY_BOOTSTRAP_FILE para emitir uma chave inicial.")
+		return
+	}
+
+	expiresAt := time.Now().Add(24 * time.Hour)
+	ctx := application.WithActor(context.Background(), "bootstrap")
+	key, secret, err := service.CreateAPIKey(ctx, "bootstrap", []string{models.ScopeAdmin}, []string{models.RoleAdmin}, &expiresAt)
+	if err != nil {
+		log.Fatalf("Não foi possível criar a chave de API inicial: %v", err)
+	}
+	if err := writeSecretFile(path, secret); err != nil {
+		// Sem o arquivo, ninguém conhece o segredo; a chave é revogada para que a próxima inicialização emita outra.
+		log.Fatalf("Não foi possível gravar a chave de API inicial em %s: %v", path, errors.Join(err, service.RevokeAPIKey(key.ID)))
+	}
+	log.Printf("Nenhuma chave de API ativa; chave inicial com o escopo e o papel admin, válida por 24 horas, gravada em %s.", path)
+}
+
+// writeSecretFile grava o segredo em um arquivo com permissão 0600, substituindo o conteúdo anterior.
+func writeSecretFile(path, secret string) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	// OpenFile só aplica a permissão a arquivos novos.
+	if err := file.Chmod(0o600); err != nil {
+		return errors.Join(err, file.Close())
+	}
+	_, err = file.WriteString(secret + "\n")
+	return errors.Join(err, file.Close())
+}
+
+// newJWTVerifier configura a validação dos tokens JWT a partir das variáveis de ambiente, carregando as chaves
+// do JWKS em JWT_JWKS_FILE ou JWT_JWKS_URL. Retorna nil quando nenhuma das duas está definida.
+func newJWTVerifier() *jwtauth.Verifier {
+	jwksFile, jwksURL := os.Getenv("JWT_JWKS_FILE"), os.Getenv("JWT_JWKS_URL")
+	if jwksFile == "" && jwksURL == "" {
+		return nil
+	}
+	if jwksFile != "" && jwksURL != "" {
+		log.Fatal("Defina apenas uma das variáveis JWT_JWKS_FILE e JWT_JWKS_URL.")
+	}
+
+	keySetConfig := jwtauth.DefaultKeySetConfig()
+	if rawInterval := os.Getenv("JWT_JWKS_REFRESH_INTERVAL"); rawInterval != "" {
+		interval, err := time.ParseDuration(rawInterval)
+		if err != nil || interval <= 0 {
+			log.Fatalf("JWT_JWKS_REFRESH_INTERVAL inválido: %q (use uma duração positiva, como \"5m\").", rawInterval)
+		}
+		keySetConfig.RefreshInterval = interval
+	}
+	var keySet *jwtauth.KeySet
+	if jwksFile != "" {
+		keySet = jwtauth.NewFileKeySet(jwksFile, keySetConfig)
+	} else {
+		keySet = jwtauth.NewURLKeySet(jwksURL, &http.Client{Timeout: 10 * time.Second}, keySetConfig)
+	}
+	if err := keySet.Refresh(context.Background()); err != nil {
+		log.Fatalf("Não foi possível carregar as chaves do JWKS: %v", err)
+	}
+
+	verifierConfig := jwtauth.DefaultVerifierConfig()
+	verifierConfig.Issuer, verifierConfig.Audience = os.Getenv("JWT_ISSUER"), os.Getenv("JWT_AUDIENCE")
+	if verifierConfig.Issuer == "" || verifierConfig.Audience == "" {
+		log.Fatal("JWT_ISSUER e JWT_AUDIENCE devem ser definidas junto com o JWKS.")
+	}
+	if rawAlgorithms := os.Getenv("JWT_ALGORITHMS"); rawAlgorithms != "" {
+		verifierConfig.Algorithms = nil
+		for _, algorithm := range strings.Split(rawAlgorithms, ",") {
+			switch algorithm = strings.TrimSpace(algorithm); algorithm {
+			case "RS256", "ES256", "HS256":
+				verifierConfig.Algorithms = append(verifierConfig.Algorithms, algorithm)
+			default:
+				log.Fatalf("JWT_ALGORITHMS inválido: %q (use RS256, ES256 e HS256, separados por vírgula).", rawAlgorithms)
+			}
+		}
+	}
+	if rawLeeway := os.Getenv("JWT_LEEWAY"); rawLeeway != "" {
+		leeway, err := time.ParseDuration(rawLeeway)
+		if err != nil || leeway < 0 {
+			log.Fatalf("JWT_LEEWAY inválido: %q (use uma duração, como \"30s\").", rawLeeway)
+		}
+		verifierConfig.Leeway = leeway
+	}
+	log.Printf("Autenticação JWT habilitada (emissor %s, audiência %s).", verifierConfig.Issuer, verifierConfig.Audience)
+	return jwtauth.NewVerifier(keySet, verifierConfig)
+}
@@ -0,0 +1,33 @@
+package eventstore
+
+import (
+	"slices"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+var _ ports.AuditRepository = (*EventSourcedProductRepository)(nil)
+
+// ListAuditEntries deriva o log de auditoria dos streams: cada evento gravado é uma mutação, com o autor e
+// a requisição registrados junto dele, e o estado anterior é o do evento precedente no mesmo stream.
+// Os registros são retornados dos mais recentes para os mais antigos.
+func (r *EventSourcedProductRepository) ListAuditEntries(filter models.AuditFilter) ([]*models.AuditEntry, error) {
+	previous := make(map[string]*models.Product)
+	var entries []*models.AuditEntry
+	err := r.store.LoadAll(0, func(event StoredEvent) error {
+		entry := models.NewEventAuditEntry(event.Event, previous[event.StreamID], event.Mutation)
+		entry.ID = event.Event.ID
+		previous[event.StreamID] = applyEvent(event.Event)
+		if filter.Matches(entry) {
+			entries = append(entries, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Reverse(entries)
+	return append(make([]*models.AuditEntry, 0), entries[:min(filter.Limit, len(entries))]...), nil
+}
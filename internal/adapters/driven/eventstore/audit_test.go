@@ -0,0 +1,53 @@
+package eventstore_test
+
+import (
+	"testing"
+
+	"github.com/danielrios/product-service-go/internal/adapters/driven/eventstore"
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+func TestEventSourcedProductRepository_ListAuditEntries(t *testing.T) {
+	repo := newRepository(t, eventstore.NewMemoryEventStore(), nil, 0)
+	product, _ := models.NewProduct("1", "Test Product", 100.0)
+	_ = repo.Add(product, models.Mutation{Actor: "alice", RequestID: "req-1"})
+	_ = repo.Update(&models.Product{ID: "1", Name: "Test Product", Price: 150.0}, nil, models.Mutation{Actor: "bob", RequestID: "req-2"})
+	other, _ := models.NewProduct("2", "Other Product", 10.0)
+	_ = repo.Add(other, models.Mutation{Actor: "alice", RequestID: "req-3"})
+	_ = repo.Delete("1", models.Mutation{Actor: "alice", RequestID: "req-4"})
+
+	t.Run("Derives Entries From The Streams", func(t *testing.T) {
+		entries, err := repo.ListAuditEntries(models.AuditFilter{ProductID: "1", Limit: 10})
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		expected := []struct {
+			operation models.AuditOperation
+			requestID string
+		}{
+			{models.AuditOperationDelete, "req-4"},
+			{models.AuditOperationUpdate, "req-2"},
+			{models.AuditOperationCreate, "req-1"},
+		}
+		if len(entries) != len(expected) {
+			t.Fatalf("Expected %d entries, got %d", len(expected), len(entries))
+		}
+		for i, e := range expected {
+			if entries[i].Operation != e.operation || entries[i].RequestID != e.requestID {
+				t.Errorf("Unexpected entry %d: %+v", i, entries[i])
+			}
+		}
+		if entries[1].Actor != "bob" || entries[1].Before.Price != 100.0 || entries[1].After.Price != 150.0 {
+			t.Errorf("Expected the update to record the previous state of the stream, got %+v", entries[1])
+		}
+	})
+
+	t.Run("Applies The Filter Before The Limit", func(t *testing.T) {
+		entries, _ := repo.ListAuditEntries(models.AuditFilter{Actor: "alice", Limit: 2})
+
+		if len(entries) != 2 || entries[0].RequestID != "req-4" || entries[1].RequestID != "req-3" {
+			t.Errorf("Expected the two most recent entries by alice, got %+v", entries)
+		}
+	})
+}
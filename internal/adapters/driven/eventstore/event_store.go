@@ -0,0 +1,53 @@
+package eventstore
+
+import (
+	"errors"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+// ErrConcurrencyConflict indica que o stream foi alterado desde a versão esperada pelo chamador.
+var ErrConcurrencyConflict = errors.New("event stream was modified concurrently")
+
+// ErrEventLogUnavailable indica que uma gravação falhou sem que o log pudesse ser restaurado,
+// e o store deixou de aceitar novos eventos.
+var ErrEventLogUnavailable = errors.New("event log is unavailable after a failed write")
+
+// StoredEvent é um evento gravado em um stream. Version é a posição do evento dentro do seu stream
+// (começando em 1) e Position é a posição global, crescente entre todos os streams. Mutation identifica
+// o autor e a requisição da mutação que gravou o evento, e é a fonte do log de auditoria.
+type StoredEvent struct {
+	StreamID string
+	Version  int
+	Position int64
+	Event    models.ProductEvent
+	Mutation models.Mutation
+}
+
+// Snapshot guarda o estado de um agregado em uma versão do stream, evitando reprocessar todos os eventos.
+// Product é nulo quando o produto estava excluído naquela versão.
+type Snapshot struct {
+	StreamID string
+	Version  int
+	Product  *models.Product
+}
+
+// EventStore armazena streams de eventos apenas com inserções.
+type EventStore interface {
+	// Append grava os eventos no fim do stream, desde que sua versão atual seja expectedVersion
+	// (0 para um stream novo), registrando neles a mutação informada. Caso contrário, retorna
+	// ErrConcurrencyConflict.
+	Append(streamID string, expectedVersion int, events []models.ProductEvent, mutation models.Mutation) ([]StoredEvent, error)
+	// Load retorna, em ordem, os eventos do stream com versão maior que afterVersion.
+	Load(streamID string, afterVersion int) ([]StoredEvent, error)
+	// LoadAll invoca fn para cada evento de todos os streams com posição global maior que afterPosition, em ordem.
+	LoadAll(afterPosition int64, fn func(event StoredEvent) error) error
+}
+
+// SnapshotStore armazena o snapshot mais recente de cada stream.
+type SnapshotStore interface {
+	// SaveSnapshot substitui o snapshot do stream.
+	SaveSnapshot(snapshot Snapshot) error
+	// LoadSnapshot retorna o snapshot do stream, ou nil se não houver nenhum.
+	LoadSnapshot(streamID string) (*Snapshot, error)
+}
@@ -0,0 +1,7 @@
+package eventstore
+
+// EventFile expõe a abstração do arquivo de eventos para os testes.
+type EventFile = eventFile
+
+// NewFileEventStore permite aos testes abrir o store sobre um arquivo com falhas simuladas.
+var NewFileEventStore = newFileEventStore
@@ -0,0 +1,224 @@
+package eventstore
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+// eventFile é o arquivo em que o FileEventStore grava os eventos; *os.File o satisfaz.
+type eventFile interface {
+	io.ReadWriteSeeker
+	io.Closer
+	Truncate(size int64) error
+	Sync() error
+}
+
+// FileEventStore é um EventStore persistido em um arquivo NDJSON, com um evento por linha.
+// O arquivo é apenas acrescido; um índice em memória é reconstruído a partir dele na abertura.
+type FileEventStore struct {
+	file   eventFile
+	size   int64
+	failed error
+	index  *MemoryEventStore
+	mu     sync.Mutex
+}
+
+// OpenFileEventStore abre (ou cria) o arquivo de eventos e carrega seu conteúdo.
+// Uma última linha incompleta, deixada por uma gravação interrompida, é descartada.
+func OpenFileEventStore(path string) (*FileEventStore, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return newFileEventStore(file)
+}
+
+// newFileEventStore carrega o conteúdo do arquivo já aberto e o posiciona ao fim da parte válida.
+func newFileEventStore(file eventFile) (*FileEventStore, error) {
+	index := NewMemoryEventStore()
+	validSize, err := loadEventLog(file, index)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if err := file.Truncate(validSize); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if _, err := file.Seek(validSize, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &FileEventStore{file: file, size: validSize, index: index}, nil
+}
+
+var _ EventStore = (*FileEventStore)(nil)
+
+// Append grava os eventos no arquivo, forçando sua persistência em disco antes de torná-los visíveis.
+// Se a gravação falhar, o arquivo é truncado de volta ao fim do último evento confirmado; se nem
+// isso for possível, o store passa a recusar novas gravações com ErrEventLogUnavailable.
+func (s *FileEventStore) Append(streamID string, expectedVersion int, events []models.ProductEvent, mutation models.Mutation) ([]StoredEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failed != nil {
+		return nil, fmt.Errorf("%w: %v", ErrEventLogUnavailable, s.failed)
+	}
+
+	s.index.mu.RLock()
+	stored, err := nextEvents(streamID, expectedVersion, len(s.index.streams[streamID]), int64(len(s.index.all)), events, mutation)
+	s.index.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, event := range stored {
+		if err := encoder.Encode(event); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.write(buf.Bytes()); err != nil {
+		return nil, err
+	}
+
+	return s.index.Append(streamID, expectedVersion, events, mutation)
+}
+
+// write acresce os dados ao arquivo e os sincroniza, desfazendo a gravação parcial em caso de erro.
+func (s *FileEventStore) write(data []byte) error {
+	_, err := s.file.Write(data)
+	if err == nil {
+		err = s.file.Sync()
+	}
+	if err == nil {
+		s.size += int64(len(data))
+		return nil
+	}
+
+	if truncErr := s.file.Truncate(s.size); truncErr != nil {
+		s.failed = truncErr
+	} else if _, seekErr := s.file.Seek(s.size, io.SeekStart); seekErr != nil {
+		s.failed = seekErr
+	}
+	return err
+}
+
+// Load retorna os eventos do stream posteriores a afterVersion.
+func (s *FileEventStore) Load(streamID string, afterVersion int) ([]StoredEvent, error) {
+	return s.index.Load(streamID, afterVersion)
+}
+
+// LoadAll percorre os eventos de todos os streams em ordem global.
+func (s *FileEventStore) LoadAll(afterPosition int64, fn func(event StoredEvent) error) error {
+	return s.index.LoadAll(afterPosition, fn)
+}
+
+// Close fecha o arquivo de eventos.
+func (s *FileEventStore) Close() error {
+	return s.file.Close()
+}
+
+// loadEventLog lê o arquivo de eventos para o índice e retorna o tamanho da parte válida do arquivo.
+func loadEventLog(file io.Reader, index *MemoryEventStore) (int64, error) {
+	reader := bufio.NewReader(file)
+	var validSize int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			// Sem quebra de linha final, a gravação foi interrompida: a linha é descartada.
+			return validSize, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		var event StoredEvent
+		if err := json.Unmarshal(line, &event); err != nil {
+			return 0, fmt.Errorf("corrupted event log at offset %d: %w", validSize, err)
+		}
+		if event.Position != int64(len(index.all))+1 || event.Version != len(index.streams[event.StreamID])+1 {
+			return 0, fmt.Errorf("corrupted event log at offset %d: unexpected position %d", validSize, event.Position)
+		}
+		index.streams[event.StreamID] = append(index.streams[event.StreamID], event)
+		index.all = append(index.all, event)
+		validSize += int64(len(line))
+	}
+}
+
+// FileSnapshotStore é um SnapshotStore que grava um arquivo JSON por stream em um diretório.
+type FileSnapshotStore struct {
+	dir string
+}
+
+// NewFileSnapshotStore cria o diretório de snapshots, se necessário.
+func NewFileSnapshotStore(dir string) (*FileSnapshotStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileSnapshotStore{dir: dir}, nil
+}
+
+var _ SnapshotStore = (*FileSnapshotStore)(nil)
+
+// SaveSnapshot grava o snapshot em um arquivo temporário e o renomeia, para que uma falha
+// no meio da gravação nunca deixe um snapshot corrompido.
+func (s *FileSnapshotStore) SaveSnapshot(snapshot Snapshot) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.dir, "snapshot-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(snapshot.StreamID))
+}
+
+// LoadSnapshot lê o snapshot do stream, se houver.
+func (s *FileSnapshotStore) LoadSnapshot(streamID string) (*Snapshot, error) {
+	data, err := os.ReadFile(s.path(streamID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// path codifica o ID do stream em hexadecimal para que qualquer ID seja um nome de arquivo seguro.
+func (s *FileSnapshotStore) path(streamID string) string {
+	return filepath.Join(s.dir, hex.EncodeToString([]byte(streamID))+".json")
+}
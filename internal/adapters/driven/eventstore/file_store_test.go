@@ -0,0 +1,178 @@
+package eventstore_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/danielrios/product-service-go/internal/adapters/driven/eventstore"
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+func TestFileEventStore_ReplaysAfterReopen(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "events.ndjson")
+	snapshotDir := filepath.Join(dir, "snapshots")
+
+	store, err := eventstore.OpenFileEventStore(path)
+	if err != nil {
+		t.Fatalf("Failed to open event store: %v", err)
+	}
+	snapshots, _ := eventstore.NewFileSnapshotStore(snapshotDir)
+	repo := newRepository(t, store, snapshots, 2)
+	product, _ := models.NewProduct("1", "Test Product", 100.0)
+	_ = repo.Add(product, models.Mutation{})
+	_ = repo.Update(&models.Product{ID: "1", Name: "Test Product", Price: 150.0}, nil, models.Mutation{})
+	other, _ := models.NewProduct("2", "Other Product", 50.0)
+	_ = repo.Add(other, models.Mutation{})
+	_ = store.Close()
+
+	// Simula uma gravação interrompida no meio de uma linha.
+	file, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	_, _ = file.WriteString(`{"StreamID":"product-2","Vers`)
+	_ = file.Close()
+
+	reopened, err := eventstore.OpenFileEventStore(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen event store: %v", err)
+	}
+	defer reopened.Close()
+	snapshots, _ = eventstore.NewFileSnapshotStore(snapshotDir)
+	repo = newRepository(t, reopened, snapshots, 2)
+
+	saved, err := repo.GetByID("1")
+	if err != nil || saved.Price != 150.0 {
+		t.Errorf("Expected product 1 with price 150, got %+v (%v)", saved, err)
+	}
+	products, _ := repo.GetAll()
+	if len(products) != 2 {
+		t.Errorf("Expected 2 products after replay, got %d", len(products))
+	}
+
+	if snapshot, _ := snapshots.LoadSnapshot("product-1"); snapshot == nil || snapshot.Version != 2 {
+		t.Errorf("Expected a persisted snapshot at version 2, got %+v", snapshot)
+	}
+
+	// Novas gravações continuam a numeração a partir do ponto válido do arquivo.
+	if err := repo.Delete("2", models.Mutation{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var last eventstore.StoredEvent
+	_ = reopened.LoadAll(0, func(event eventstore.StoredEvent) error {
+		last = event
+		return nil
+	})
+	if last.Position != 4 || last.Version != 2 || last.Event.Type != models.EventProductDeleted {
+		t.Errorf("Expected deletion at position 4 and version 2, got %+v", last)
+	}
+}
+
+// faultyFile simula falhas de gravação sobre um arquivo real. Uma escrita com falha grava apenas
+// metade dos dados, como uma gravação interrompida.
+type faultyFile struct {
+	*os.File
+	failWrite    bool
+	failSync     bool
+	failTruncate bool
+}
+
+var errDiskFailure = errors.New("simulated disk failure")
+
+func (f *faultyFile) Write(data []byte) (int, error) {
+	if f.failWrite {
+		n, _ := f.File.Write(data[:len(data)/2])
+		return n, errDiskFailure
+	}
+	return f.File.Write(data)
+}
+
+func (f *faultyFile) Sync() error {
+	if f.failSync {
+		return errDiskFailure
+	}
+	return f.File.Sync()
+}
+
+func (f *faultyFile) Truncate(size int64) error {
+	if f.failTruncate {
+		return errDiskFailure
+	}
+	return f.File.Truncate(size)
+}
+
+func openFaultyStore(t *testing.T, path string) (*eventstore.FileEventStore, *faultyFile) {
+	t.Helper()
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		t.Fatalf("Failed to open event file: %v", err)
+	}
+	faulty := &faultyFile{File: file}
+	store, err := eventstore.NewFileEventStore(faulty)
+	if err != nil {
+		t.Fatalf("Failed to open event store: %v", err)
+	}
+	return store, faulty
+}
+
+func createdEvent(id string) []models.ProductEvent {
+	return []models.ProductEvent{{Type: models.EventProductCreated, ProductID: id}}
+}
+
+func TestFileEventStore_FailedWrites(t *testing.T) {
+	t.Run("Rolls Back Partial Writes", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "events.ndjson")
+		store, faulty := openFaultyStore(t, path)
+		if _, err := store.Append("product-1", 0, createdEvent("1"), models.Mutation{}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		faulty.failWrite = true
+		if _, err := store.Append("product-2", 0, createdEvent("2"), models.Mutation{}); !errors.Is(err, errDiskFailure) {
+			t.Fatalf("Expected the write failure, got %v", err)
+		}
+		faulty.failWrite = false
+		faulty.failSync = true
+		if _, err := store.Append("product-2", 0, createdEvent("2"), models.Mutation{}); !errors.Is(err, errDiskFailure) {
+			t.Fatalf("Expected the sync failure, got %v", err)
+		}
+		faulty.failSync = false
+		if _, err := store.Append("product-3", 0, createdEvent("3"), models.Mutation{}); err != nil {
+			t.Fatalf("Expected appends to succeed after the rollback, got %v", err)
+		}
+		_ = store.Close()
+
+		reopened, err := eventstore.OpenFileEventStore(path)
+		if err != nil {
+			t.Fatalf("Expected the event log to reopen cleanly, got %v", err)
+		}
+		defer reopened.Close()
+		var streams []string
+		_ = reopened.LoadAll(0, func(event eventstore.StoredEvent) error {
+			streams = append(streams, event.StreamID)
+			return nil
+		})
+		if len(streams) != 2 || streams[0] != "product-1" || streams[1] != "product-3" {
+			t.Errorf("Expected only the confirmed events, got %v", streams)
+		}
+	})
+
+	t.Run("Refuses Appends When Rollback Fails", func(t *testing.T) {
+		store, faulty := openFaultyStore(t, filepath.Join(t.TempDir(), "events.ndjson"))
+		defer store.Close()
+
+		faulty.failWrite = true
+		faulty.failTruncate = true
+		if _, err := store.Append("product-1", 0, createdEvent("1"), models.Mutation{}); !errors.Is(err, errDiskFailure) {
+			t.Fatalf("Expected the write failure, got %v", err)
+		}
+		faulty.failWrite = false
+		faulty.failTruncate = false
+		if _, err := store.Append("product-1", 0, createdEvent("1"), models.Mutation{}); !errors.Is(err, eventstore.ErrEventLogUnavailable) {
+			t.Errorf("Expected ErrEventLogUnavailable, got %v", err)
+		}
+		if events, _ := store.Load("product-1", 0); len(events) != 0 {
+			t.Errorf("Expected no visible events, got %+v", events)
+		}
+	})
+}
@@ -0,0 +1,119 @@
+package eventstore
+
+import (
+	"sync"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+// MemoryEventStore é um EventStore em memória, útil para testes.
+type MemoryEventStore struct {
+	streams map[string][]StoredEvent
+	all     []StoredEvent
+	mu      sync.RWMutex
+}
+
+// NewMemoryEventStore cria um EventStore vazio em memória.
+func NewMemoryEventStore() *MemoryEventStore {
+	return &MemoryEventStore{
+		streams: make(map[string][]StoredEvent),
+	}
+}
+
+var _ EventStore = (*MemoryEventStore)(nil)
+
+// Append grava os eventos no stream, verificando a versão esperada.
+func (s *MemoryEventStore) Append(streamID string, expectedVersion int, events []models.ProductEvent, mutation models.Mutation) ([]StoredEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, err := nextEvents(streamID, expectedVersion, len(s.streams[streamID]), int64(len(s.all)), events, mutation)
+	if err != nil {
+		return nil, err
+	}
+	s.streams[streamID] = append(s.streams[streamID], stored...)
+	s.all = append(s.all, stored...)
+	return stored, nil
+}
+
+// Load retorna os eventos do stream posteriores a afterVersion.
+func (s *MemoryEventStore) Load(streamID string, afterVersion int) ([]StoredEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stream := s.streams[streamID]
+	if afterVersion >= len(stream) {
+		return nil, nil
+	}
+	return append([]StoredEvent(nil), stream[afterVersion:]...), nil
+}
+
+// LoadAll percorre os eventos de todos os streams em ordem global.
+func (s *MemoryEventStore) LoadAll(afterPosition int64, fn func(event StoredEvent) error) error {
+	s.mu.RLock()
+	events := append([]StoredEvent(nil), s.all[min(afterPosition, int64(len(s.all))):]...)
+	s.mu.RUnlock()
+
+	for _, event := range events {
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MemorySnapshotStore é um SnapshotStore em memória.
+type MemorySnapshotStore struct {
+	snapshots map[string]Snapshot
+	mu        sync.RWMutex
+}
+
+// NewMemorySnapshotStore cria um SnapshotStore vazio em memória.
+func NewMemorySnapshotStore() *MemorySnapshotStore {
+	return &MemorySnapshotStore{
+		snapshots: make(map[string]Snapshot),
+	}
+}
+
+var _ SnapshotStore = (*MemorySnapshotStore)(nil)
+
+// SaveSnapshot substitui o snapshot do stream.
+func (s *MemorySnapshotStore) SaveSnapshot(snapshot Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.snapshots[snapshot.StreamID] = snapshot
+	return nil
+}
+
+// LoadSnapshot retorna o snapshot do stream, se houver.
+func (s *MemorySnapshotStore) LoadSnapshot(streamID string) (*Snapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snapshot, ok := s.snapshots[streamID]
+	if !ok {
+		return nil, nil
+	}
+	return &snapshot, nil
+}
+
+// nextEvents numera os novos eventos de um stream após verificar a versão esperada.
+// As posições globais começam em lastPosition+1.
+func nextEvents(streamID string, expectedVersion, currentVersion int, lastPosition int64, events []models.ProductEvent, mutation models.Mutation) ([]StoredEvent, error) {
+	if expectedVersion != currentVersion {
+		return nil, ErrConcurrencyConflict
+	}
+
+	stored := make([]StoredEvent, len(events))
+	for i, event := range events {
+		stored[i] = StoredEvent{
+			StreamID: streamID,
+			Version:  currentVersion + i + 1,
+			Position: lastPosition + int64(i) + 1,
+			Event:    event,
+			Mutation: mutation,
+		}
+	}
+	return stored, nil
+}
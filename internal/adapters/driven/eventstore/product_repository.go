@@ -0,0 +1,263 @@
+package eventstore
+
+import (
+	"errors"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+// DefaultSnapshotInterval é o número de eventos entre dois snapshots de um mesmo stream.
+const DefaultSnapshotInterval = 50
+
+// EventSourcedProductRepository é um Adaptador de Saída que implementa ports.ProductRepository derivando
+// o estado dos produtos de um stream de eventos por produto, em vez de armazenar registros.
+// GetByID reconstrói o agregado a partir do último snapshot e dos eventos seguintes; GetAll lê de uma
+// projeção em memória que pode ser reconstruída a qualquer momento a partir dos eventos.
+type EventSourcedProductRepository struct {
+	store            EventStore
+	snapshots        SnapshotStore
+	snapshotInterval int
+
+	projection map[string]*models.Product
+	position   int64
+	mu         sync.RWMutex
+}
+
+// NewEventSourcedProductRepository cria o repositório e constrói a projeção a partir dos eventos existentes.
+// Um snapshotInterval menor ou igual a zero desativa os snapshots.
+func NewEventSourcedProductRepository(store EventStore, snapshots SnapshotStore, snapshotInterval int) (*EventSourcedProductRepository, error) {
+	r := &EventSourcedProductRepository{
+		store:            store,
+		snapshots:        snapshots,
+		snapshotInterval: snapshotInterval,
+	}
+	if err := r.RebuildProjections(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+var _ ports.ProductRepository = (*EventSourcedProductRepository)(nil)
+
+// productAggregate é o estado de um produto reconstruído a partir do seu stream.
+type productAggregate struct {
+	streamID string
+	version  int
+	product  *models.Product
+}
+
+// apply aplica um evento ao agregado. Os eventos carregam o estado completo do produto após a alteração.
+func (a *productAggregate) apply(event StoredEvent) {
+	a.version = event.Version
+	a.product = applyEvent(event.Event)
+}
+
+// applyEvent retorna o estado do produto após o evento, ou nil se o produto foi excluído.
+func applyEvent(event models.ProductEvent) *models.Product {
+	if event.Type == models.EventProductDeleted {
+		return nil
+	}
+	product := *event.Product
+	return &product
+}
+
+// streamID retorna o stream de eventos do produto informado.
+func streamID(productID string) string {
+	return "product-" + productID
+}
+
+// Add grava um evento de criação no stream do produto.
+func (r *EventSourcedProductRepository) Add(product *models.Product, mutation models.Mutation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	aggregate, err := r.load(product.ID)
+	if err != nil {
+		return err
+	}
+	if aggregate.product != nil {
+		return models.ErrProductAlreadyExists
+	}
+	return r.append(aggregate, models.NewProductEvent(models.EventProductCreated, product), mutation)
+}
+
+// GetByID reconstrói o produto a partir do seu stream de eventos.
+func (r *EventSourcedProductRepository) GetByID(id string) (*models.Product, error) {
+	aggregate, err := r.load(id)
+	if err != nil {
+		return nil, err
+	}
+	if aggregate.product == nil {
+		return nil, models.ErrProductNotFound
+	}
+	return aggregate.product, nil
+}
+
+// GetAll retorna todos os produtos existentes a partir da projeção, ordenados pela data de criação.
+func (r *EventSourcedProductRepository) GetAll() ([]*models.Product, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	allProducts := make([]*models.Product, 0, len(r.projection))
+	for _, p := range r.projection {
+		product := *p
+		allProducts = append(allProducts, &product)
+	}
+	sort.Slice(allProducts, func(i, j int) bool {
+		if allProducts[i].CreatedAt.Equal(allProducts[j].CreatedAt) {
+			return allProducts[i].ID < allProducts[j].ID
+		}
+		return allProducts[i].CreatedAt.Before(allProducts[j].CreatedAt)
+	})
+	return allProducts, nil
+}
+
+// Update grava um evento de atualização no stream do produto, desde que ele ainda esteja no estado esperado.
+func (r *EventSourcedProductRepository) Update(product, expected *models.Product, mutation models.Mutation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	aggregate, err := r.load(product.ID)
+	if err != nil {
+		return err
+	}
+	if aggregate.product == nil {
+		return models.ErrProductNotFound
+	}
+	// A versão do stream lida aqui é a esperada por append, então a comparação vale até a gravação.
+	if expected != nil && models.ProductModified(expected, aggregate.product) {
+		return models.ErrProductModified
+	}
+	// Assim como nos demais adaptadores, apenas nome e preço são alterados; a data de criação é preservada
+	// e a data de atualização é definida pelo repositório.
+	updated := *product
+	updated.CreatedAt = aggregate.product.CreatedAt
+	updated.UpdatedAt = time.Now()
+	return r.append(aggregate, models.NewProductEvent(models.EventProductUpdated, &updated), mutation)
+}
+
+// Delete grava um evento de exclusão no stream do produto.
+func (r *EventSourcedProductRepository) Delete(id string, mutation models.Mutation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	aggregate, err := r.load(id)
+	if err != nil {
+		return err
+	}
+	if aggregate.product == nil {
+		return models.ErrProductNotFound
+	}
+	return r.append(aggregate, models.NewProductEvent(models.EventProductDeleted, aggregate.product), mutation)
+}
+
+// RebuildProjections descarta a projeção usada por GetAll e a reconstrói reprocessando todos os eventos.
+// Deve ser chamado após mudanças nas regras de projeção para que o estado reflita o histórico completo.
+func (r *EventSourcedProductRepository) RebuildProjections() error {
+	projection := make(map[string]*models.Product)
+	var position int64
+	err := r.store.LoadAll(0, func(event StoredEvent) error {
+		project(projection, event.Event)
+		position = event.Position
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.projection = projection
+	r.position = position
+	// Eventos gravados durante a reconstrução são aplicados antes de liberar a projeção.
+	return r.catchUp()
+}
+
+// load reconstrói o agregado a partir do último snapshot e dos eventos posteriores a ele.
+func (r *EventSourcedProductRepository) load(productID string) (*productAggregate, error) {
+	aggregate := &productAggregate{streamID: streamID(productID)}
+
+	if r.snapshots != nil {
+		snapshot, err := r.snapshots.LoadSnapshot(aggregate.streamID)
+		if err != nil {
+			return nil, err
+		}
+		if snapshot != nil {
+			aggregate.version = snapshot.Version
+			if snapshot.Product != nil {
+				product := *snapshot.Product
+				aggregate.product = &product
+			}
+		}
+	}
+
+	events, err := r.store.Load(aggregate.streamID, aggregate.version)
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range events {
+		aggregate.apply(event)
+	}
+	return aggregate, nil
+}
+
+// append grava o evento, com a mutação que o originou, no stream do agregado, atualiza a projeção e,
+// quando necessário, gera um snapshot. Deve ser chamado com o lock de escrita adquirido.
+func (r *EventSourcedProductRepository) append(aggregate *productAggregate, event models.ProductEvent, mutation models.Mutation) error {
+	stored, err := r.store.Append(aggregate.streamID, aggregate.version, []models.ProductEvent{event}, mutation)
+	if errors.Is(err, ErrConcurrencyConflict) {
+		// Outro processo alterou o stream; a projeção é atualizada para refletir o estado atual.
+		if catchUpErr := r.catchUp(); catchUpErr != nil {
+			return catchUpErr
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, e := range stored {
+		aggregate.apply(e)
+	}
+	if err := r.catchUp(); err != nil {
+		return err
+	}
+
+	if r.snapshots != nil && r.snapshotInterval > 0 && aggregate.version%r.snapshotInterval == 0 {
+		snapshot := Snapshot{StreamID: aggregate.streamID, Version: aggregate.version}
+		if aggregate.product != nil {
+			product := *aggregate.product
+			snapshot.Product = &product
+		}
+		// O evento já foi gravado; uma falha no snapshot apenas torna as próximas leituras mais lentas.
+		if err := r.snapshots.SaveSnapshot(snapshot); err != nil {
+			log.Printf("Falha ao gravar snapshot do stream %s: %v", aggregate.streamID, err)
+		}
+	}
+	return nil
+}
+
+// catchUp aplica à projeção os eventos gravados após a última posição processada.
+// Deve ser chamado com o lock de escrita adquirido.
+func (r *EventSourcedProductRepository) catchUp() error {
+	return r.store.LoadAll(r.position, func(event StoredEvent) error {
+		project(r.projection, event.Event)
+		r.position = event.Position
+		return nil
+	})
+}
+
+// project aplica um evento à projeção de produtos existentes.
+func project(projection map[string]*models.Product, event models.ProductEvent) {
+	if product := applyEvent(event); product != nil {
+		projection[event.ProductID] = product
+	} else {
+		delete(projection, event.ProductID)
+	}
+}
@@ -0,0 +1,161 @@
+package eventstore_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/danielrios/product-service-go/internal/adapters/driven/eventstore"
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+func newRepository(t *testing.T, store eventstore.EventStore, snapshots eventstore.SnapshotStore, interval int) *eventstore.EventSourcedProductRepository {
+	t.Helper()
+	repo, err := eventstore.NewEventSourcedProductRepository(store, snapshots, interval)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	return repo
+}
+
+func TestEventSourcedProductRepository_Lifecycle(t *testing.T) {
+	store := eventstore.NewMemoryEventStore()
+	repo := newRepository(t, store, eventstore.NewMemorySnapshotStore(), eventstore.DefaultSnapshotInterval)
+	product, _ := models.NewProduct("1", "Test Product", 100.0)
+
+	t.Run("Add And Get", func(t *testing.T) {
+		if err := repo.Add(product, models.Mutation{}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := repo.Add(product, models.Mutation{}); !errors.Is(err, models.ErrProductAlreadyExists) {
+			t.Errorf("Expected ErrProductAlreadyExists, got %v", err)
+		}
+
+		saved, err := repo.GetByID("1")
+		if err != nil || saved.Name != "Test Product" || saved.Price != 100.0 {
+			t.Errorf("Expected the added product, got %+v (%v)", saved, err)
+		}
+	})
+
+	t.Run("Update Preserves CreatedAt", func(t *testing.T) {
+		updated := &models.Product{ID: "1", Name: "Updated Product", Price: 150.0}
+		if err := repo.Update(updated, nil, models.Mutation{}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		saved, _ := repo.GetByID("1")
+		if saved.Price != 150.0 || !saved.CreatedAt.Equal(product.CreatedAt) {
+			t.Errorf("Expected updated price with original CreatedAt, got %+v", saved)
+		}
+	})
+
+	t.Run("Delete And Recreate", func(t *testing.T) {
+		if err := repo.Delete("1", models.Mutation{}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if _, err := repo.GetByID("1"); !errors.Is(err, models.ErrProductNotFound) {
+			t.Errorf("Expected ErrProductNotFound, got %v", err)
+		}
+		if err := repo.Delete("1", models.Mutation{}); !errors.Is(err, models.ErrProductNotFound) {
+			t.Errorf("Expected ErrProductNotFound on second delete, got %v", err)
+		}
+
+		recreated, _ := models.NewProduct("1", "Recreated Product", 120.0)
+		if err := repo.Add(recreated, models.Mutation{}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		events, _ := store.Load("product-1", 0)
+		if len(events) != 4 || events[3].Version != 4 || events[3].Event.Type != models.EventProductCreated {
+			t.Errorf("Expected 4 events ending in a creation, got %+v", events)
+		}
+	})
+
+	t.Run("Update Stale Product", func(t *testing.T) {
+		stale := &models.Product{ID: "1", Name: "Test Product", Price: 1.0}
+		if err := repo.Update(&models.Product{ID: "1", Name: "Renamed", Price: 1.0}, stale, models.Mutation{}); !errors.Is(err, models.ErrProductModified) {
+			t.Errorf("Expected ErrProductModified, got %v", err)
+		}
+	})
+
+	t.Run("Update Missing Product", func(t *testing.T) {
+		missing := &models.Product{ID: "nonexistent", Name: "Missing", Price: 1.0}
+		if err := repo.Update(missing, nil, models.Mutation{}); !errors.Is(err, models.ErrProductNotFound) {
+			t.Errorf("Expected ErrProductNotFound, got %v", err)
+		}
+	})
+}
+
+func TestEventSourcedProductRepository_Snapshots(t *testing.T) {
+	snapshots := eventstore.NewMemorySnapshotStore()
+	repo := newRepository(t, eventstore.NewMemoryEventStore(), snapshots, 3)
+	product, _ := models.NewProduct("1", "Test Product", 100.0)
+	_ = repo.Add(product, models.Mutation{})
+	_ = repo.Update(&models.Product{ID: "1", Name: "Test Product", Price: 110.0}, nil, models.Mutation{})
+
+	if snapshot, _ := snapshots.LoadSnapshot("product-1"); snapshot != nil {
+		t.Fatalf("Expected no snapshot before the interval, got %+v", snapshot)
+	}
+
+	_ = repo.Update(&models.Product{ID: "1", Name: "Test Product", Price: 120.0}, nil, models.Mutation{})
+	_ = repo.Update(&models.Product{ID: "1", Name: "Test Product", Price: 130.0}, nil, models.Mutation{})
+
+	snapshot, err := snapshots.LoadSnapshot("product-1")
+	if err != nil || snapshot == nil {
+		t.Fatalf("Expected a snapshot, got %v (%v)", snapshot, err)
+	}
+	if snapshot.Version != 3 || snapshot.Product.Price != 120.0 {
+		t.Errorf("Expected snapshot at version 3 with price 120, got %+v", snapshot)
+	}
+
+	saved, _ := repo.GetByID("1")
+	if saved.Price != 130.0 {
+		t.Errorf("Expected events after the snapshot to be applied, got price %v", saved.Price)
+	}
+}
+
+func TestEventSourcedProductRepository_RebuildProjections(t *testing.T) {
+	store := eventstore.NewMemoryEventStore()
+	writer := newRepository(t, store, nil, 0)
+	first, _ := models.NewProduct("1", "First Product", 100.0)
+	second, _ := models.NewProduct("2", "Second Product", 200.0)
+	_ = writer.Add(first, models.Mutation{})
+	_ = writer.Add(second, models.Mutation{})
+	_ = writer.Delete("1", models.Mutation{})
+
+	// Um segundo repositório sobre o mesmo store reconstrói a projeção a partir dos eventos.
+	reader := newRepository(t, store, nil, 0)
+	third, _ := models.NewProduct("3", "Third Product", 300.0)
+	_ = writer.Add(third, models.Mutation{})
+
+	products, _ := reader.GetAll()
+	if len(products) != 1 || products[0].ID != "2" {
+		t.Fatalf("Expected only product 2 before rebuilding, got %+v", products)
+	}
+
+	if err := reader.RebuildProjections(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	products, _ = reader.GetAll()
+	if len(products) != 2 || products[0].ID != "2" || products[1].ID != "3" {
+		t.Errorf("Expected products 2 and 3 after rebuilding, got %+v", products)
+	}
+}
+
+func TestEventSourcedProductRepository_ConcurrentWriters(t *testing.T) {
+	store := eventstore.NewMemoryEventStore()
+	first := newRepository(t, store, nil, 0)
+	second := newRepository(t, store, nil, 0)
+	product, _ := models.NewProduct("1", "Test Product", 100.0)
+
+	if err := first.Add(product, models.Mutation{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// O segundo repositório lê o stream atualizado e detecta que o produto já existe.
+	if err := second.Add(product, models.Mutation{}); !errors.Is(err, models.ErrProductAlreadyExists) {
+		t.Errorf("Expected ErrProductAlreadyExists, got %v", err)
+	}
+
+	if _, err := store.Append("product-1", 0, []models.ProductEvent{models.NewProductEvent(models.EventProductCreated, product)}, models.Mutation{}); !errors.Is(err, eventstore.ErrConcurrencyConflict) {
+		t.Errorf("Expected ErrConcurrencyConflict for a stale version, got %v", err)
+	}
+}
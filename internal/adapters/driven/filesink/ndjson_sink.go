@@ -0,0 +1,50 @@
+package filesink
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+// NDJSONSink é um Adaptador de Saída que grava cada evento como uma linha JSON em um arquivo local.
+// É útil para desenvolvimento e testes do outbox, sem depender de um broker de mensagens.
+type NDJSONSink struct {
+	file *os.File
+	mu   sync.Mutex
+}
+
+// NewNDJSONSink abre (ou cria) o arquivo informado para gravação dos eventos em modo append.
+func NewNDJSONSink(path string) (*NDJSONSink, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &NDJSONSink{file: file}, nil
+}
+
+var _ ports.EventSink = (*NDJSONSink)(nil)
+
+// Publish grava o evento no arquivo e força sua persistência em disco.
+func (s *NDJSONSink) Publish(_ context.Context, event models.ProductEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return s.file.Sync()
+}
+
+// Close fecha o arquivo de eventos.
+func (s *NDJSONSink) Close() error {
+	return s.file.Close()
+}
@@ -0,0 +1,316 @@
+package jwtauth
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// maxJWKSBytes limita o tamanho do documento JWKS lido de um arquivo ou URL.
+const maxJWKSBytes = 1 << 20
+
+// KeySetConfig controla a releitura do JWKS, que acompanha a rotação das chaves do emissor.
+type KeySetConfig struct {
+	// RefreshInterval é a idade máxima das chaves em cache; depois dela, o JWKS é relido no próximo uso.
+	RefreshInterval time.Duration
+	// MinRefreshInterval é o intervalo mínimo entre releituras provocadas por um kid desconhecido, que
+	// impede que tokens forjados façam o serviço buscar o JWKS a cada requisição.
+	MinRefreshInterval time.Duration
+}
+
+// DefaultKeySetConfig retorna a configuração padrão: releitura a cada 5 minutos e, para kids
+// desconhecidos, no máximo a cada 30 segundos.
+func DefaultKeySetConfig() KeySetConfig {
+	return KeySetConfig{
+		RefreshInterval:    5 * time.Minute,
+		MinRefreshInterval: 30 * time.Second,
+	}
+}
+
+// KeySet mantém em cache as chaves de verificação de um JWKS (RFC 7517), lido de um arquivo local ou
+// de uma URL. As chaves são relidas periodicamente e quando chega um token com um kid desconhecido,
+// o que permite ao emissor publicar uma chave nova antes de usá-la e retirar a antiga depois.
+type KeySet struct {
+	source func(ctx context.Context) ([]byte, error)
+	config KeySetConfig
+
+	mu          sync.Mutex
+	keys        map[string]*verificationKey
+	loadedAt    time.Time
+	attemptedAt time.Time
+	refreshing  *refreshCall
+}
+
+// refreshCall é uma releitura do JWKS em andamento, à qual as chamadas simultâneas se juntam. done é
+// fechado ao fim da releitura, quando err já traz o seu resultado.
+type refreshCall struct {
+	done chan struct{}
+	err  error
+}
+
+// NewFileKeySet cria um KeySet que lê o JWKS do arquivo informado.
+func NewFileKeySet(path string, config KeySetConfig) *KeySet {
+	return &KeySet{
+		source: func(ctx context.Context) ([]byte, error) {
+			file, err := os.Open(path)
+			if err != nil {
+				return nil, err
+			}
+			defer file.Close()
+			return io.ReadAll(io.LimitReader(file, maxJWKSBytes))
+		},
+		config: config,
+	}
+}
+
+// NewURLKeySet cria um KeySet que busca o JWKS na URL informada, como o jwks_uri de um provedor OpenID.
+func NewURLKeySet(url string, client *http.Client, config KeySetConfig) *KeySet {
+	return &KeySet{
+		source: func(ctx context.Context) ([]byte, error) {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Set("Accept", "application/jwk-set+json, application/json")
+
+			resp, err := client.Do(req)
+			if err != nil {
+				return nil, err
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+			}
+			return io.ReadAll(io.LimitReader(resp.Body, maxJWKSBytes))
+		},
+		config: config,
+	}
+}
+
+// Refresh relê o JWKS e substitui as chaves em cache. Em caso de erro, as chaves anteriores são mantidas.
+// Chamadas simultâneas compartilham uma única releitura.
+func (s *KeySet) Refresh(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.refreshLocked(ctx)
+}
+
+// refreshLocked inicia uma releitura, ou se junta à que está em andamento, e aguarda o seu resultado. É
+// chamada com o mutex adquirido, que fica liberado durante a busca do JWKS, para que as verificações com as
+// chaves em cache não esperem pela rede, e é readquirido antes do retorno.
+func (s *KeySet) refreshLocked(ctx context.Context) error {
+	if call := s.refreshing; call != nil {
+		s.mu.Unlock()
+		defer s.mu.Lock()
+		select {
+		case <-call.done:
+			return call.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	call := &refreshCall{done: make(chan struct{})}
+	attemptedAt := time.Now()
+	s.refreshing, s.attemptedAt = call, attemptedAt
+	s.mu.Unlock()
+	keys, err := s.fetch(ctx)
+	s.mu.Lock()
+
+	if err == nil {
+		s.keys, s.loadedAt = keys, attemptedAt
+	}
+	s.refreshing = nil
+	call.err = err
+	close(call.done)
+	return err
+}
+
+// fetch lê e converte o JWKS da origem.
+func (s *KeySet) fetch(ctx context.Context) (map[string]*verificationKey, error) {
+	data, err := s.source(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading JWKS: %w", err)
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing JWKS: %w", err)
+	}
+	return keys, nil
+}
+
+// key retorna a chave que verifica um token com o kid e o algoritmo informados. Um token sem kid só é
+// aceito quando o JWKS tem uma única chave.
+func (s *KeySet) key(ctx context.Context, kid, alg string) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	key, found := s.lookupLocked(kid)
+	stale := now.Sub(s.loadedAt) >= s.config.RefreshInterval
+	// Um kid desconhecido também aguarda a releitura em andamento, que pode trazer a chave nova.
+	joinable := !found && s.refreshing != nil
+	if (stale || !found) && (joinable || now.Sub(s.attemptedAt) >= s.config.MinRefreshInterval) {
+		if err := s.refreshLocked(ctx); err != nil {
+			if s.keys == nil {
+				return nil, err
+			}
+			log.Printf("Erro ao atualizar o JWKS; usando as chaves em cache: %v", err)
+		}
+		key, found = s.lookupLocked(kid)
+	}
+
+	if !found {
+		return nil, fmt.Errorf("no key matches kid %q", kid)
+	}
+	if key.alg != "" && key.alg != alg {
+		return nil, fmt.Errorf("key %q is restricted to %s", kid, key.alg)
+	}
+	return key.key, nil
+}
+
+func (s *KeySet) lookupLocked(kid string) (*verificationKey, bool) {
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, true
+		}
+	}
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+// verificationKey é uma chave do JWKS já convertida: *rsa.PublicKey, *ecdsa.PublicKey ou []byte (chaves
+// simétricas). alg, se informado no JWKS, restringe o algoritmo dos tokens verificados com a chave.
+type verificationKey struct {
+	key any
+	alg string
+}
+
+// jsonWebKey traz os membros de uma JWK usados nas chaves RSA, EC e simétricas (RFC 7518, seção 6).
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	K   string `json:"k"`
+}
+
+// parseJWKS converte as chaves de assinatura do documento, indexadas pelo kid. Chaves de cifragem
+// (use "enc") e de tipos não suportados são ignoradas.
+func parseJWKS(data []byte) (map[string]*verificationKey, error) {
+	var document struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]*verificationKey, len(document.Keys))
+	for _, jwk := range document.Keys {
+		if jwk.Use == "enc" {
+			continue
+		}
+		var (
+			key any
+			err error
+		)
+		switch jwk.Kty {
+		case "RSA":
+			key, err = jwk.rsaPublicKey()
+		case "EC":
+			key, err = jwk.ecdsaPublicKey()
+		case "oct":
+			key, err = decodeSegment(jwk.K)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", jwk.Kid, err)
+		}
+		if _, duplicate := keys[jwk.Kid]; duplicate {
+			return nil, fmt.Errorf("duplicate kid %q", jwk.Kid)
+		}
+		keys[jwk.Kid] = &verificationKey{key: key, alg: jwk.Alg}
+	}
+	return keys, nil
+}
+
+func (jwk *jsonWebKey) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := decodeSegment(jwk.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := decodeSegment(jwk.E)
+	if err != nil {
+		return nil, err
+	}
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+		return nil, errors.New("invalid RSA exponent")
+	}
+	key := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}
+	if key.N.BitLen() < 2048 {
+		return nil, errors.New("RSA keys must have at least 2048 bits")
+	}
+	return key, nil
+}
+
+func (jwk *jsonWebKey) ecdsaPublicKey() (*ecdsa.PublicKey, error) {
+	var (
+		curve    elliptic.Curve
+		ecdhKind ecdh.Curve
+	)
+	switch jwk.Crv {
+	case "P-256":
+		curve, ecdhKind = elliptic.P256(), ecdh.P256()
+	case "P-384":
+		curve, ecdhKind = elliptic.P384(), ecdh.P384()
+	case "P-521":
+		curve, ecdhKind = elliptic.P521(), ecdh.P521()
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+	}
+	x, err := decodeSegment(jwk.X)
+	if err != nil {
+		return nil, err
+	}
+	y, err := decodeSegment(jwk.Y)
+	if err != nil {
+		return nil, err
+	}
+
+	// A conversão pelo crypto/ecdh confere que o ponto pertence à curva.
+	size := (curve.Params().BitSize + 7) / 8
+	if len(x) != size || len(y) != size {
+		return nil, errors.New("invalid EC coordinates")
+	}
+	if _, err := ecdhKind.NewPublicKey(append(append([]byte{4}, x...), y...)); err != nil {
+		return nil, err
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+}
+
+func decodeSegment(value string) ([]byte, error) {
+	if value == "" {
+		return nil, errors.New("missing key material")
+	}
+	return base64.RawURLEncoding.DecodeString(value)
+}
@@ -0,0 +1,106 @@
+package jwtauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+// VerifierConfig define as regras de validação dos tokens.
+type VerifierConfig struct {
+	// Issuer é o valor exigido na claim iss.
+	Issuer string
+	// Audience é o valor que a claim aud deve conter.
+	Audience string
+	// Algorithms são os algoritmos de assinatura aceitos; tokens com outros algoritmos, inclusive "none",
+	// são rejeitados.
+	Algorithms []string
+	// Leeway é a tolerância a diferenças de relógio na verificação de exp, nbf e iat.
+	Leeway time.Duration
+}
+
+// DefaultVerifierConfig retorna a configuração padrão, sem emissor nem audiência: RS256, ES256 e HS256,
+// com 30 segundos de tolerância.
+func DefaultVerifierConfig() VerifierConfig {
+	return VerifierConfig{
+		Algorithms: []string{"RS256", "ES256", "HS256"},
+		Leeway:     30 * time.Second,
+	}
+}
+
+// Verifier é um Adaptador de Saída que valida tokens JWT (RFC 7519) com as chaves de um KeySet.
+type Verifier struct {
+	keys   *KeySet
+	parser *jwt.Parser
+}
+
+// NewVerifier cria um Verifier. Os tokens devem ter exp e, quando configurados, o emissor e a audiência
+// informados.
+func NewVerifier(keys *KeySet, config VerifierConfig) *Verifier {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(config.Algorithms),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(config.Leeway),
+	}
+	if config.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(config.Issuer))
+	}
+	if config.Audience != "" {
+		opts = append(opts, jwt.WithAudience(config.Audience))
+	}
+	return &Verifier{keys: keys, parser: jwt.NewParser(opts...)}
+}
+
+var _ ports.CredentialVerifier = (*Verifier)(nil)
+
+// claims são as claims lidas dos tokens. Os escopos podem vir em scope, separados por espaços (RFC 8693),
+// ou em scp, como lista ou string. Os papéis vêm em roles (RFC 9068), também como lista ou string.
+type claims struct {
+	jwt.RegisteredClaims
+	Scope string    `json:"scope"`
+	Scp   scopeList `json:"scp"`
+	Roles scopeList `json:"roles"`
+}
+
+type scopeList []string
+
+func (l *scopeList) UnmarshalJSON(data []byte) error {
+	var scope string
+	if err := json.Unmarshal(data, &scope); err == nil {
+		*l = strings.Fields(scope)
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(l))
+}
+
+// Verify valida a assinatura e as claims do token e retorna o principal identificado pela claim sub.
+func (v *Verifier) Verify(ctx context.Context, token string) (*models.Principal, error) {
+	var claims claims
+	_, err := v.parser.ParseWithClaims(token, &claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return v.keys.key(ctx, kid, token.Method.Alg())
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", models.ErrInvalidCredentials, err)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: token has no subject", models.ErrInvalidCredentials)
+	}
+
+	return &models.Principal{
+		Subject:   claims.Subject,
+		Method:    models.AuthMethodJWT,
+		Issuer:    claims.Issuer,
+		Scopes:    append(strings.Fields(claims.Scope), claims.Scp...),
+		Roles:     claims.Roles,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}, nil
+}
@@ -0,0 +1,273 @@
+package jwtauth_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/danielrios/product-service-go/internal/adapters/driven/jwtauth"
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+func encode(value []byte) string {
+	return base64.RawURLEncoding.EncodeToString(value)
+}
+
+func rsaJWK(kid string, key *rsa.PrivateKey) map[string]string {
+	return map[string]string{"kty": "RSA", "kid": kid, "use": "sig", "n": encode(key.N.Bytes()), "e": encode(big.NewInt(int64(key.E)).Bytes())}
+}
+
+func ecJWK(kid string, key *ecdsa.PrivateKey) map[string]string {
+	return map[string]string{"kty": "EC", "kid": kid, "crv": "P-256", "x": encode(key.X.FillBytes(make([]byte, 32))), "y": encode(key.Y.FillBytes(make([]byte, 32)))}
+}
+
+func writeJWKS(t *testing.T, path string, keys ...map[string]string) {
+	t.Helper()
+	data, err := json.Marshal(map[string]any{"keys": keys})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key any, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":   "https://issuer.example",
+		"aud":   "product-service",
+		"sub":   "user-1",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"iat":   time.Now().Unix(),
+		"scope": "products:read products:write",
+		"roles": []string{"editor"},
+	}
+}
+
+func withClaim(name string, value any) jwt.MapClaims {
+	claims := validClaims()
+	if value == nil {
+		delete(claims, name)
+	} else {
+		claims[name] = value
+	}
+	return claims
+}
+
+func TestVerifier(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := []byte("0123456789abcdef0123456789abcdef")
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	restricted := rsaJWK("rsa-ps", rsaKey)
+	restricted["alg"] = "PS256"
+	writeJWKS(t, path, rsaJWK("rsa-1", rsaKey), ecJWK("ec-1", ecKey),
+		map[string]string{"kty": "oct", "kid": "hmac-1", "k": encode(secret)}, restricted)
+
+	keys := jwtauth.NewFileKeySet(path, jwtauth.DefaultKeySetConfig())
+	if err := keys.Refresh(context.Background()); err != nil {
+		t.Fatalf("Expected the JWKS to load, got %v", err)
+	}
+	config := jwtauth.DefaultVerifierConfig()
+	config.Issuer, config.Audience = "https://issuer.example", "product-service"
+	verifier := jwtauth.NewVerifier(keys, config)
+
+	t.Run("Valid Tokens", func(t *testing.T) {
+		for _, token := range []string{
+			sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, validClaims()),
+			sign(t, jwt.SigningMethodES256, "ec-1", ecKey, validClaims()),
+			sign(t, jwt.SigningMethodHS256, "hmac-1", secret, validClaims()),
+		} {
+			principal, err := verifier.Verify(context.Background(), token)
+			if err != nil {
+				t.Fatalf("Expected a valid token, got %v", err)
+			}
+			if principal.Subject != "user-1" || principal.Method != models.AuthMethodJWT || !principal.HasScope("products:write") ||
+				!slices.Equal(principal.Roles, []string{"editor"}) {
+				t.Errorf("Unexpected principal %+v", principal)
+			}
+		}
+	})
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"Expired", sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, withClaim("exp", time.Now().Add(-time.Hour).Unix()))},
+		{"Without Expiry", sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, withClaim("exp", nil))},
+		{"Other Issuer", sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, withClaim("iss", "https://evil.example"))},
+		{"Other Audience", sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, withClaim("aud", "other-service"))},
+		{"Without Subject", sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, withClaim("sub", nil))},
+		{"Unknown Key", sign(t, jwt.SigningMethodRS256, "rsa-2", rsaKey, validClaims())},
+		{"Missing Kid With Several Keys", sign(t, jwt.SigningMethodRS256, "", rsaKey, validClaims())},
+		{"Algorithm Not Allowed", sign(t, jwt.SigningMethodPS256, "rsa-ps", rsaKey, validClaims())},
+		{"Algorithm Restricted By The Key", sign(t, jwt.SigningMethodRS256, "rsa-ps", rsaKey, validClaims())},
+		{"Key Of Another Type", sign(t, jwt.SigningMethodHS256, "rsa-1", secret, validClaims())},
+		{"Unsigned", sign(t, jwt.SigningMethodNone, "rsa-1", jwt.UnsafeAllowNoneSignatureType, validClaims())},
+		{"Malformed", "not-a-token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := verifier.Verify(context.Background(), tt.token); !errors.Is(err, models.ErrInvalidCredentials) {
+				t.Errorf("Expected ErrInvalidCredentials, got %v", err)
+			}
+		})
+	}
+}
+
+func TestKeySetRotation(t *testing.T) {
+	oldKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		fetches atomic.Int32
+		jwks    atomic.Value
+	)
+	jwks.Store([]map[string]string{ecJWK("old", oldKey)})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": jwks.Load()})
+	}))
+	defer server.Close()
+
+	keys := jwtauth.NewURLKeySet(server.URL, server.Client(), jwtauth.KeySetConfig{RefreshInterval: time.Hour, MinRefreshInterval: 0})
+	verifier := jwtauth.NewVerifier(keys, jwtauth.DefaultVerifierConfig())
+
+	if _, err := verifier.Verify(context.Background(), sign(t, jwt.SigningMethodES256, "", oldKey, validClaims())); err != nil {
+		t.Fatalf("Expected the only key to verify a token without kid, got %v", err)
+	}
+	if _, err := verifier.Verify(context.Background(), sign(t, jwt.SigningMethodES256, "old", oldKey, validClaims())); err != nil {
+		t.Fatalf("Expected the cached key to verify the token, got %v", err)
+	}
+	if fetches.Load() != 1 {
+		t.Errorf("Expected the keys to be cached, got %d fetches", fetches.Load())
+	}
+
+	jwks.Store([]map[string]string{ecJWK("old", oldKey), ecJWK("new", newKey)})
+	if _, err := verifier.Verify(context.Background(), sign(t, jwt.SigningMethodES256, "new", newKey, validClaims())); err != nil {
+		t.Fatalf("Expected an unknown kid to refresh the keys, got %v", err)
+	}
+	if fetches.Load() != 2 {
+		t.Errorf("Expected a second fetch, got %d", fetches.Load())
+	}
+
+	t.Run("Refreshes Are Rate Limited", func(t *testing.T) {
+		keys := jwtauth.NewURLKeySet(server.URL, server.Client(), jwtauth.DefaultKeySetConfig())
+		verifier := jwtauth.NewVerifier(keys, jwtauth.DefaultVerifierConfig())
+		before := fetches.Load()
+		for range 3 {
+			if _, err := verifier.Verify(context.Background(), sign(t, jwt.SigningMethodES256, "forged", newKey, validClaims())); !errors.Is(err, models.ErrInvalidCredentials) {
+				t.Fatalf("Expected ErrInvalidCredentials, got %v", err)
+			}
+		}
+		if fetches.Load()-before != 1 {
+			t.Errorf("Expected a single fetch for unknown kids, got %d", fetches.Load()-before)
+		}
+	})
+}
+
+func TestKeySetConcurrentRefresh(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fetches atomic.Int32
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fetches.Add(1) > 1 {
+			started <- struct{}{}
+			<-release
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{ecJWK("current", key)}})
+	}))
+	defer server.Close()
+
+	keys := jwtauth.NewURLKeySet(server.URL, server.Client(), jwtauth.KeySetConfig{RefreshInterval: time.Hour, MinRefreshInterval: 0})
+	if err := keys.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	verifier := jwtauth.NewVerifier(keys, jwtauth.DefaultVerifierConfig())
+
+	var wg sync.WaitGroup
+	refresh := func() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := keys.Refresh(context.Background()); err != nil {
+				t.Errorf("Expected the refresh to succeed, got %v", err)
+			}
+		}()
+	}
+	refresh()
+	<-started
+
+	// Com a releitura bloqueada na rede, as chaves em cache continuam verificando tokens.
+	verified := make(chan error, 1)
+	go func() {
+		_, err := verifier.Verify(context.Background(), sign(t, jwt.SigningMethodES256, "current", key, validClaims()))
+		verified <- err
+	}()
+	select {
+	case err := <-verified:
+		if err != nil {
+			t.Errorf("Expected the cached key to verify the token, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Error("Expected the verification not to wait for the refresh")
+	}
+
+	for range 3 {
+		refresh()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if fetches.Load() != 2 {
+		t.Errorf("Expected concurrent refreshes to share a single fetch, got %d", fetches.Load()-1)
+	}
+}
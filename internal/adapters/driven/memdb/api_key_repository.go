@@ -0,0 +1,96 @@
+package memdb
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+// InMemoryAPIKeyRepository é um Adaptador de Saída que armazena as chaves de API em memória.
+type InMemoryAPIKeyRepository struct {
+	keys map[string]*models.APIKey
+	mu   sync.RWMutex
+}
+
+// NewInMemoryAPIKeyRepository cria uma nova instância do repositório de chaves de API em memória.
+func NewInMemoryAPIKeyRepository() *InMemoryAPIKeyRepository {
+	return &InMemoryAPIKeyRepository{
+		keys: make(map[string]*models.APIKey),
+	}
+}
+
+var _ ports.APIKeyRepository = (*InMemoryAPIKeyRepository)(nil)
+
+// AddAPIKey adiciona uma nova chave.
+func (r *InMemoryAPIKeyRepository) AddAPIKey(key *models.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.keys[key.ID] = copyAPIKey(key)
+	return nil
+}
+
+// GetAPIKey busca uma chave pelo ID.
+func (r *InMemoryAPIKeyRepository) GetAPIKey(id string) (*models.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key, ok := r.keys[id]
+	if !ok {
+		return nil, models.ErrAPIKeyNotFound
+	}
+	return copyAPIKey(key), nil
+}
+
+// ListAPIKeys retorna todas as chaves, ordenadas pela data de criação.
+func (r *InMemoryAPIKeyRepository) ListAPIKeys() ([]*models.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]*models.APIKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		keys = append(keys, copyAPIKey(key))
+	}
+	slices.SortFunc(keys, func(a, b *models.APIKey) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return keys, nil
+}
+
+// RevokeAPIKey marca a chave como revogada, se ainda não estiver.
+func (r *InMemoryAPIKeyRepository) RevokeAPIKey(id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, ok := r.keys[id]
+	if !ok {
+		return models.ErrAPIKeyNotFound
+	}
+	if key.RevokedAt == nil {
+		key.RevokedAt = &at
+	}
+	return nil
+}
+
+// TouchAPIKey registra o último uso da chave.
+func (r *InMemoryAPIKeyRepository) TouchAPIKey(id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, ok := r.keys[id]
+	if !ok {
+		return models.ErrAPIKeyNotFound
+	}
+	key.LastUsedAt = &at
+	return nil
+}
+
+func copyAPIKey(key *models.APIKey) *models.APIKey {
+	copied := *key
+	copied.Scopes = slices.Clone(key.Scopes)
+	copied.Roles = slices.Clone(key.Roles)
+	return &copied
+}
@@ -0,0 +1,52 @@
+package memdb_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/adapters/driven/memdb"
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+func TestInMemoryAPIKeyRepository(t *testing.T) {
+	repo := memdb.NewInMemoryAPIKeyRepository()
+	key, _, err := models.NewAPIKey("batch", []string{models.ScopeProductsRead}, []string{models.RoleViewer}, nil, "admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.AddAPIKey(key); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Returns Copies", func(t *testing.T) {
+		stored, _ := repo.GetAPIKey(key.ID)
+		stored.Scopes[0] = models.ScopeAdmin
+		if again, _ := repo.GetAPIKey(key.ID); again.Scopes[0] != models.ScopeProductsRead {
+			t.Errorf("Expected the stored scopes to be unchanged, got %v", again.Scopes)
+		}
+	})
+
+	t.Run("Revoke Keeps The First Date", func(t *testing.T) {
+		first := time.Now()
+		_ = repo.RevokeAPIKey(key.ID, first)
+		_ = repo.RevokeAPIKey(key.ID, first.Add(time.Hour))
+
+		stored, _ := repo.GetAPIKey(key.ID)
+		if stored.RevokedAt == nil || !stored.RevokedAt.Equal(first) {
+			t.Errorf("Expected the key revoked at %v, got %v", first, stored.RevokedAt)
+		}
+		if keys, _ := repo.ListAPIKeys(); len(keys) != 1 {
+			t.Errorf("Expected revoked keys to stay listed, got %d keys", len(keys))
+		}
+	})
+
+	t.Run("Unknown Keys", func(t *testing.T) {
+		if _, err := repo.GetAPIKey("missing"); !errors.Is(err, models.ErrAPIKeyNotFound) {
+			t.Errorf("Expected ErrAPIKeyNotFound, got %v", err)
+		}
+		if err := repo.TouchAPIKey("missing", time.Now()); !errors.Is(err, models.ErrAPIKeyNotFound) {
+			t.Errorf("Expected ErrAPIKeyNotFound, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,23 @@
+package memdb
+
+import (
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+var _ ports.AuditRepository = (*InMemoryProductRepository)(nil)
+
+// ListAuditEntries percorre o log do fim para o início, retornando os registros mais recentes primeiro.
+func (r *InMemoryProductRepository) ListAuditEntries(filter models.AuditFilter) ([]*models.AuditEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := make([]*models.AuditEntry, 0)
+	for i := len(r.audit) - 1; i >= 0 && len(entries) < filter.Limit; i-- {
+		if filter.Matches(r.audit[i]) {
+			found := *r.audit[i]
+			entries = append(entries, &found)
+		}
+	}
+	return entries, nil
+}
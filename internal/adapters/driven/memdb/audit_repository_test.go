@@ -0,0 +1,73 @@
+package memdb_test
+
+import (
+	"testing"
+
+	"github.com/danielrios/product-service-go/internal/adapters/driven/memdb"
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+func TestInMemoryProductRepository_ListAuditEntries(t *testing.T) {
+	t.Run("Records Each Mutation With The Stored Previous State", func(t *testing.T) {
+		repo := memdb.NewInMemoryProductRepository()
+		mutation := models.Mutation{Actor: "alice", RequestID: "req-1"}
+		product, _ := models.NewProduct("1", "Test Product", 100.0)
+		_ = repo.Add(product, mutation)
+		_ = repo.Update(&models.Product{ID: "1", Name: "Test Product", Price: 150.0}, nil, mutation)
+		_ = repo.Delete("1", mutation)
+
+		entries, err := repo.ListAuditEntries(models.AuditFilter{ProductID: "1", Limit: 10})
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		expected := []models.AuditOperation{models.AuditOperationDelete, models.AuditOperationUpdate, models.AuditOperationCreate}
+		if len(entries) != len(expected) {
+			t.Fatalf("Expected %d entries, got %d", len(expected), len(entries))
+		}
+		for i, operation := range expected {
+			if entries[i].Operation != operation || entries[i].Actor != "alice" || entries[i].RequestID != "req-1" {
+				t.Errorf("Unexpected entry %d: %+v", i, entries[i])
+			}
+		}
+		update := entries[1]
+		if update.Before.Price != 100.0 || update.After.Price != 150.0 || !update.After.CreatedAt.Equal(product.CreatedAt) {
+			t.Errorf("Expected the update to go from the stored state, got before=%+v after=%+v", update.Before, update.After)
+		}
+		if entries[0].Before.Price != 150.0 || entries[0].After != nil {
+			t.Errorf("Expected the deletion to record the last state, got %+v", entries[0])
+		}
+	})
+
+	t.Run("Failed Mutations Are Not Recorded", func(t *testing.T) {
+		repo := memdb.NewInMemoryProductRepository()
+		product, _ := models.NewProduct("1", "Test Product", 100.0)
+		_ = repo.Add(product, models.Mutation{})
+		_ = repo.Add(product, models.Mutation{})
+		_ = repo.Update(&models.Product{ID: "nonexistent", Name: "Missing", Price: 1}, nil, models.Mutation{})
+		_ = repo.Delete("nonexistent", models.Mutation{})
+
+		entries, _ := repo.ListAuditEntries(models.AuditFilter{Limit: 10})
+
+		if len(entries) != 1 {
+			t.Errorf("Expected only the creation to be recorded, got %d entries", len(entries))
+		}
+	})
+
+	t.Run("Imports Are Recorded", func(t *testing.T) {
+		repo := memdb.NewInMemoryProductRepository()
+		existing, _ := models.NewProduct("1", "Existing", 10)
+		_ = repo.Add(existing, models.Mutation{})
+
+		_, _ = repo.ImportProducts([]*models.Product{{ID: "1", Name: "Renamed", Price: 12}, {ID: "2", Name: "New", Price: 5}}, nil,
+			models.ImportModeUpsert, models.Mutation{Actor: "importer"})
+
+		entries, _ := repo.ListAuditEntries(models.AuditFilter{Actor: "importer", Limit: 10})
+		if len(entries) != 2 || entries[0].Operation != models.AuditOperationCreate || entries[1].Operation != models.AuditOperationUpdate {
+			t.Fatalf("Expected the import to record a creation and an update, got %+v", entries)
+		}
+		if entries[1].Before.Name != "Existing" {
+			t.Errorf("Expected the update to record the previous state, got %+v", entries[1].Before)
+		}
+	})
+}
@@ -0,0 +1,33 @@
+package memdb
+
+import (
+	"sort"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+var _ ports.ChangeFeedRepository = (*InMemoryProductRepository)(nil)
+
+// recordChange acrescenta o evento ao feed de alterações. Deve ser chamado com o mutex de escrita adquirido.
+func (r *InMemoryProductRepository) recordChange(event models.ProductEvent) {
+	sequence := int64(len(r.changes)) + 1
+	r.changes = append(r.changes, models.NewProductChange(sequence, event))
+}
+
+// ChangesSince retorna as alterações com sequência maior que since.
+func (r *InMemoryProductRepository) ChangesSince(since int64, limit int) ([]*models.ProductChange, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// As alterações estão em ordem crescente de sequência, o que permite a busca binária.
+	start := sort.Search(len(r.changes), func(i int) bool { return r.changes[i].Sequence > since })
+	end := min(start+limit, len(r.changes))
+
+	changes := make([]*models.ProductChange, 0, end-start)
+	for _, change := range r.changes[start:end] {
+		found := *change
+		changes = append(changes, &found)
+	}
+	return changes, nil
+}
@@ -0,0 +1,68 @@
+package memdb_test
+
+import (
+	"testing"
+
+	"github.com/danielrios/product-service-go/internal/adapters/driven/memdb"
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+func TestInMemoryProductRepository_ChangesSince(t *testing.T) {
+	t.Run("Returns Ordered Changes With Tombstones", func(t *testing.T) {
+		repo := memdb.NewInMemoryProductRepository()
+		product, _ := models.NewProduct("1", "Test Product", 100.0)
+		_ = repo.Add(product, models.Mutation{})
+		updated, _ := models.NewProduct("1", "Updated Product", 150.0)
+		_ = repo.Update(updated, nil, models.Mutation{})
+		_ = repo.Delete("1", models.Mutation{})
+
+		changes, err := repo.ChangesSince(0, 10)
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(changes) != 3 {
+			t.Fatalf("Expected 3 changes, got %d", len(changes))
+		}
+		for i, change := range changes {
+			if change.Sequence != int64(i+1) {
+				t.Errorf("Expected sequence %d, got %d", i+1, change.Sequence)
+			}
+		}
+		if changes[1].Product == nil || changes[1].Product.Name != "Updated Product" {
+			t.Errorf("Expected update to carry the new state, got %+v", changes[1].Product)
+		}
+		tombstone := changes[2]
+		if !tombstone.Deleted || tombstone.Product != nil || tombstone.ProductID != "1" {
+			t.Errorf("Expected a tombstone for product 1, got %+v", tombstone)
+		}
+	})
+
+	t.Run("Resumes From Checkpoint", func(t *testing.T) {
+		repo := memdb.NewInMemoryProductRepository()
+		for _, id := range []string{"1", "2", "3", "4"} {
+			product, _ := models.NewProduct(id, "Product "+id, 10)
+			_ = repo.Add(product, models.Mutation{})
+		}
+
+		changes, _ := repo.ChangesSince(2, 1)
+
+		if len(changes) != 1 || changes[0].Sequence != 3 || changes[0].ProductID != "3" {
+			t.Errorf("Expected only change 3, got %+v", changes)
+		}
+	})
+
+	t.Run("Failed Mutations Do Not Consume Sequences", func(t *testing.T) {
+		repo := memdb.NewInMemoryProductRepository()
+		product, _ := models.NewProduct("1", "Test Product", 100.0)
+		_ = repo.Add(product, models.Mutation{})
+		_ = repo.Add(product, models.Mutation{})
+		_ = repo.Delete("nonexistent", models.Mutation{})
+
+		changes, _ := repo.ChangesSince(0, 10)
+
+		if len(changes) != 1 {
+			t.Errorf("Expected 1 change, got %d", len(changes))
+		}
+	})
+}
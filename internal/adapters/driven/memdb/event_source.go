@@ -0,0 +1,41 @@
+package memdb
+
+import (
+	"context"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+var _ ports.ProductEventSource = (*InMemoryProductRepository)(nil)
+
+// Listen registra handle para receber os eventos do repositório em memória até o cancelamento do contexto.
+// Os eventos são entregues de forma síncrona, com o mutex do repositório adquirido; por isso handle
+// não pode chamar métodos do repositório.
+func (r *InMemoryProductRepository) Listen(ctx context.Context, handle func(event models.ProductEvent)) error {
+	r.listenersMu.Lock()
+	if r.listeners == nil {
+		r.listeners = make(map[int]func(models.ProductEvent))
+	}
+	r.nextListener++
+	id := r.nextListener
+	r.listeners[id] = handle
+	r.listenersMu.Unlock()
+
+	<-ctx.Done()
+
+	r.listenersMu.Lock()
+	delete(r.listeners, id)
+	r.listenersMu.Unlock()
+	return nil
+}
+
+// notifyListeners repassa o evento aos ouvintes registrados.
+func (r *InMemoryProductRepository) notifyListeners(event models.ProductEvent) {
+	r.listenersMu.RLock()
+	defer r.listenersMu.RUnlock()
+
+	for _, handle := range r.listeners {
+		handle(event)
+	}
+}
@@ -0,0 +1,56 @@
+package memdb
+
+import (
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+var _ ports.ProductHistoryRepository = (*InMemoryProductRepository)(nil)
+
+// recordRevision acrescenta uma revisão ao histórico do produto. Deve ser chamado com o mutex de escrita adquirido.
+func (r *InMemoryProductRepository) recordRevision(event models.ProductEvent) {
+	if r.revisions == nil {
+		r.revisions = make(map[string][]*models.ProductRevision)
+	}
+	history := r.revisions[event.ProductID]
+	r.revisions[event.ProductID] = append(history, models.NewProductRevision(len(history)+1, event))
+}
+
+// ListRevisions retorna as revisões do produto em ordem crescente.
+func (r *InMemoryProductRepository) ListRevisions(productID string) ([]*models.ProductRevision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	history, ok := r.revisions[productID]
+	if !ok {
+		return nil, models.ErrProductNotFound
+	}
+
+	revisions := make([]*models.ProductRevision, 0, len(history))
+	for _, revision := range history {
+		found := *revision
+		revisions = append(revisions, &found)
+	}
+	return revisions, nil
+}
+
+// GetAsOf retorna o estado do produto na última revisão iniciada até o instante informado.
+func (r *InMemoryProductRepository) GetAsOf(productID string, at time.Time) (*models.Product, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	history := r.revisions[productID]
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].ValidFrom.After(at) {
+			continue
+		}
+		if history[i].Deleted {
+			return nil, models.ErrProductNotFound
+		}
+		product := *history[i].Product
+		return &product, nil
+	}
+	return nil, models.ErrProductNotFound
+}
@@ -0,0 +1,79 @@
+package memdb_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/adapters/driven/memdb"
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+func TestInMemoryProductRepository_ListRevisions(t *testing.T) {
+	t.Run("Numbering Continues After Recreation", func(t *testing.T) {
+		repo := memdb.NewInMemoryProductRepository()
+		product, _ := models.NewProduct("1", "Test Product", 100.0)
+		_ = repo.Add(product, models.Mutation{})
+		_ = repo.Delete("1", models.Mutation{})
+		recreated, _ := models.NewProduct("1", "Recreated Product", 120.0)
+		_ = repo.Add(recreated, models.Mutation{})
+
+		revisions, err := repo.ListRevisions("1")
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(revisions) != 3 {
+			t.Fatalf("Expected 3 revisions, got %d", len(revisions))
+		}
+		if !revisions[1].Deleted || revisions[1].Product != nil {
+			t.Errorf("Expected revision 2 to mark the deletion, got %+v", revisions[1])
+		}
+		if revisions[2].Revision != 3 || revisions[2].Product.Name != "Recreated Product" {
+			t.Errorf("Expected revision 3 to hold the recreated product, got %+v", revisions[2])
+		}
+	})
+
+	t.Run("Product Never Existed", func(t *testing.T) {
+		repo := memdb.NewInMemoryProductRepository()
+
+		_, err := repo.ListRevisions("nonexistent")
+
+		if !errors.Is(err, models.ErrProductNotFound) {
+			t.Errorf("Expected ErrProductNotFound, got %v", err)
+		}
+	})
+}
+
+func TestInMemoryProductRepository_GetAsOf(t *testing.T) {
+	repo := memdb.NewInMemoryProductRepository()
+	beforeCreation := time.Now()
+	product, _ := models.NewProduct("1", "Test Product", 100.0)
+	_ = repo.Add(product, models.Mutation{})
+	afterCreation := time.Now()
+	updated, _ := models.NewProduct("1", "Test Product", 150.0)
+	_ = repo.Update(updated, nil, models.Mutation{})
+	afterUpdate := time.Now()
+	_ = repo.Delete("1", models.Mutation{})
+	afterDeletion := time.Now()
+
+	t.Run("Returns State At Each Instant", func(t *testing.T) {
+		atCreation, err := repo.GetAsOf("1", afterCreation)
+		if err != nil || atCreation.Price != 100.0 {
+			t.Errorf("Expected original price 100, got %+v (%v)", atCreation, err)
+		}
+
+		atUpdate, err := repo.GetAsOf("1", afterUpdate)
+		if err != nil || atUpdate.Price != 150.0 {
+			t.Errorf("Expected updated price 150, got %+v (%v)", atUpdate, err)
+		}
+	})
+
+	t.Run("Not Found Before Creation Or After Deletion", func(t *testing.T) {
+		for _, at := range []time.Time{beforeCreation, afterDeletion} {
+			if _, err := repo.GetAsOf("1", at); !errors.Is(err, models.ErrProductNotFound) {
+				t.Errorf("Expected ErrProductNotFound at %s, got %v", at, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,79 @@
+package memdb
+
+import (
+	"sync"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+// InMemoryIdempotencyRepository é um Adaptador de Saída que armazena as chaves de idempotência em memória.
+type InMemoryIdempotencyRepository struct {
+	records map[string]*models.IdempotencyRecord
+	mu      sync.Mutex
+}
+
+// NewInMemoryIdempotencyRepository cria uma nova instância do repositório de chaves de idempotência em memória.
+func NewInMemoryIdempotencyRepository() *InMemoryIdempotencyRepository {
+	return &InMemoryIdempotencyRepository{
+		records: make(map[string]*models.IdempotencyRecord),
+	}
+}
+
+var _ ports.IdempotencyRepository = (*InMemoryIdempotencyRepository)(nil)
+
+// Reserve grava o registro, a menos que exista um registro válido para a mesma chave.
+func (r *InMemoryIdempotencyRepository) Reserve(record *models.IdempotencyRecord) (*models.IdempotencyRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.records[record.Key]; ok && !existing.IsExpired(record.CreatedAt) {
+		return copyIdempotencyRecord(existing), nil
+	}
+	r.records[record.Key] = copyIdempotencyRecord(record)
+	return nil, nil
+}
+
+// Complete grava a resposta da chave reservada.
+func (r *InMemoryIdempotencyRepository) Complete(record *models.IdempotencyRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.records[record.Key]; !ok {
+		return models.ErrIdempotencyKeyNotFound
+	}
+	r.records[record.Key] = copyIdempotencyRecord(record)
+	return nil
+}
+
+// Release remove a chave.
+func (r *InMemoryIdempotencyRepository) Release(key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.records, key)
+	return nil
+}
+
+// DeleteExpired remove os registros expirados.
+func (r *InMemoryIdempotencyRepository) DeleteExpired(now time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deleted := 0
+	for key, record := range r.records {
+		if record.IsExpired(now) {
+			delete(r.records, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func copyIdempotencyRecord(record *models.IdempotencyRecord) *models.IdempotencyRecord {
+	copied := *record
+	copied.ResponseHeaders = record.ResponseHeaders.Clone()
+	copied.ResponseBody = append([]byte(nil), record.ResponseBody...)
+	return &copied
+}
@@ -0,0 +1,49 @@
+package memdb_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/adapters/driven/memdb"
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+func TestInMemoryIdempotencyRepository(t *testing.T) {
+	now := time.Now()
+	newRecord := func(key string, expiresAt time.Time) *models.IdempotencyRecord {
+		return &models.IdempotencyRecord{Key: key, Fingerprint: "fp", Status: models.IdempotencyInProgress, CreatedAt: now, ExpiresAt: expiresAt}
+	}
+
+	t.Run("Reserve Returns Existing Record", func(t *testing.T) {
+		repo := memdb.NewInMemoryIdempotencyRepository()
+
+		if existing, err := repo.Reserve(newRecord("k", now.Add(time.Minute))); err != nil || existing != nil {
+			t.Fatalf("Expected the key to be reserved, got %+v (%v)", existing, err)
+		}
+		existing, err := repo.Reserve(newRecord("k", now.Add(time.Minute)))
+		if err != nil || existing == nil || existing.Status != models.IdempotencyInProgress {
+			t.Errorf("Expected the in-progress record, got %+v (%v)", existing, err)
+		}
+	})
+
+	t.Run("Complete Requires A Reserved Key", func(t *testing.T) {
+		repo := memdb.NewInMemoryIdempotencyRepository()
+
+		if err := repo.Complete(newRecord("missing", now)); !errors.Is(err, models.ErrIdempotencyKeyNotFound) {
+			t.Errorf("Expected ErrIdempotencyKeyNotFound, got %v", err)
+		}
+	})
+
+	t.Run("DeleteExpired", func(t *testing.T) {
+		repo := memdb.NewInMemoryIdempotencyRepository()
+		_, _ = repo.Reserve(newRecord("expired", now.Add(-time.Second)))
+		_, _ = repo.Reserve(newRecord("valid", now.Add(time.Minute)))
+
+		deleted, err := repo.DeleteExpired(now)
+
+		if err != nil || deleted != 1 {
+			t.Errorf("Expected 1 deleted record, got %d (%v)", deleted, err)
+		}
+	})
+}
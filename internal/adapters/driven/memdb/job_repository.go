@@ -0,0 +1,151 @@
+package memdb
+
+import (
+	"sync"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+// InMemoryJobRepository é um Adaptador de Saída que mantém a fila de jobs em memória.
+// Os jobs não sobrevivem ao reinício do processo.
+type InMemoryJobRepository struct {
+	jobs  map[string]*models.Job
+	order []string // IDs na ordem de submissão.
+	mu    sync.Mutex
+}
+
+// NewInMemoryJobRepository cria uma nova instância do repositório de jobs em memória.
+func NewInMemoryJobRepository() *InMemoryJobRepository {
+	return &InMemoryJobRepository{
+		jobs: make(map[string]*models.Job),
+	}
+}
+
+var _ ports.JobRepository = (*InMemoryJobRepository)(nil)
+
+// AddJob adiciona um job à fila.
+func (r *InMemoryJobRepository) AddJob(job *models.Job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.jobs[job.ID] = copyJob(job)
+	r.order = append(r.order, job.ID)
+	return nil
+}
+
+// GetJob busca um job pelo ID.
+func (r *InMemoryJobRepository) GetJob(id string) (*models.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil, models.ErrJobNotFound
+	}
+	return copyJob(job), nil
+}
+
+// ClaimNextJob reserva o job mais antigo que esteja na fila ou com o lease expirado.
+func (r *InMemoryJobRepository) ClaimNextJob(lease time.Duration) (*models.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, id := range r.order {
+		job := r.jobs[id]
+		abandoned := job.Status == models.JobRunning && job.LeaseExpiresAt != nil && !now.Before(*job.LeaseExpiresAt)
+		if job.Status != models.JobQueued && !abandoned {
+			continue
+		}
+
+		expiresAt := now.Add(lease)
+		job.Status = models.JobRunning
+		job.Attempts++
+		job.LeaseExpiresAt = &expiresAt
+		if job.StartedAt == nil {
+			job.StartedAt = &now
+		}
+		return copyJob(job), nil
+	}
+	return nil, nil
+}
+
+// HeartbeatJob renova o lease e grava o progresso do job em execução.
+func (r *InMemoryJobRepository) HeartbeatJob(id string, progress models.JobProgress, lease time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job, ok := r.jobs[id]
+	if !ok {
+		return false, models.ErrJobNotFound
+	}
+	if job.Status == models.JobRunning {
+		expiresAt := time.Now().Add(lease)
+		job.LeaseExpiresAt = &expiresAt
+		job.Progress = progress
+	}
+	return job.CancelRequested, nil
+}
+
+// UpdateJob substitui o job armazenado.
+func (r *InMemoryJobRepository) UpdateJob(job *models.Job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.jobs[job.ID]; !ok {
+		return models.ErrJobNotFound
+	}
+	r.jobs[job.ID] = copyJob(job)
+	return nil
+}
+
+// RequestJobCancellation cancela um job na fila ou marca um job em execução para cancelamento.
+func (r *InMemoryJobRepository) RequestJobCancellation(id string) (*models.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil, models.ErrJobNotFound
+	}
+	switch {
+	case job.Status.IsFinal():
+		return nil, models.ErrJobAlreadyFinished
+	case job.Status == models.JobQueued:
+		now := time.Now()
+		job.Status = models.JobCancelled
+		job.FinishedAt = &now
+	default:
+		job.CancelRequested = true
+	}
+	return copyJob(job), nil
+}
+
+// DeleteFinishedJobs remove os jobs finalizados antes do instante informado.
+func (r *InMemoryJobRepository) DeleteFinishedJobs(before time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.order[:0]
+	deleted := 0
+	for _, id := range r.order {
+		job := r.jobs[id]
+		if job.Status.IsFinal() && job.FinishedAt != nil && job.FinishedAt.Before(before) {
+			delete(r.jobs, id)
+			deleted++
+			continue
+		}
+		kept = append(kept, id)
+	}
+	r.order = kept
+	return deleted, nil
+}
+
+// copyJob evita que o chamador altere o job armazenado. Os bytes e as datas referenciados nunca são
+// alterados no lugar, apenas substituídos, e por isso podem ser compartilhados.
+func copyJob(job *models.Job) *models.Job {
+	copied := *job
+	return &copied
+}
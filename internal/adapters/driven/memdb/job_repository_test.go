@@ -0,0 +1,90 @@
+package memdb_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/adapters/driven/memdb"
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+func TestInMemoryJobRepository(t *testing.T) {
+	t.Run("Claims In Submission Order", func(t *testing.T) {
+		repo := memdb.NewInMemoryJobRepository()
+		first := models.NewJob("test", nil, nil, "alice", "req-1")
+		second := models.NewJob("test", nil, nil, "alice", "req-2")
+		_ = repo.AddJob(first)
+		_ = repo.AddJob(second)
+
+		claimed, _ := repo.ClaimNextJob(time.Minute)
+		if claimed.ID != first.ID || claimed.Status != models.JobRunning || claimed.Attempts != 1 || claimed.StartedAt == nil {
+			t.Errorf("Expected the first job to be claimed, got %+v", claimed)
+		}
+		claimed, _ = repo.ClaimNextJob(time.Minute)
+		if claimed.ID != second.ID {
+			t.Errorf("Expected the second job to be claimed, got %+v", claimed)
+		}
+		if claimed, _ := repo.ClaimNextJob(time.Minute); claimed != nil {
+			t.Errorf("Expected no job to claim, got %+v", claimed)
+		}
+	})
+
+	t.Run("Reclaims Jobs With Expired Lease", func(t *testing.T) {
+		repo := memdb.NewInMemoryJobRepository()
+		job := models.NewJob("test", nil, nil, "alice", "req-1")
+		_ = repo.AddJob(job)
+
+		_, _ = repo.ClaimNextJob(-time.Second)
+		reclaimed, _ := repo.ClaimNextJob(time.Minute)
+
+		if reclaimed == nil || reclaimed.ID != job.ID || reclaimed.Attempts != 2 {
+			t.Errorf("Expected the abandoned job to be reclaimed, got %+v", reclaimed)
+		}
+	})
+
+	t.Run("Cancellation", func(t *testing.T) {
+		repo := memdb.NewInMemoryJobRepository()
+		queued := models.NewJob("test", nil, nil, "alice", "req-1")
+		running := models.NewJob("test", nil, nil, "alice", "req-2")
+		_ = repo.AddJob(running)
+		_ = repo.AddJob(queued)
+		_, _ = repo.ClaimNextJob(time.Minute)
+
+		cancelled, err := repo.RequestJobCancellation(queued.ID)
+		if err != nil || cancelled.Status != models.JobCancelled || cancelled.FinishedAt == nil {
+			t.Errorf("Expected the queued job to be cancelled, got %+v (%v)", cancelled, err)
+		}
+		marked, err := repo.RequestJobCancellation(running.ID)
+		if err != nil || marked.Status != models.JobRunning || !marked.CancelRequested {
+			t.Errorf("Expected the running job to be marked, got %+v (%v)", marked, err)
+		}
+		if cancelRequested, _ := repo.HeartbeatJob(running.ID, models.JobProgress{Processed: 1}, time.Minute); !cancelRequested {
+			t.Error("Expected the heartbeat to report the cancellation")
+		}
+		if _, err := repo.RequestJobCancellation(queued.ID); !errors.Is(err, models.ErrJobAlreadyFinished) {
+			t.Errorf("Expected ErrJobAlreadyFinished, got %v", err)
+		}
+		if _, err := repo.RequestJobCancellation("missing"); !errors.Is(err, models.ErrJobNotFound) {
+			t.Errorf("Expected ErrJobNotFound, got %v", err)
+		}
+	})
+
+	t.Run("DeleteFinishedJobs", func(t *testing.T) {
+		repo := memdb.NewInMemoryJobRepository()
+		finished := models.NewJob("test", nil, nil, "alice", "req-1")
+		pending := models.NewJob("test", nil, nil, "alice", "req-2")
+		_ = repo.AddJob(finished)
+		_ = repo.AddJob(pending)
+		_, _ = repo.RequestJobCancellation(finished.ID)
+
+		deleted, err := repo.DeleteFinishedJobs(time.Now().Add(time.Second))
+
+		if err != nil || deleted != 1 {
+			t.Errorf("Expected 1 deleted job, got %d (%v)", deleted, err)
+		}
+		if _, err := repo.GetJob(pending.ID); err != nil {
+			t.Errorf("Expected the pending job to be kept, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,16 @@
+package memdb
+
+import "github.com/danielrios/product-service-go/internal/core/models"
+
+// recordEvent registra todos os efeitos derivados de uma mutação: a mensagem do outbox, o registro do
+// feed de alterações, a revisão do produto, o registro de auditoria e a notificação aos ouvintes. before
+// é o estado do produto antes da mutação (nulo na criação). Deve ser chamado com o mutex de escrita
+// adquirido, para que a alteração do produto e seus efeitos sejam visíveis atomicamente.
+func (r *InMemoryProductRepository) recordEvent(eventType models.EventType, product, before *models.Product, mutation models.Mutation) {
+	event := models.NewProductEvent(eventType, product)
+	r.writeOutbox(event)
+	r.recordChange(event)
+	r.recordRevision(event)
+	r.audit = append(r.audit, models.NewEventAuditEntry(event, before, mutation))
+	r.notifyListeners(event)
+}
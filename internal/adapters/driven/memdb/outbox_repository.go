@@ -0,0 +1,79 @@
+package memdb
+
+import (
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+// outbox guarda as mensagens pendentes do repositório em memória.
+// Os campos são protegidos pelo mutex do InMemoryProductRepository.
+type outbox struct {
+	messages []*models.OutboxMessage
+	inFlight map[int64]bool
+	nextID   int64
+}
+
+var _ ports.OutboxRepository = (*InMemoryProductRepository)(nil)
+
+// writeOutbox grava o evento no outbox. Deve ser chamado com o mutex de escrita adquirido.
+func (r *InMemoryProductRepository) writeOutbox(event models.ProductEvent) {
+	r.outbox.nextID++
+	r.outbox.messages = append(r.outbox.messages, &models.OutboxMessage{
+		ID:            r.outbox.nextID,
+		Event:         event,
+		NextAttemptAt: time.Now(),
+	})
+}
+
+// ProcessPending entrega as mensagens pendentes do outbox em memória.
+// Mensagens em entrega ficam reservadas e não são retornadas a chamadas concorrentes.
+func (r *InMemoryProductRepository) ProcessPending(limit int, deliver func(msg *models.OutboxMessage) error, retryAfter func(attempts int) time.Duration) (int, error) {
+	r.mu.Lock()
+	if r.outbox.inFlight == nil {
+		r.outbox.inFlight = make(map[int64]bool)
+	}
+	now := time.Now()
+	batch := make([]models.OutboxMessage, 0, limit)
+	for _, msg := range r.outbox.messages {
+		if len(batch) == limit {
+			break
+		}
+		if r.outbox.inFlight[msg.ID] || msg.NextAttemptAt.After(now) {
+			continue
+		}
+		r.outbox.inFlight[msg.ID] = true
+		batch = append(batch, *msg)
+	}
+	r.mu.Unlock()
+
+	// A entrega acontece fora do lock, para não bloquear o repositório durante chamadas externas.
+	results := make(map[int64]error, len(batch))
+	for i := range batch {
+		results[batch[i].ID] = deliver(&batch[i])
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	remaining := r.outbox.messages[:0]
+	for _, msg := range r.outbox.messages {
+		deliverErr, processed := results[msg.ID]
+		if !processed {
+			remaining = append(remaining, msg)
+			continue
+		}
+		delete(r.outbox.inFlight, msg.ID)
+		if deliverErr == nil {
+			continue // Mensagem despachada: removida do outbox.
+		}
+		msg.Attempts++
+		msg.LastError = deliverErr.Error()
+		msg.NextAttemptAt = time.Now().Add(retryAfter(msg.Attempts))
+		remaining = append(remaining, msg)
+	}
+	r.outbox.messages = remaining
+
+	return len(batch), nil
+}
@@ -0,0 +1,90 @@
+package memdb_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/adapters/driven/memdb"
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+func noRetryDelay(int) time.Duration { return 0 }
+
+func TestInMemoryProductRepository_ProcessPending(t *testing.T) {
+	t.Run("Records One Event Per Mutation", func(t *testing.T) {
+		repo := memdb.NewInMemoryProductRepository()
+		product, _ := models.NewProduct("1", "Test Product", 100.0)
+		_ = repo.Add(product, models.Mutation{})
+		updated, _ := models.NewProduct("1", "Updated Product", 150.0)
+		_ = repo.Update(updated, nil, models.Mutation{})
+		_ = repo.Delete("1", models.Mutation{})
+
+		var types []models.EventType
+		processed, err := repo.ProcessPending(10, func(msg *models.OutboxMessage) error {
+			types = append(types, msg.Event.Type)
+			return nil
+		}, noRetryDelay)
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if processed != 3 {
+			t.Fatalf("Expected 3 processed messages, got %d", processed)
+		}
+		expected := []models.EventType{models.EventProductCreated, models.EventProductUpdated, models.EventProductDeleted}
+		for i, eventType := range expected {
+			if types[i] != eventType {
+				t.Errorf("Expected event %d to be %s, got %s", i, eventType, types[i])
+			}
+		}
+	})
+
+	t.Run("Dispatched Messages Are Not Delivered Again", func(t *testing.T) {
+		repo := memdb.NewInMemoryProductRepository()
+		product, _ := models.NewProduct("1", "Test Product", 100.0)
+		_ = repo.Add(product, models.Mutation{})
+
+		_, _ = repo.ProcessPending(10, func(*models.OutboxMessage) error { return nil }, noRetryDelay)
+		processed, _ := repo.ProcessPending(10, func(*models.OutboxMessage) error { return nil }, noRetryDelay)
+
+		if processed != 0 {
+			t.Errorf("Expected no pending messages, got %d", processed)
+		}
+	})
+
+	t.Run("Failed Messages Are Rescheduled", func(t *testing.T) {
+		repo := memdb.NewInMemoryProductRepository()
+		product, _ := models.NewProduct("1", "Test Product", 100.0)
+		_ = repo.Add(product, models.Mutation{})
+
+		_, _ = repo.ProcessPending(10, func(*models.OutboxMessage) error {
+			return errors.New("sink unavailable")
+		}, func(int) time.Duration { return time.Hour })
+
+		processed, _ := repo.ProcessPending(10, func(*models.OutboxMessage) error { return nil }, noRetryDelay)
+		if processed != 0 {
+			t.Errorf("Expected message to wait for its backoff, but %d were processed", processed)
+		}
+	})
+
+	t.Run("Retries Carry Attempt Count And Last Error", func(t *testing.T) {
+		repo := memdb.NewInMemoryProductRepository()
+		product, _ := models.NewProduct("1", "Test Product", 100.0)
+		_ = repo.Add(product, models.Mutation{})
+
+		_, _ = repo.ProcessPending(10, func(*models.OutboxMessage) error {
+			return errors.New("sink unavailable")
+		}, noRetryDelay)
+
+		var retried models.OutboxMessage
+		_, _ = repo.ProcessPending(10, func(msg *models.OutboxMessage) error {
+			retried = *msg
+			return nil
+		}, noRetryDelay)
+
+		if retried.Attempts != 1 || retried.LastError != "sink unavailable" {
+			t.Errorf("Expected 1 previous attempt with its error, got %d attempts and error %q", retried.Attempts, retried.LastError)
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package memdb
+
+import (
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+var _ ports.ProductImportRepository = (*InMemoryProductRepository)(nil)
+
+// ExistingProducts retorna cópias dos produtos, dentre os IDs informados, que já estão no repositório.
+func (r *InMemoryProductRepository) ExistingProducts(ids []string) (map[string]*models.Product, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	existing := make(map[string]*models.Product)
+	for _, id := range ids {
+		if product, ok := r.products[id]; ok {
+			copied := *product
+			existing[id] = &copied
+		}
+	}
+	return existing, nil
+}
+
+// ImportProducts grava os produtos sob o mutex de escrita, de modo que a importação inteira é vista
+// atomicamente. Os conflitos do modo create e as alterações desde a leitura de expected são verificados
+// antes de qualquer gravação.
+func (r *InMemoryProductRepository) ImportProducts(products []*models.Product, expected map[string]*models.Product, mode models.ImportMode, mutation models.Mutation) ([]models.ImportedProduct, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if mode == models.ImportModeCreate {
+		for _, product := range products {
+			if _, ok := r.products[product.ID]; ok {
+				return nil, models.ErrProductAlreadyExists
+			}
+		}
+	}
+	for _, product := range products {
+		if expected != nil && models.ProductModified(expected[product.ID], r.products[product.ID]) {
+			return nil, models.ErrProductModified
+		}
+	}
+
+	now := time.Now()
+	results := make([]models.ImportedProduct, 0, len(products))
+	for _, product := range products {
+		stored := *product
+		stored.UpdatedAt = now
+		eventType := models.EventProductCreated
+
+		existing, ok := r.products[product.ID]
+		if ok {
+			stored.CreatedAt = existing.CreatedAt
+			eventType = models.EventProductUpdated
+		} else {
+			stored.CreatedAt = now
+		}
+
+		r.products[product.ID] = &stored
+		r.recordEvent(eventType, &stored, existing, mutation)
+		results = append(results, models.ImportedProduct{Before: existing, After: &stored})
+	}
+	return results, nil
+}
@@ -0,0 +1,83 @@
+package memdb_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/danielrios/product-service-go/internal/adapters/driven/memdb"
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+func TestInMemoryProductRepository_ImportProducts(t *testing.T) {
+	newRepo := func() (*memdb.InMemoryProductRepository, *models.Product) {
+		repo := memdb.NewInMemoryProductRepository()
+		existing, _ := models.NewProduct("1", "Existing", 10)
+		_ = repo.Add(existing, models.Mutation{})
+		return repo, existing
+	}
+	imported := []*models.Product{{ID: "1", Name: "Renamed", Price: 12}, {ID: "2", Name: "New", Price: 20}}
+
+	t.Run("Create Mode Rejects Existing IDs Atomically", func(t *testing.T) {
+		repo, _ := newRepo()
+
+		_, err := repo.ImportProducts(imported, nil, models.ImportModeCreate, models.Mutation{})
+
+		if !errors.Is(err, models.ErrProductAlreadyExists) {
+			t.Errorf("Expected ErrProductAlreadyExists, got %v", err)
+		}
+		if _, err := repo.GetByID("2"); !errors.Is(err, models.ErrProductNotFound) {
+			t.Errorf("Expected product 2 not to be created, got %v", err)
+		}
+	})
+
+	t.Run("Upsert Records Events And Preserves CreatedAt", func(t *testing.T) {
+		repo, existing := newRepo()
+
+		results, err := repo.ImportProducts(imported, nil, models.ImportModeUpsert, models.Mutation{})
+
+		if err != nil || len(results) != 2 {
+			t.Fatalf("Expected 2 results, got %d (%v)", len(results), err)
+		}
+		if results[0].Before == nil || results[0].Before.Name != "Existing" || results[1].Before != nil {
+			t.Errorf("Unexpected previous states: %+v, %+v", results[0].Before, results[1].Before)
+		}
+		if !results[0].After.CreatedAt.Equal(existing.CreatedAt) || results[0].After.Name != "Renamed" {
+			t.Errorf("Expected updated product to keep CreatedAt, got %+v", results[0].After)
+		}
+
+		changes, _ := repo.ChangesSince(0, 10)
+		if len(changes) != 3 || changes[1].Type != models.EventProductUpdated || changes[2].Type != models.EventProductCreated {
+			t.Errorf("Expected create, update and create changes, got %+v", changes)
+		}
+		revisions, _ := repo.ListRevisions("1")
+		if len(revisions) != 2 || revisions[1].Revision != 2 {
+			t.Errorf("Expected a second revision for product 1, got %+v", revisions)
+		}
+	})
+
+	t.Run("Products Modified Since The Read Are Rejected", func(t *testing.T) {
+		repo, _ := newRepo()
+		expected, _ := repo.ExistingProducts([]string{"1", "2"})
+		created, _ := models.NewProduct("2", "Concurrent", 5)
+		_ = repo.Add(created, models.Mutation{})
+
+		_, err := repo.ImportProducts(imported, expected, models.ImportModeUpsert, models.Mutation{})
+
+		if !errors.Is(err, models.ErrProductModified) {
+			t.Errorf("Expected ErrProductModified, got %v", err)
+		}
+		if product, _ := repo.GetByID("1"); product.Name != "Existing" {
+			t.Errorf("Expected nothing to be written, got %+v", product)
+		}
+	})
+
+	t.Run("ExistingProducts", func(t *testing.T) {
+		repo, _ := newRepo()
+
+		existing, err := repo.ExistingProducts([]string{"1", "2"})
+
+		if err != nil || len(existing) != 1 || existing["1"] == nil || existing["1"].Name == "" {
+			t.Errorf("Expected only product 1, got %v (%v)", existing, err)
+		}
+	})
+}
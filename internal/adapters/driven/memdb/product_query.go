@@ -0,0 +1,60 @@
+package memdb
+
+import (
+	"sort"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+var _ ports.ProductQueryRepository = (*InMemoryProductRepository)(nil)
+
+// Find retorna cópias dos produtos que atendem ao filtro, ordenados pela data de criação. Os produtos
+// já estão em memória, então a projeção é ignorada e todos os campos são preenchidos.
+func (r *InMemoryProductRepository) Find(filter models.ProductFilter, _ models.ProductProjection) ([]*models.Product, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	products := make([]*models.Product, 0)
+	for _, p := range r.products {
+		if filter.Matches(p) {
+			product := *p
+			products = append(products, &product)
+		}
+	}
+	sort.Slice(products, func(i, j int) bool {
+		if products[i].CreatedAt.Equal(products[j].CreatedAt) {
+			return products[i].ID < products[j].ID
+		}
+		return products[i].CreatedAt.Before(products[j].CreatedAt)
+	})
+	return products, nil
+}
+
+// ForEach percorre uma cópia dos produtos filtrados, de modo que fn pode ser lento sem bloquear as escritas.
+func (r *InMemoryProductRepository) ForEach(filter models.ProductFilter, fn func(product *models.Product) error) error {
+	products, err := r.Find(filter, nil)
+	if err != nil {
+		return err
+	}
+	for _, product := range products {
+		if err := fn(product); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Stats agrega os produtos em uma única passagem, sem copiá-los.
+func (r *InMemoryProductRepository) Stats(filter models.ProductFilter, groupBy models.StatsGrouping) (*models.ProductStats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	builder := models.NewProductStatsBuilder(groupBy)
+	for _, p := range r.products {
+		if filter.Matches(p) {
+			builder.Add(p)
+		}
+	}
+	return builder.Build(), nil
+}
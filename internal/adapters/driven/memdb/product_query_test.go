@@ -0,0 +1,57 @@
+package memdb_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/danielrios/product-service-go/internal/adapters/driven/memdb"
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+func TestInMemoryProductRepository_Queries(t *testing.T) {
+	repo := memdb.NewInMemoryProductRepository()
+	for _, p := range []struct {
+		id, name string
+		price    float64
+	}{{"1", "Blue Shirt", 50}, {"2", "Red Shirt", 70}, {"3", "Blue Jeans", 120}} {
+		product, _ := models.NewProduct(p.id, p.name, p.price)
+		_ = repo.Add(product, models.Mutation{})
+	}
+	minPrice := 60.0
+
+	t.Run("Find", func(t *testing.T) {
+		products, err := repo.Find(models.ProductFilter{NameContains: "shirt", MinPrice: &minPrice}, nil)
+
+		if err != nil || len(products) != 1 || products[0].ID != "2" {
+			t.Errorf("Expected only product 2, got %+v (%v)", products, err)
+		}
+	})
+
+	t.Run("ForEach Stops On Error", func(t *testing.T) {
+		stop := errors.New("stop")
+		var visited []string
+
+		err := repo.ForEach(models.ProductFilter{}, func(product *models.Product) error {
+			visited = append(visited, product.ID)
+			if len(visited) == 2 {
+				return stop
+			}
+			return nil
+		})
+
+		if !errors.Is(err, stop) || len(visited) != 2 || visited[0] != "1" || visited[1] != "2" {
+			t.Errorf("Expected to stop after products 1 and 2, got %v (%v)", visited, err)
+		}
+	})
+
+	t.Run("Stats", func(t *testing.T) {
+		stats, err := repo.Stats(models.ProductFilter{NameContains: "blue"}, models.StatsGroupDay)
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if stats.Count != 2 || *stats.AvgPrice != 85 || len(stats.Groups) != 1 || stats.Groups[0].Count != 2 {
+			t.Errorf("Unexpected stats: %+v", stats)
+		}
+	})
+}
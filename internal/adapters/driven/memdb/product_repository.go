@@ -4,12 +4,21 @@ import (
 	"github.com/danielrios/product-service-go/internal/core/models"
 	"github.com/danielrios/product-service-go/internal/core/ports"
 	"sync"
+	"time"
 )
 
 // InMemoryProductRepository é um Adaptador de Saída (Driven Adapter) que implementa a porta ports ProductRepository definida no Core.
 type InMemoryProductRepository struct {
-	products map[string]*models.Product
-	mu       sync.RWMutex
+	products  map[string]*models.Product
+	outbox    outbox
+	changes   []*models.ProductChange
+	revisions map[string][]*models.ProductRevision
+	audit     []*models.AuditEntry
+	mu        sync.RWMutex
+
+	listeners    map[int]func(models.ProductEvent)
+	nextListener int
+	listenersMu  sync.RWMutex
 }
 
 // NewInMemoryProductRepository cria uma nova instância do repositório de produtos em memória.
@@ -22,7 +31,7 @@ func NewInMemoryProductRepository() *InMemoryProductRepository {
 var _ ports.ProductRepository = (*InMemoryProductRepository)(nil)
 
 // Add adiciona um novo produto ao repositório em memória.
-func (r *InMemoryProductRepository) Add(product *models.Product) error {
+func (r *InMemoryProductRepository) Add(product *models.Product, mutation models.Mutation) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -30,6 +39,7 @@ func (r *InMemoryProductRepository) Add(product *models.Product) error {
 		return models.ErrProductAlreadyExists
 	}
 	r.products[product.ID] = product
+	r.recordEvent(models.EventProductCreated, product, nil, mutation)
 	return nil
 }
 
@@ -57,26 +67,38 @@ func (r *InMemoryProductRepository) GetAll() ([]*models.Product, error) {
 	return allProducts, nil
 }
 
-// Update atualiza um produto existente no repositório em memória.
-func (r *InMemoryProductRepository) Update(product *models.Product) error {
+// Update atualiza um produto existente no repositório em memória, desde que ele ainda esteja no estado esperado.
+func (r *InMemoryProductRepository) Update(product, expected *models.Product, mutation models.Mutation) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, ok := r.products[product.ID]; !ok {
+	existing, ok := r.products[product.ID]
+	if !ok {
 		return models.ErrProductNotFound
 	}
-	r.products[product.ID] = product
+	if expected != nil && models.ProductModified(expected, existing) {
+		return models.ErrProductModified
+	}
+	// Assim como no PostgreSQL, apenas nome e preço são alterados; a data de criação é preservada
+	// e a data de atualização é definida pelo repositório.
+	updated := *product
+	updated.CreatedAt = existing.CreatedAt
+	updated.UpdatedAt = time.Now()
+	r.products[product.ID] = &updated
+	r.recordEvent(models.EventProductUpdated, &updated, existing, mutation)
 	return nil
 }
 
 // Delete remove um produto pelo seu ID do repositório em memória.
-func (r *InMemoryProductRepository) Delete(id string) error {
+func (r *InMemoryProductRepository) Delete(id string, mutation models.Mutation) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, ok := r.products[id]; !ok {
+	product, ok := r.products[id]
+	if !ok {
 		return models.ErrProductNotFound
 	}
 	delete(r.products, id)
+	r.recordEvent(models.EventProductDeleted, product, product, mutation)
 	return nil
 }
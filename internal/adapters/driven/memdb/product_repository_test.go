@@ -16,7 +16,7 @@ func TestInMemoryProductRepository_Add(t *testing.T) {
 			t.Fatalf("Failed to create test product: %v", err)
 		}
 
-		err = repo.Add(product)
+		err = repo.Add(product, models.Mutation{})
 
 		if err != nil {
 			t.Errorf("Expected no error, got %v", err)
@@ -35,9 +35,9 @@ func TestInMemoryProductRepository_Add(t *testing.T) {
 		repo := memdb.NewInMemoryProductRepository()
 		product, _ := models.NewProduct("1", "Test Product", 100.0)
 
-		_ = repo.Add(product)
+		_ = repo.Add(product, models.Mutation{})
 
-		err := repo.Add(product)
+		err := repo.Add(product, models.Mutation{})
 
 		if !errors.Is(err, models.ErrProductAlreadyExists) {
 			t.Errorf("Expected ErrProductAlreadyExists, got %v", err)
@@ -49,7 +49,7 @@ func TestInMemoryProductRepository_GetByID(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		repo := memdb.NewInMemoryProductRepository()
 		product, _ := models.NewProduct("1", "Test Product", 100.0)
-		_ = repo.Add(product)
+		_ = repo.Add(product, models.Mutation{})
 
 		retrievedProduct, err := repo.GetByID("1")
 
@@ -77,8 +77,8 @@ func TestInMemoryProductRepository_GetAll(t *testing.T) {
 		repo := memdb.NewInMemoryProductRepository()
 		product1, _ := models.NewProduct("1", "Product 1", 100.0)
 		product2, _ := models.NewProduct("2", "Product 2", 200.0)
-		_ = repo.Add(product1)
-		_ = repo.Add(product2)
+		_ = repo.Add(product1, models.Mutation{})
+		_ = repo.Add(product2, models.Mutation{})
 
 		products, err := repo.GetAll()
 
@@ -126,11 +126,11 @@ func TestInMemoryProductRepository_Update(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		repo := memdb.NewInMemoryProductRepository()
 		product, _ := models.NewProduct("1", "Original Product", 100.0)
-		_ = repo.Add(product)
+		_ = repo.Add(product, models.Mutation{})
 
 		updatedProduct, _ := models.NewProduct("1", "Updated Product", 150.0)
 
-		err := repo.Update(updatedProduct)
+		err := repo.Update(updatedProduct, nil, models.Mutation{})
 
 		if err != nil {
 			t.Errorf("Expected no error, got %v", err)
@@ -142,11 +142,44 @@ func TestInMemoryProductRepository_Update(t *testing.T) {
 		}
 	})
 
+	t.Run("Maintains Timestamps", func(t *testing.T) {
+		repo := memdb.NewInMemoryProductRepository()
+		product, _ := models.NewProduct("1", "Original Product", 100.0)
+		_ = repo.Add(product, models.Mutation{})
+
+		_ = repo.Update(&models.Product{ID: "1", Name: "Updated Product", Price: 150.0}, nil, models.Mutation{})
+
+		retrievedProduct, _ := repo.GetByID("1")
+		if !retrievedProduct.CreatedAt.Equal(product.CreatedAt) {
+			t.Errorf("Expected CreatedAt to be preserved, got %v", retrievedProduct.CreatedAt)
+		}
+		if !retrievedProduct.UpdatedAt.After(product.UpdatedAt) {
+			t.Errorf("Expected UpdatedAt to advance past %v, got %v", product.UpdatedAt, retrievedProduct.UpdatedAt)
+		}
+	})
+
+	t.Run("Stale Expected State", func(t *testing.T) {
+		repo := memdb.NewInMemoryProductRepository()
+		product, _ := models.NewProduct("1", "Original Product", 100.0)
+		_ = repo.Add(product, models.Mutation{})
+		expected := *product
+		_ = repo.Update(&models.Product{ID: "1", Name: "Original Product", Price: 120.0}, nil, models.Mutation{})
+
+		err := repo.Update(&models.Product{ID: "1", Name: "Renamed", Price: 100.0}, &expected, models.Mutation{})
+
+		if !errors.Is(err, models.ErrProductModified) {
+			t.Errorf("Expected ErrProductModified, got %v", err)
+		}
+		if retrieved, _ := repo.GetByID("1"); retrieved.Name != "Original Product" || retrieved.Price != 120.0 {
+			t.Errorf("Expected the product to be unchanged, got %v", retrieved)
+		}
+	})
+
 	t.Run("Product Not Found", func(t *testing.T) {
 		repo := memdb.NewInMemoryProductRepository()
 		product, _ := models.NewProduct("1", "Test Product", 100.0)
 
-		err := repo.Update(product)
+		err := repo.Update(product, nil, models.Mutation{})
 
 		if !errors.Is(err, models.ErrProductNotFound) {
 			t.Errorf("Expected ErrProductNotFound, got %v", err)
@@ -158,9 +191,9 @@ func TestInMemoryProductRepository_Delete(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		repo := memdb.NewInMemoryProductRepository()
 		product, _ := models.NewProduct("1", "Test Product", 100.0)
-		_ = repo.Add(product)
+		_ = repo.Add(product, models.Mutation{})
 
-		err := repo.Delete("1")
+		err := repo.Delete("1", models.Mutation{})
 
 		if err != nil {
 			t.Errorf("Expected no error, got %v", err)
@@ -175,7 +208,7 @@ func TestInMemoryProductRepository_Delete(t *testing.T) {
 	t.Run("Product Not Found", func(t *testing.T) {
 		repo := memdb.NewInMemoryProductRepository()
 
-		err := repo.Delete("nonexistent")
+		err := repo.Delete("nonexistent", models.Mutation{})
 
 		if !errors.Is(err, models.ErrProductNotFound) {
 			t.Errorf("Expected ErrProductNotFound, got %v", err)
@@ -188,7 +221,7 @@ func TestInMemoryProductRepository_Concurrency(t *testing.T) {
 
 		repo := memdb.NewInMemoryProductRepository()
 		product, _ := models.NewProduct("1", "Test Product", 100.0)
-		_ = repo.Add(product)
+		_ = repo.Add(product, models.Mutation{})
 
 		done := make(chan bool)
 		for i := 0; i < 10; i++ {
@@ -198,7 +231,7 @@ func TestInMemoryProductRepository_Concurrency(t *testing.T) {
 					_, _ = repo.GetAll()
 				} else {
 					updatedProduct, _ := models.NewProduct("1", "Updated Product", float64(100+index))
-					_ = repo.Update(updatedProduct)
+					_ = repo.Update(updatedProduct, nil, models.Mutation{})
 				}
 				done <- true
 			}(i)
@@ -0,0 +1,192 @@
+package memdb
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+// InMemoryWebhookRepository é um Adaptador de Saída que implementa ports.WebhookRepository em memória.
+// As entidades são copiadas na entrada e na saída, evitando que alterações externas afetem o estado armazenado.
+type InMemoryWebhookRepository struct {
+	subscriptions map[string]*models.WebhookSubscription
+	deliveries    map[string]*models.WebhookDelivery
+	enqueued      map[deliveryKey]struct{} // Pares inscrição/evento já enfileirados.
+	mu            sync.RWMutex
+}
+
+// deliveryKey identifica a entrega de um evento a uma inscrição.
+type deliveryKey struct {
+	subscriptionID string
+	eventID        string
+}
+
+// NewInMemoryWebhookRepository cria uma nova instância do repositório de webhooks em memória.
+func NewInMemoryWebhookRepository() *InMemoryWebhookRepository {
+	return &InMemoryWebhookRepository{
+		subscriptions: make(map[string]*models.WebhookSubscription),
+		deliveries:    make(map[string]*models.WebhookDelivery),
+		enqueued:      make(map[deliveryKey]struct{}),
+	}
+}
+
+var _ ports.WebhookRepository = (*InMemoryWebhookRepository)(nil)
+
+// AddSubscription adiciona uma nova inscrição.
+func (r *InMemoryWebhookRepository) AddSubscription(sub *models.WebhookSubscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *sub
+	r.subscriptions[sub.ID] = &stored
+	return nil
+}
+
+// GetSubscription busca uma inscrição pelo ID.
+func (r *InMemoryWebhookRepository) GetSubscription(id string) (*models.WebhookSubscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sub, ok := r.subscriptions[id]
+	if !ok {
+		return nil, models.ErrWebhookNotFound
+	}
+	found := *sub
+	return &found, nil
+}
+
+// ListSubscriptions retorna todas as inscrições, ordenadas pela data de criação.
+func (r *InMemoryWebhookRepository) ListSubscriptions() ([]*models.WebhookSubscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	subs := make([]*models.WebhookSubscription, 0, len(r.subscriptions))
+	for _, sub := range r.subscriptions {
+		found := *sub
+		subs = append(subs, &found)
+	}
+	sort.Slice(subs, func(i, j int) bool { return subs[i].CreatedAt.Before(subs[j].CreatedAt) })
+	return subs, nil
+}
+
+// UpdateSubscription atualiza uma inscrição existente.
+func (r *InMemoryWebhookRepository) UpdateSubscription(sub *models.WebhookSubscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.subscriptions[sub.ID]; !ok {
+		return models.ErrWebhookNotFound
+	}
+	stored := *sub
+	r.subscriptions[sub.ID] = &stored
+	return nil
+}
+
+// DeleteSubscription remove uma inscrição e todo o seu histórico de entregas.
+func (r *InMemoryWebhookRepository) DeleteSubscription(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.subscriptions[id]; !ok {
+		return models.ErrWebhookNotFound
+	}
+	delete(r.subscriptions, id)
+	for deliveryID, delivery := range r.deliveries {
+		if delivery.SubscriptionID == id {
+			delete(r.deliveries, deliveryID)
+			delete(r.enqueued, deliveryKey{delivery.SubscriptionID, delivery.Event.ID})
+		}
+	}
+	return nil
+}
+
+// EnqueueDeliveries grava as entregas, ignorando as que repetem um par evento/inscrição já enfileirado.
+func (r *InMemoryWebhookRepository) EnqueueDeliveries(deliveries []*models.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, delivery := range deliveries {
+		key := deliveryKey{delivery.SubscriptionID, delivery.Event.ID}
+		if _, ok := r.enqueued[key]; ok {
+			continue
+		}
+		stored := *delivery
+		r.deliveries[delivery.ID] = &stored
+		r.enqueued[key] = struct{}{}
+	}
+	return nil
+}
+
+// ClaimDueDeliveries reserva as entregas pendentes e vencidas mais antigas.
+func (r *InMemoryWebhookRepository) ClaimDueDeliveries(limit int, lease time.Duration) ([]*models.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	due := make([]*models.WebhookDelivery, 0)
+	for _, delivery := range r.deliveries {
+		if delivery.Status == models.DeliveryPending && !delivery.NextAttemptAt.After(now) {
+			due = append(due, delivery)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].NextAttemptAt.Before(due[j].NextAttemptAt) })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	claimed := make([]*models.WebhookDelivery, 0, len(due))
+	for _, delivery := range due {
+		delivery.NextAttemptAt = now.Add(lease)
+		found := *delivery
+		claimed = append(claimed, &found)
+	}
+	return claimed, nil
+}
+
+// GetDelivery busca uma entrega pelo ID.
+func (r *InMemoryWebhookRepository) GetDelivery(id string) (*models.WebhookDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	delivery, ok := r.deliveries[id]
+	if !ok {
+		return nil, models.ErrWebhookDeliveryNotFound
+	}
+	found := *delivery
+	return &found, nil
+}
+
+// UpdateDelivery registra o resultado de uma tentativa de entrega.
+func (r *InMemoryWebhookRepository) UpdateDelivery(delivery *models.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.deliveries[delivery.ID]; !ok {
+		return models.ErrWebhookDeliveryNotFound
+	}
+	stored := *delivery
+	r.deliveries[delivery.ID] = &stored
+	return nil
+}
+
+// ListDeliveries retorna as entregas mais recentes da inscrição.
+func (r *InMemoryWebhookRepository) ListDeliveries(subscriptionID string, limit int) ([]*models.WebhookDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	deliveries := make([]*models.WebhookDelivery, 0)
+	for _, delivery := range r.deliveries {
+		if delivery.SubscriptionID == subscriptionID {
+			found := *delivery
+			deliveries = append(deliveries, &found)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt) })
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,101 @@
+package memdb_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/adapters/driven/memdb"
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+func newTestDelivery(t *testing.T, repo *memdb.InMemoryWebhookRepository) (*models.WebhookSubscription, *models.WebhookDelivery) {
+	t.Helper()
+	sub, err := models.NewWebhookSubscription("http://localhost/callback", nil, "")
+	if err != nil {
+		t.Fatalf("Failed to create test subscription: %v", err)
+	}
+	_ = repo.AddSubscription(sub)
+
+	product, _ := models.NewProduct("1", "Test Product", 100.0)
+	delivery := models.NewWebhookDelivery(sub.ID, models.NewProductEvent(models.EventProductCreated, product))
+	_ = repo.EnqueueDeliveries([]*models.WebhookDelivery{delivery})
+	return sub, delivery
+}
+
+func TestInMemoryWebhookRepository_EnqueueDeliveries(t *testing.T) {
+	t.Run("Ignores Repeated Event For The Same Subscription", func(t *testing.T) {
+		repo := memdb.NewInMemoryWebhookRepository()
+		sub, delivery := newTestDelivery(t, repo)
+
+		repeated := models.NewWebhookDelivery(sub.ID, delivery.Event)
+		if err := repo.EnqueueDeliveries([]*models.WebhookDelivery{repeated}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if _, err := repo.GetDelivery(repeated.ID); !errors.Is(err, models.ErrWebhookDeliveryNotFound) {
+			t.Errorf("Expected the repeated delivery to be ignored, got %v", err)
+		}
+		if deliveries, _ := repo.ListDeliveries(sub.ID, 10); len(deliveries) != 1 {
+			t.Errorf("Expected 1 delivery, got %d", len(deliveries))
+		}
+	})
+}
+
+func TestInMemoryWebhookRepository_ClaimDueDeliveries(t *testing.T) {
+	t.Run("Claimed Deliveries Are Leased", func(t *testing.T) {
+		repo := memdb.NewInMemoryWebhookRepository()
+		newTestDelivery(t, repo)
+
+		first, err := repo.ClaimDueDeliveries(10, time.Hour)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		second, _ := repo.ClaimDueDeliveries(10, time.Hour)
+
+		if len(first) != 1 {
+			t.Errorf("Expected 1 claimed delivery, got %d", len(first))
+		}
+		if len(second) != 0 {
+			t.Errorf("Expected leased delivery not to be claimed again, got %d", len(second))
+		}
+	})
+
+	t.Run("Finished Deliveries Are Not Claimed", func(t *testing.T) {
+		repo := memdb.NewInMemoryWebhookRepository()
+		_, delivery := newTestDelivery(t, repo)
+		delivery.Status = models.DeliverySucceeded
+		_ = repo.UpdateDelivery(delivery)
+
+		claimed, _ := repo.ClaimDueDeliveries(10, time.Hour)
+
+		if len(claimed) != 0 {
+			t.Errorf("Expected no claimed deliveries, got %d", len(claimed))
+		}
+	})
+}
+
+func TestInMemoryWebhookRepository_DeleteSubscription(t *testing.T) {
+	t.Run("Removes Delivery History", func(t *testing.T) {
+		repo := memdb.NewInMemoryWebhookRepository()
+		sub, delivery := newTestDelivery(t, repo)
+
+		if err := repo.DeleteSubscription(sub.ID); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if _, err := repo.GetDelivery(delivery.ID); !errors.Is(err, models.ErrWebhookDeliveryNotFound) {
+			t.Errorf("Expected ErrWebhookDeliveryNotFound, got %v", err)
+		}
+	})
+
+	t.Run("Subscription Not Found", func(t *testing.T) {
+		repo := memdb.NewInMemoryWebhookRepository()
+
+		err := repo.DeleteSubscription("nonexistent")
+
+		if !errors.Is(err, models.ErrWebhookNotFound) {
+			t.Errorf("Expected ErrWebhookNotFound, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package postgresdb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+// PostgresAPIKeyRepository é a implementação do repositório de chaves de API para PostgreSQL.
+type PostgresAPIKeyRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresAPIKeyRepository cria uma nova instância do repositório sobre o pool de conexões informado.
+func NewPostgresAPIKeyRepository(db *sql.DB) *PostgresAPIKeyRepository {
+	return &PostgresAPIKeyRepository{db: db}
+}
+
+// Garante em tempo de compilação que PostgresAPIKeyRepository implementa a interface.
+var _ ports.APIKeyRepository = (*PostgresAPIKeyRepository)(nil)
+
+const apiKeyColumns = "id, name, secret_hash, scopes, roles, created_by, created_at, expires_at, last_used_at, revoked_at"
+
+// AddAPIKey adiciona uma nova chave ao banco de dados.
+func (r *PostgresAPIKeyRepository) AddAPIKey(key *models.APIKey) error {
+	scopes, err := json.Marshal(key.Scopes)
+	if err != nil {
+		return err
+	}
+	roles, err := json.Marshal(key.Roles)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO api_keys (id, name, secret_hash, scopes, roles, created_by, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	_, err = r.db.ExecContext(context.Background(), query, key.ID, key.Name, key.SecretHash, scopes, roles,
+		key.CreatedBy, key.CreatedAt, key.ExpiresAt)
+	return err
+}
+
+// GetAPIKey busca uma chave pelo ID.
+func (r *PostgresAPIKeyRepository) GetAPIKey(id string) (*models.APIKey, error) {
+	query := "SELECT " + apiKeyColumns + " FROM api_keys WHERE id = $1"
+	key, err := scanAPIKey(r.db.QueryRowContext(context.Background(), query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrAPIKeyNotFound
+	}
+	return key, err
+}
+
+// ListAPIKeys retorna todas as chaves, ordenadas pela data de criação.
+func (r *PostgresAPIKeyRepository) ListAPIKeys() (keys []*models.APIKey, err error) {
+	rows, err := r.db.QueryContext(context.Background(), "SELECT "+apiKeyColumns+" FROM api_keys ORDER BY created_at")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = errors.Join(err, rows.Close())
+	}()
+
+	keys = []*models.APIKey{}
+	for rows.Next() {
+		key, scanErr := scanAPIKey(rows)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+		keys = append(keys, key)
+	}
+
+	err = rows.Err()
+	return keys, err
+}
+
+// RevokeAPIKey marca a chave como revogada, preservando a data de uma revogação anterior.
+func (r *PostgresAPIKeyRepository) RevokeAPIKey(id string, at time.Time) error {
+	query := "UPDATE api_keys SET revoked_at = COALESCE(revoked_at, $2) WHERE id = $1"
+	result, err := r.db.ExecContext(context.Background(), query, id, at)
+	return requireAffected(result, err, models.ErrAPIKeyNotFound)
+}
+
+// TouchAPIKey registra o último uso da chave. Usos simultâneos mantêm o instante mais recente.
+func (r *PostgresAPIKeyRepository) TouchAPIKey(id string, at time.Time) error {
+	query := "UPDATE api_keys SET last_used_at = GREATEST(last_used_at, $2) WHERE id = $1"
+	result, err := r.db.ExecContext(context.Background(), query, id, at)
+	return requireAffected(result, err, models.ErrAPIKeyNotFound)
+}
+
+func scanAPIKey(row rowScanner) (*models.APIKey, error) {
+	var (
+		key    models.APIKey
+		scopes []byte
+		roles  []byte
+	)
+	if err := row.Scan(&key.ID, &key.Name, &key.SecretHash, &scopes, &roles, &key.CreatedBy, &key.CreatedAt,
+		&key.ExpiresAt, &key.LastUsedAt, &key.RevokedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(scopes, &key.Scopes); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(roles, &key.Roles); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
@@ -0,0 +1,136 @@
+package postgresdb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+// Garante em tempo de compilação que PostgresProductRepository implementa a consulta à auditoria.
+// A imutabilidade dos registros é garantida no banco por um trigger que rejeita UPDATE e DELETE.
+var _ ports.AuditRepository = (*PostgresProductRepository)(nil)
+
+// auditColumns são as colunas de audit_log, na ordem dos valores de auditValues.
+var auditColumns = []string{"id", "product_id", "operation", "actor", "request_id", "occurred_at", "before", "after", "changes"}
+
+// recordAudit insere o registro no log de auditoria, na transação da mutação que ele descreve.
+func (r *PostgresProductRepository) recordAudit(tx *sql.Tx, entry *models.AuditEntry) error {
+	values, err := auditValues(entry)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO audit_log (id, product_id, operation, actor, request_id, occurred_at, before, after, changes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	_, err = tx.ExecContext(context.Background(), query, values...)
+	return err
+}
+
+// auditValues codifica o registro nos valores das colunas de audit_log.
+func auditValues(entry *models.AuditEntry) ([]any, error) {
+	before, err := marshalNullable(entry.Before)
+	if err != nil {
+		return nil, err
+	}
+	after, err := marshalNullable(entry.After)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return nil, err
+	}
+	return []any{entry.ID, entry.ProductID, string(entry.Operation), entry.Actor, entry.RequestID, entry.Timestamp,
+		before, after, changes}, nil
+}
+
+// ListAuditEntries busca os registros que atendem ao filtro, dos mais recentes para os mais antigos.
+func (r *PostgresProductRepository) ListAuditEntries(filter models.AuditFilter) (entries []*models.AuditEntry, err error) {
+	var (
+		conditions []string
+		args       []any
+	)
+	addCondition := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.ProductID != "" {
+		addCondition("product_id = $%d", filter.ProductID)
+	}
+	if filter.Actor != "" {
+		addCondition("actor = $%d", filter.Actor)
+	}
+	if filter.Operation != "" {
+		addCondition("operation = $%d", string(filter.Operation))
+	}
+	if !filter.From.IsZero() {
+		addCondition("occurred_at >= $%d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		addCondition("occurred_at < $%d", filter.To)
+	}
+
+	query := "SELECT id, product_id, operation, actor, request_id, occurred_at, before, after, changes FROM audit_log"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY occurred_at DESC, id LIMIT $%d", len(args))
+
+	rows, err := r.db.QueryContext(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = errors.Join(err, rows.Close())
+	}()
+
+	entries = []*models.AuditEntry{}
+	for rows.Next() {
+		var (
+			entry                  models.AuditEntry
+			operation              string
+			before, after, changes []byte
+		)
+		if scanErr := rows.Scan(&entry.ID, &entry.ProductID, &operation, &entry.Actor, &entry.RequestID,
+			&entry.Timestamp, &before, &after, &changes); scanErr != nil {
+			return nil, scanErr
+		}
+		entry.Operation = models.AuditOperation(operation)
+		if err := unmarshalNullable(before, &entry.Before); err != nil {
+			return nil, err
+		}
+		if err := unmarshalNullable(after, &entry.After); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(changes, &entry.Changes); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &entry)
+	}
+
+	err = rows.Err()
+	return entries, err
+}
+
+// marshalNullable codifica o produto em JSON, retornando nil (NULL no banco) para produtos nulos.
+func marshalNullable(product *models.Product) ([]byte, error) {
+	if product == nil {
+		return nil, nil
+	}
+	return json.Marshal(product)
+}
+
+// unmarshalNullable decodifica um produto em JSON, mantendo nil para colunas NULL.
+func unmarshalNullable(data []byte, product **models.Product) error {
+	if data == nil {
+		return nil
+	}
+	return json.Unmarshal(data, product)
+}
@@ -0,0 +1,67 @@
+package postgresdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+// Garante em tempo de compilação que PostgresProductRepository implementa o feed de alterações.
+var _ ports.ChangeFeedRepository = (*PostgresProductRepository)(nil)
+
+// recordChange grava o registro do feed de alterações. A sequência é obtida de uma linha única em
+// change_sequence, cujo lock é mantido até o fim da transação: assim, as sequências ficam na mesma
+// ordem das confirmações e um consumidor nunca vê a sequência N+1 antes da N (o que aconteceria com BIGSERIAL).
+func (r *PostgresProductRepository) recordChange(tx *sql.Tx, event models.ProductEvent) error {
+	var sequence int64
+	row := tx.QueryRowContext(context.Background(), "UPDATE change_sequence SET value = value + 1 RETURNING value")
+	if err := row.Scan(&sequence); err != nil {
+		return err
+	}
+
+	change := models.NewProductChange(sequence, event)
+	product, err := marshalNullable(change.Product)
+	if err != nil {
+		return err
+	}
+
+	query := "INSERT INTO product_changes (seq, event_type, product_id, product, changed_at) VALUES ($1, $2, $3, $4, $5)"
+	_, err = tx.ExecContext(context.Background(), query, change.Sequence, string(change.Type), change.ProductID, product, change.ChangedAt)
+	return err
+}
+
+// ChangesSince busca as alterações com sequência maior que since.
+func (r *PostgresProductRepository) ChangesSince(since int64, limit int) (changes []*models.ProductChange, err error) {
+	query := "SELECT seq, event_type, product_id, product, changed_at FROM product_changes WHERE seq > $1 ORDER BY seq LIMIT $2"
+	rows, err := r.db.QueryContext(context.Background(), query, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = errors.Join(err, rows.Close())
+	}()
+
+	changes = []*models.ProductChange{}
+	for rows.Next() {
+		var (
+			change    models.ProductChange
+			eventType string
+			product   []byte
+		)
+		if scanErr := rows.Scan(&change.Sequence, &eventType, &change.ProductID, &product, &change.ChangedAt); scanErr != nil {
+			return nil, scanErr
+		}
+		change.Type = models.EventType(eventType)
+		change.Deleted = product == nil
+		if err := unmarshalNullable(product, &change.Product); err != nil {
+			return nil, err
+		}
+		changes = append(changes, &change)
+	}
+
+	err = rows.Err()
+	return changes, err
+}
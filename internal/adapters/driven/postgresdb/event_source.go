@@ -0,0 +1,67 @@
+package postgresdb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// productEventsChannel é o canal de LISTEN/NOTIFY usado para divulgar as alterações de produtos.
+const productEventsChannel = "product_events"
+
+// Garante em tempo de compilação que PostgresProductRepository implementa a porta de eventos.
+var _ ports.ProductEventSource = (*PostgresProductRepository)(nil)
+
+// notifyEvent publica o evento no canal de notificações dentro da transação informada.
+func (r *PostgresProductRepository) notifyEvent(tx *sql.Tx, event models.ProductEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(context.Background(), "SELECT pg_notify($1, $2)", productEventsChannel, string(payload))
+	return err
+}
+
+// Listen reserva uma conexão dedicada do pool, executa LISTEN no canal de eventos e repassa cada
+// notificação recebida a handle até que o contexto seja cancelado ou a conexão falhe.
+func (r *PostgresProductRepository) Listen(ctx context.Context, handle func(event models.ProductEvent)) error {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn any) error {
+		pgxConn := driverConn.(*stdlib.Conn).Conn()
+		if _, err := pgxConn.Exec(ctx, "LISTEN "+productEventsChannel); err != nil {
+			return err
+		}
+
+		for {
+			notification, err := pgxConn.WaitForNotification(ctx)
+			if err != nil {
+				return err
+			}
+
+			var event models.ProductEvent
+			if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+				log.Printf("Notificação de evento inválida ignorada: %v", err)
+				continue
+			}
+			handle(event)
+		}
+	})
+
+	// O cancelamento do contexto encerra a conexão do pgx; o pool a descarta ao recebê-la de volta.
+	if ctx.Err() != nil {
+		return nil
+	}
+	return fmt.Errorf("listen %s: %w", productEventsChannel, err)
+}
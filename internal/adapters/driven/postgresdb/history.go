@@ -0,0 +1,85 @@
+package postgresdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+// Garante em tempo de compilação que PostgresProductRepository implementa o histórico de revisões.
+var _ ports.ProductHistoryRepository = (*PostgresProductRepository)(nil)
+
+// recordRevision grava a próxima revisão do produto. O número é calculado a partir da última revisão,
+// o que é seguro porque recordChange já serializou as mutações ao bloquear change_sequence.
+func (r *PostgresProductRepository) recordRevision(tx *sql.Tx, event models.ProductEvent) error {
+	revision := models.NewProductRevision(0, event)
+	product, err := marshalNullable(revision.Product)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO product_revisions (product_id, revision, product, deleted, valid_from)
+		SELECT $1, COALESCE(MAX(revision), 0) + 1, $2, $3, $4 FROM product_revisions WHERE product_id = $1`
+	_, err = tx.ExecContext(context.Background(), query, revision.ProductID, product, revision.Deleted, revision.ValidFrom)
+	return err
+}
+
+// ListRevisions busca as revisões do produto em ordem crescente.
+func (r *PostgresProductRepository) ListRevisions(productID string) (revisions []*models.ProductRevision, err error) {
+	query := "SELECT product_id, revision, product, deleted, valid_from FROM product_revisions WHERE product_id = $1 ORDER BY revision"
+	rows, err := r.db.QueryContext(context.Background(), query, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = errors.Join(err, rows.Close())
+	}()
+
+	for rows.Next() {
+		var (
+			revision models.ProductRevision
+			product  []byte
+		)
+		if scanErr := rows.Scan(&revision.ProductID, &revision.Revision, &product, &revision.Deleted, &revision.ValidFrom); scanErr != nil {
+			return nil, scanErr
+		}
+		if err := unmarshalNullable(product, &revision.Product); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, &revision)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(revisions) == 0 {
+		return nil, models.ErrProductNotFound
+	}
+	return revisions, nil
+}
+
+// GetAsOf busca a última revisão do produto iniciada até o instante informado.
+func (r *PostgresProductRepository) GetAsOf(productID string, at time.Time) (*models.Product, error) {
+	query := `SELECT product FROM product_revisions
+		WHERE product_id = $1 AND valid_from <= $2
+		ORDER BY revision DESC
+		LIMIT 1`
+	var product []byte
+	err := r.db.QueryRowContext(context.Background(), query, productID, at).Scan(&product)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && product == nil) {
+		return nil, models.ErrProductNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var found *models.Product
+	if err := unmarshalNullable(product, &found); err != nil {
+		return nil, err
+	}
+	return found, nil
+}
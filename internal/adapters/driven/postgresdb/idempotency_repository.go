@@ -0,0 +1,106 @@
+package postgresdb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+// PostgresIdempotencyRepository é a implementação das chaves de idempotência para PostgreSQL.
+// A chave primária da tabela garante que apenas uma requisição reserve cada chave, mesmo com várias instâncias do serviço.
+type PostgresIdempotencyRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresIdempotencyRepository cria uma nova instância do repositório sobre o pool de conexões informado.
+func NewPostgresIdempotencyRepository(db *sql.DB) *PostgresIdempotencyRepository {
+	return &PostgresIdempotencyRepository{db: db}
+}
+
+// Garante em tempo de compilação que PostgresIdempotencyRepository implementa a interface.
+var _ ports.IdempotencyRepository = (*PostgresIdempotencyRepository)(nil)
+
+// Reserve insere o registro ou substitui um registro expirado da mesma chave. Se a chave estiver
+// em uso, retorna o registro existente.
+func (r *PostgresIdempotencyRepository) Reserve(record *models.IdempotencyRecord) (*models.IdempotencyRecord, error) {
+	ctx := context.Background()
+	query := `INSERT INTO idempotency_keys (key, fingerprint, status, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (key) DO UPDATE SET fingerprint = EXCLUDED.fingerprint, status = EXCLUDED.status,
+			response_status = NULL, response_headers = NULL, response_body = NULL,
+			created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= EXCLUDED.created_at`
+	result, err := r.db.ExecContext(ctx, query, record.Key, record.Fingerprint, string(record.Status),
+		record.CreatedAt, record.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if rowsAffected, err := result.RowsAffected(); err != nil || rowsAffected == 1 {
+		return nil, err
+	}
+
+	query = `SELECT key, fingerprint, status, response_status, response_headers, response_body, created_at, expires_at
+		FROM idempotency_keys WHERE key = $1`
+	existing, err := scanIdempotencyRecord(r.db.QueryRowContext(ctx, query, record.Key))
+	if errors.Is(err, sql.ErrNoRows) {
+		// O registro foi removido entre as duas consultas; uma nova tentativa pode reservá-lo.
+		return r.Reserve(record)
+	}
+	return existing, err
+}
+
+// Complete grava a resposta de uma chave reservada.
+func (r *PostgresIdempotencyRepository) Complete(record *models.IdempotencyRecord) error {
+	headers, err := json.Marshal(record.ResponseHeaders)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE idempotency_keys SET status = $2, response_status = $3, response_headers = $4,
+		response_body = $5, expires_at = $6 WHERE key = $1`
+	result, err := r.db.ExecContext(context.Background(), query, record.Key, string(record.Status),
+		record.ResponseStatus, headers, record.ResponseBody, record.ExpiresAt)
+	return requireAffected(result, err, models.ErrIdempotencyKeyNotFound)
+}
+
+// Release remove a chave.
+func (r *PostgresIdempotencyRepository) Release(key string) error {
+	_, err := r.db.ExecContext(context.Background(), "DELETE FROM idempotency_keys WHERE key = $1", key)
+	return err
+}
+
+// DeleteExpired remove os registros expirados.
+func (r *PostgresIdempotencyRepository) DeleteExpired(now time.Time) (int, error) {
+	result, err := r.db.ExecContext(context.Background(), "DELETE FROM idempotency_keys WHERE expires_at <= $1", now)
+	if err != nil {
+		return 0, err
+	}
+	deleted, err := result.RowsAffected()
+	return int(deleted), err
+}
+
+func scanIdempotencyRecord(row rowScanner) (*models.IdempotencyRecord, error) {
+	var (
+		record         models.IdempotencyRecord
+		status         string
+		responseStatus sql.NullInt64
+		headers        []byte
+	)
+	if err := row.Scan(&record.Key, &record.Fingerprint, &status, &responseStatus, &headers,
+		&record.ResponseBody, &record.CreatedAt, &record.ExpiresAt); err != nil {
+		return nil, err
+	}
+	record.Status = models.IdempotencyStatus(status)
+	record.ResponseStatus = int(responseStatus.Int64)
+	if headers != nil {
+		if err := json.Unmarshal(headers, &record.ResponseHeaders); err != nil {
+			return nil, err
+		}
+	}
+	return &record, nil
+}
@@ -0,0 +1,159 @@
+package postgresdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+// jobColumns são as colunas lidas por scanJob, na ordem esperada.
+const jobColumns = `id, type, status, parameters, input, processed, total, result, error, actor, request_id,
+	attempts, cancel_requested, lease_expires_at, created_at, started_at, finished_at`
+
+// PostgresJobRepository é a implementação da fila de jobs para PostgreSQL. Os jobs sobrevivem ao
+// reinício do serviço, e a reserva com FOR UPDATE SKIP LOCKED permite várias instâncias consumindo a fila.
+type PostgresJobRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresJobRepository cria uma nova instância do repositório sobre o pool de conexões informado.
+func NewPostgresJobRepository(db *sql.DB) *PostgresJobRepository {
+	return &PostgresJobRepository{db: db}
+}
+
+// Garante em tempo de compilação que PostgresJobRepository implementa a interface.
+var _ ports.JobRepository = (*PostgresJobRepository)(nil)
+
+// AddJob insere um job na fila.
+func (r *PostgresJobRepository) AddJob(job *models.Job) error {
+	query := `INSERT INTO jobs (id, type, status, parameters, input, actor, request_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	_, err := r.db.ExecContext(context.Background(), query, job.ID, string(job.Type), string(job.Status),
+		[]byte(job.Parameters), job.Input, job.Actor, job.RequestID, job.CreatedAt)
+	return err
+}
+
+// GetJob busca um job pelo ID.
+func (r *PostgresJobRepository) GetJob(id string) (*models.Job, error) {
+	row := r.db.QueryRowContext(context.Background(), "SELECT "+jobColumns+" FROM jobs WHERE id = $1", id)
+	job, err := scanJob(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrJobNotFound
+	}
+	return job, err
+}
+
+// ClaimNextJob reserva o job mais antigo que esteja na fila ou com o lease expirado. SKIP LOCKED evita
+// que instâncias concorrentes esperem umas pelas outras ou reservem o mesmo job.
+func (r *PostgresJobRepository) ClaimNextJob(lease time.Duration) (*models.Job, error) {
+	now := time.Now()
+	query := `UPDATE jobs SET status = $1, attempts = attempts + 1, lease_expires_at = $3,
+			started_at = COALESCE(started_at, $2)
+		WHERE id = (
+			SELECT id FROM jobs
+			WHERE status = $4 OR (status = $1 AND lease_expires_at <= $2)
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + jobColumns
+	row := r.db.QueryRowContext(context.Background(), query, string(models.JobRunning), now, now.Add(lease), string(models.JobQueued))
+	job, err := scanJob(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return job, err
+}
+
+// HeartbeatJob renova o lease e grava o progresso do job em execução.
+func (r *PostgresJobRepository) HeartbeatJob(id string, progress models.JobProgress, lease time.Duration) (bool, error) {
+	query := `UPDATE jobs SET processed = $2, total = $3, lease_expires_at = $4
+		WHERE id = $1 AND status = $5
+		RETURNING cancel_requested`
+	var cancelRequested bool
+	err := r.db.QueryRowContext(context.Background(), query, id, progress.Processed, progress.Total,
+		time.Now().Add(lease), string(models.JobRunning)).Scan(&cancelRequested)
+	if errors.Is(err, sql.ErrNoRows) {
+		// O job não está mais em execução, o que só ocorre se ele tiver sido cancelado.
+		return true, nil
+	}
+	return cancelRequested, err
+}
+
+// UpdateJob grava a situação, o progresso, o resultado e as datas do job.
+func (r *PostgresJobRepository) UpdateJob(job *models.Job) error {
+	query := `UPDATE jobs SET status = $2, processed = $3, total = $4, result = $5, error = $6, attempts = $7,
+		lease_expires_at = $8, started_at = $9, finished_at = $10
+		WHERE id = $1`
+	result, err := r.db.ExecContext(context.Background(), query, job.ID, string(job.Status), job.Progress.Processed,
+		job.Progress.Total, []byte(job.Result), job.Error, job.Attempts, job.LeaseExpiresAt, job.StartedAt, job.FinishedAt)
+	return requireAffected(result, err, models.ErrJobNotFound)
+}
+
+// RequestJobCancellation cancela um job na fila ou marca um job em execução para cancelamento,
+// em um único comando para não competir com a reserva feita pelos workers.
+func (r *PostgresJobRepository) RequestJobCancellation(id string) (*models.Job, error) {
+	query := `UPDATE jobs SET
+			status = CASE WHEN status = $2 THEN $3 ELSE status END,
+			finished_at = CASE WHEN status = $2 THEN $4 ELSE finished_at END,
+			cancel_requested = status <> $2
+		WHERE id = $1 AND status IN ($2, $5)
+		RETURNING ` + jobColumns
+	row := r.db.QueryRowContext(context.Background(), query, id, string(models.JobQueued), string(models.JobCancelled),
+		time.Now(), string(models.JobRunning))
+	job, err := scanJob(row)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return job, err
+	}
+
+	// Nenhuma linha alterada: o job não existe ou já terminou.
+	if _, err := r.GetJob(id); err != nil {
+		return nil, err
+	}
+	return nil, models.ErrJobAlreadyFinished
+}
+
+// DeleteFinishedJobs remove os jobs finalizados antes do instante informado.
+func (r *PostgresJobRepository) DeleteFinishedJobs(before time.Time) (int, error) {
+	result, err := r.db.ExecContext(context.Background(), "DELETE FROM jobs WHERE finished_at < $1", before)
+	if err != nil {
+		return 0, err
+	}
+	deleted, err := result.RowsAffected()
+	return int(deleted), err
+}
+
+func scanJob(row rowScanner) (*models.Job, error) {
+	var (
+		job                   models.Job
+		jobType, status       string
+		parameters, result    []byte
+		leaseExpiresAt        sql.NullTime
+		startedAt, finishedAt sql.NullTime
+	)
+	err := row.Scan(&job.ID, &jobType, &status, &parameters, &job.Input, &job.Progress.Processed, &job.Progress.Total,
+		&result, &job.Error, &job.Actor, &job.RequestID, &job.Attempts, &job.CancelRequested, &leaseExpiresAt,
+		&job.CreatedAt, &startedAt, &finishedAt)
+	if err != nil {
+		return nil, err
+	}
+	job.Type = models.JobType(jobType)
+	job.Status = models.JobStatus(status)
+	job.Parameters = parameters
+	job.Result = result
+	job.LeaseExpiresAt = nullableTime(leaseExpiresAt)
+	job.StartedAt = nullableTime(startedAt)
+	job.FinishedAt = nullableTime(finishedAt)
+	return &job, nil
+}
+
+func nullableTime(value sql.NullTime) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Time
+}
@@ -0,0 +1,27 @@
+package postgresdb
+
+import (
+	"database/sql"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+// recordEvent registra, na transação da alteração do produto, todos os efeitos derivados do evento:
+// a mensagem do outbox, o registro do feed de alterações, a revisão do produto, o registro de auditoria
+// e a notificação aos ouvintes (entregue pelo PostgreSQL somente após a confirmação da transação).
+// before é o estado do produto lido na própria transação antes da alteração (nulo na criação).
+func (r *PostgresProductRepository) recordEvent(tx *sql.Tx, event models.ProductEvent, before *models.Product, mutation models.Mutation) error {
+	if err := r.writeOutbox(tx, event); err != nil {
+		return err
+	}
+	if err := r.recordChange(tx, event); err != nil {
+		return err
+	}
+	if err := r.recordRevision(tx, event); err != nil {
+		return err
+	}
+	if err := r.recordAudit(tx, models.NewEventAuditEntry(event, before, mutation)); err != nil {
+		return err
+	}
+	return r.notifyEvent(tx, event)
+}
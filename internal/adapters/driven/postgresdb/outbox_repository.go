@@ -0,0 +1,99 @@
+package postgresdb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+// Garante em tempo de compilação que PostgresProductRepository implementa a porta do outbox.
+var _ ports.OutboxRepository = (*PostgresProductRepository)(nil)
+
+// writeOutbox grava o evento na tabela outbox usando a transação da alteração do produto.
+func (r *PostgresProductRepository) writeOutbox(tx *sql.Tx, event models.ProductEvent) error {
+	payload, err := json.Marshal(event.Product)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO outbox (event_id, event_type, aggregate_id, payload, occurred_at)
+		VALUES ($1, $2, $3, $4, $5)`
+	_, err = tx.ExecContext(context.Background(), query, event.ID, string(event.Type), event.ProductID, payload, event.OccurredAt)
+	return err
+}
+
+// ProcessPending reserva mensagens pendentes com FOR UPDATE SKIP LOCKED, permitindo que várias
+// instâncias do relay trabalhem em paralelo sem entregar a mesma mensagem simultaneamente.
+// O lock das linhas é mantido até o fim da transação, após o registro do resultado de cada entrega.
+func (r *PostgresProductRepository) ProcessPending(limit int, deliver func(msg *models.OutboxMessage) error, retryAfter func(attempts int) time.Duration) (processed int, err error) {
+	err = r.withTx(func(tx *sql.Tx) error {
+		messages, err := r.claimPending(tx, limit)
+		if err != nil {
+			return err
+		}
+
+		for _, msg := range messages {
+			if deliverErr := deliver(msg); deliverErr != nil {
+				query := `UPDATE outbox
+					SET attempts = attempts + 1, last_error = $2, next_attempt_at = now() + make_interval(secs => $3)
+					WHERE id = $1`
+				delay := retryAfter(msg.Attempts + 1).Seconds()
+				if _, err := tx.ExecContext(context.Background(), query, msg.ID, deliverErr.Error(), delay); err != nil {
+					return err
+				}
+				continue
+			}
+
+			query := "UPDATE outbox SET attempts = attempts + 1, dispatched_at = now() WHERE id = $1"
+			if _, err := tx.ExecContext(context.Background(), query, msg.ID); err != nil {
+				return err
+			}
+		}
+
+		processed = len(messages)
+		return nil
+	})
+	return processed, err
+}
+
+// claimPending seleciona e bloqueia as próximas mensagens pendentes do outbox.
+func (r *PostgresProductRepository) claimPending(tx *sql.Tx, limit int) (messages []*models.OutboxMessage, err error) {
+	query := `SELECT id, event_id, event_type, aggregate_id, payload, occurred_at, attempts, COALESCE(last_error, ''), next_attempt_at
+		FROM outbox
+		WHERE dispatched_at IS NULL AND next_attempt_at <= now()
+		ORDER BY id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED`
+	rows, err := tx.QueryContext(context.Background(), query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = errors.Join(err, rows.Close())
+	}()
+
+	for rows.Next() {
+		var (
+			msg       models.OutboxMessage
+			eventType string
+			payload   []byte
+		)
+		if scanErr := rows.Scan(&msg.ID, &msg.Event.ID, &eventType, &msg.Event.ProductID, &payload,
+			&msg.Event.OccurredAt, &msg.Attempts, &msg.LastError, &msg.NextAttemptAt); scanErr != nil {
+			return nil, scanErr
+		}
+		msg.Event.Type = models.EventType(eventType)
+		if err := json.Unmarshal(payload, &msg.Event.Product); err != nil {
+			return nil, err
+		}
+		messages = append(messages, &msg)
+	}
+
+	err = rows.Err()
+	return messages, err
+}
@@ -0,0 +1,278 @@
+package postgresdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+var _ ports.ProductImportRepository = (*PostgresProductRepository)(nil)
+
+// ExistingProducts busca, em uma única consulta, os produtos dos IDs informados que já existem.
+func (r *PostgresProductRepository) ExistingProducts(ids []string) (existing map[string]*models.Product, err error) {
+	query := "SELECT id, name, price, created_at, updated_at FROM products WHERE id = ANY($1)"
+	rows, err := r.db.QueryContext(context.Background(), query, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = errors.Join(err, rows.Close())
+	}()
+
+	existing = make(map[string]*models.Product)
+	for rows.Next() {
+		var product models.Product
+		if scanErr := rows.Scan(&product.ID, &product.Name, &product.Price, &product.CreatedAt, &product.UpdatedAt); scanErr != nil {
+			return nil, scanErr
+		}
+		existing[product.ID] = &product
+	}
+
+	err = rows.Err()
+	return existing, err
+}
+
+// importedRow é a linha de products gravada pela importação, com o estado anterior dos produtos atualizados.
+type importedRow struct {
+	product  models.Product
+	previous *models.Product
+}
+
+// ImportProducts grava os produtos em uma transação usando COPY FROM, que no PostgreSQL é muito mais
+// rápido do que um INSERT por linha: os produtos são copiados para uma tabela temporária e aplicados a
+// products com um único INSERT ... ON CONFLICT. Os registros do outbox, do feed de alterações, das
+// revisões e da auditoria também são gravados com COPY, e as notificações com um único pg_notify sobre um array.
+// O lock em change_sequence, o mesmo adquirido por cada mutação individual, serializa a importação
+// com as demais escritas e mantém o feed de alterações em ordem de confirmação.
+func (r *PostgresProductRepository) ImportProducts(products []*models.Product, expected map[string]*models.Product, mode models.ImportMode, mutation models.Mutation) (results []models.ImportedProduct, err error) {
+	ctx := context.Background()
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = errors.Join(err, conn.Close())
+	}()
+
+	err = conn.Raw(func(driverConn any) error {
+		pgxConn := driverConn.(*stdlib.Conn).Conn()
+		return pgx.BeginFunc(ctx, pgxConn, func(tx pgx.Tx) error {
+			results, err = importProducts(ctx, tx, products, expected, mode, mutation)
+			return err
+		})
+	})
+	return results, err
+}
+
+func importProducts(ctx context.Context, tx pgx.Tx, products []*models.Product, expected map[string]*models.Product, mode models.ImportMode, mutation models.Mutation) ([]models.ImportedProduct, error) {
+	const staging = `CREATE TEMP TABLE product_import (
+		position  INTEGER NOT NULL,
+		id        TEXT PRIMARY KEY,
+		name      TEXT NOT NULL,
+		price     NUMERIC(10, 2) NOT NULL
+	) ON COMMIT DROP`
+	if _, err := tx.Exec(ctx, staging); err != nil {
+		return nil, err
+	}
+
+	_, err := tx.CopyFrom(ctx, pgx.Identifier{"product_import"}, []string{"position", "id", "name", "price"},
+		pgx.CopyFromSlice(len(products), func(i int) ([]any, error) {
+			return []any{i, products[i].ID, products[i].Name, products[i].Price}, nil
+		}))
+	if err != nil {
+		return nil, err
+	}
+
+	var sequence int64
+	if err := tx.QueryRow(ctx, "SELECT value FROM change_sequence FOR UPDATE").Scan(&sequence); err != nil {
+		return nil, err
+	}
+
+	if mode == models.ImportModeCreate {
+		var conflict bool
+		query := "SELECT EXISTS (SELECT 1 FROM product_import JOIN products USING (id))"
+		if err := tx.QueryRow(ctx, query).Scan(&conflict); err != nil {
+			return nil, err
+		}
+		if conflict {
+			return nil, models.ErrProductAlreadyExists
+		}
+	}
+
+	rows, err := upsertImportedProducts(ctx, tx, len(products))
+	if err != nil {
+		return nil, err
+	}
+	// O lock em change_sequence impede outras mutações desde antes do upsert, então o estado anterior das
+	// linhas é o que estava confirmado; se divergir do esperado, a transação é desfeita.
+	for i := range rows {
+		if expected != nil && models.ProductModified(expected[rows[i].product.ID], rows[i].previous) {
+			return nil, models.ErrProductModified
+		}
+	}
+
+	events := make([]models.ProductEvent, len(rows))
+	audit := make([]*models.AuditEntry, len(rows))
+	results := make([]models.ImportedProduct, len(rows))
+	for i := range rows {
+		eventType := models.EventProductCreated
+		if rows[i].previous != nil {
+			eventType = models.EventProductUpdated
+		}
+		events[i] = models.NewProductEvent(eventType, &rows[i].product)
+		audit[i] = models.NewEventAuditEntry(events[i], rows[i].previous, mutation)
+		results[i] = models.ImportedProduct{Before: rows[i].previous, After: &rows[i].product}
+	}
+
+	if _, err := tx.Exec(ctx, "UPDATE change_sequence SET value = $1", sequence+int64(len(events))); err != nil {
+		return nil, err
+	}
+	if err := copyImportEvents(ctx, tx, events, audit, sequence); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// upsertImportedProducts aplica a tabela temporária a products e devolve as linhas gravadas na ordem do
+// arquivo. O estado anterior é lido com FOR UPDATE no mesmo comando, para compor a auditoria.
+func upsertImportedProducts(ctx context.Context, tx pgx.Tx, count int) ([]importedRow, error) {
+	query := `WITH previous AS (
+			SELECT p.id, p.name, p.price, p.created_at, p.updated_at
+			FROM products p JOIN product_import i USING (id)
+			FOR UPDATE OF p
+		), upserted AS (
+			INSERT INTO products (id, name, price, created_at, updated_at)
+			SELECT id, name, price, $1, $1 FROM product_import
+			ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name, price = EXCLUDED.price, updated_at = EXCLUDED.updated_at
+			RETURNING id, name, price, created_at, updated_at
+		)
+		SELECT u.id, u.name, u.price, u.created_at, u.updated_at,
+			p.id IS NOT NULL, p.name, p.price, p.created_at, p.updated_at
+		FROM upserted u
+		JOIN product_import i USING (id)
+		LEFT JOIN previous p USING (id)
+		ORDER BY i.position`
+	rows, err := tx.Query(ctx, query, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	imported := make([]importedRow, 0, count)
+	for rows.Next() {
+		var (
+			row      importedRow
+			existed  bool
+			previous struct {
+				name      *string
+				price     *float64
+				createdAt *time.Time
+				updatedAt *time.Time
+			}
+		)
+		err := rows.Scan(&row.product.ID, &row.product.Name, &row.product.Price, &row.product.CreatedAt, &row.product.UpdatedAt,
+			&existed, &previous.name, &previous.price, &previous.createdAt, &previous.updatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if existed {
+			row.previous = &models.Product{
+				ID:        row.product.ID,
+				Name:      *previous.name,
+				Price:     *previous.price,
+				CreatedAt: *previous.createdAt,
+				UpdatedAt: *previous.updatedAt,
+			}
+		}
+		imported = append(imported, row)
+	}
+	return imported, rows.Err()
+}
+
+// copyImportEvents grava os efeitos derivados dos eventos da importação e seus registros de auditoria,
+// com as sequências do feed atribuídas a partir de lastSequence, e agenda as notificações para a
+// confirmação da transação.
+func copyImportEvents(ctx context.Context, tx pgx.Tx, events []models.ProductEvent, audit []*models.AuditEntry, lastSequence int64) error {
+	revisions, err := lastRevisions(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		outboxRows   = make([][]any, len(events))
+		changeRows   = make([][]any, len(events))
+		revisionRows = make([][]any, len(events))
+		auditRows    = make([][]any, len(events))
+		payloads     = make([]string, len(events))
+	)
+	for i, event := range events {
+		product, err := json.Marshal(event.Product)
+		if err != nil {
+			return err
+		}
+		notification, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		change := models.NewProductChange(lastSequence+int64(i)+1, event)
+		revision := models.NewProductRevision(revisions[event.ProductID]+1, event)
+		outboxRows[i] = []any{event.ID, string(event.Type), event.ProductID, product, event.OccurredAt}
+		changeRows[i] = []any{change.Sequence, string(change.Type), change.ProductID, product, change.ChangedAt}
+		revisionRows[i] = []any{revision.ProductID, revision.Revision, product, revision.Deleted, revision.ValidFrom}
+		if auditRows[i], err = auditValues(audit[i]); err != nil {
+			return err
+		}
+		payloads[i] = string(notification)
+	}
+
+	copies := []struct {
+		table   string
+		columns []string
+		rows    [][]any
+	}{
+		{"outbox", []string{"event_id", "event_type", "aggregate_id", "payload", "occurred_at"}, outboxRows},
+		{"product_changes", []string{"seq", "event_type", "product_id", "product", "changed_at"}, changeRows},
+		{"product_revisions", []string{"product_id", "revision", "product", "deleted", "valid_from"}, revisionRows},
+		{"audit_log", auditColumns, auditRows},
+	}
+	for _, c := range copies {
+		if _, err := tx.CopyFrom(ctx, pgx.Identifier{c.table}, c.columns, pgx.CopyFromRows(c.rows)); err != nil {
+			return fmt.Errorf("copy %s: %w", c.table, err)
+		}
+	}
+
+	_, err = tx.Exec(ctx, "SELECT pg_notify($1, payload) FROM unnest($2::text[]) AS payload", productEventsChannel, payloads)
+	return err
+}
+
+// lastRevisions busca o número da última revisão de cada produto importado; produtos sem histórico ficam fora do mapa.
+func lastRevisions(ctx context.Context, tx pgx.Tx) (map[string]int, error) {
+	query := `SELECT product_id, MAX(revision) FROM product_revisions
+		WHERE product_id IN (SELECT id FROM product_import) GROUP BY product_id`
+	rows, err := tx.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := make(map[string]int)
+	for rows.Next() {
+		var (
+			productID string
+			revision  int
+		)
+		if err := rows.Scan(&productID, &revision); err != nil {
+			return nil, err
+		}
+		revisions[productID] = revision
+	}
+	return revisions, rows.Err()
+}
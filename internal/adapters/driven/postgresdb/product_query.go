@@ -0,0 +1,234 @@
+package postgresdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+var _ ports.ProductQueryRepository = (*PostgresProductRepository)(nil)
+
+// periodExpressions traduz cada agrupamento para o rótulo do período calculado no banco, em UTC.
+var periodExpressions = map[models.StatsGrouping]string{
+	models.StatsGroupDay:   "to_char(created_at AT TIME ZONE 'UTC', 'YYYY-MM-DD')",
+	models.StatsGroupMonth: "to_char(created_at AT TIME ZONE 'UTC', 'YYYY-MM')",
+}
+
+// productColumns associa cada campo de produto à sua coluna e ao destino da leitura, na ordem do modelo.
+var productColumns = []struct {
+	field  models.ProductField
+	column string
+	target func(product *models.Product) any
+}{
+	{models.ProductFieldID, "id", func(p *models.Product) any { return &p.ID }},
+	{models.ProductFieldName, "name", func(p *models.Product) any { return &p.Name }},
+	{models.ProductFieldPrice, "price", func(p *models.Product) any { return &p.Price }},
+	{models.ProductFieldCreatedAt, "created_at", func(p *models.Product) any { return &p.CreatedAt }},
+	{models.ProductFieldUpdatedAt, "updated_at", func(p *models.Product) any { return &p.UpdatedAt }},
+}
+
+// projectionColumns retorna a lista de colunas da projeção, para o SELECT, e os destinos do Scan de cada
+// linha, na mesma ordem.
+func projectionColumns(projection models.ProductProjection) (string, func(product *models.Product) []any) {
+	var columns []string
+	var targets []func(product *models.Product) any
+	for _, column := range productColumns {
+		if projection.Includes(column.field) {
+			columns = append(columns, column.column)
+			targets = append(targets, column.target)
+		}
+	}
+	return strings.Join(columns, ", "), func(product *models.Product) []any {
+		dest := make([]any, len(targets))
+		for i, target := range targets {
+			dest[i] = target(product)
+		}
+		return dest
+	}
+}
+
+// Find busca os produtos que atendem ao filtro, ordenados pela data de criação. Apenas as colunas da
+// projeção são lidas.
+func (r *PostgresProductRepository) Find(filter models.ProductFilter, projection models.ProductProjection) (products []*models.Product, err error) {
+	where, args := productFilterClause(filter)
+	columns, dest := projectionColumns(projection)
+	query := "SELECT " + columns + " FROM products" + where + " ORDER BY created_at, id"
+	rows, err := r.db.QueryContext(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = errors.Join(err, rows.Close())
+	}()
+
+	products = []*models.Product{}
+	for rows.Next() {
+		var product models.Product
+		if scanErr := rows.Scan(dest(&product)...); scanErr != nil {
+			return nil, scanErr
+		}
+		products = append(products, &product)
+	}
+
+	err = rows.Err()
+	return products, err
+}
+
+// exportBatchSize é a quantidade de linhas lidas do cursor a cada FETCH.
+const exportBatchSize = 500
+
+// ForEach percorre os produtos com um cursor no servidor, lendo exportBatchSize linhas por vez, para que
+// tabelas grandes sejam exportadas com memória constante. A transação é somente leitura e REPEATABLE READ,
+// então todos os lotes vêm do mesmo snapshot, mesmo com escritas concorrentes.
+func (r *PostgresProductRepository) ForEach(filter models.ProductFilter, fn func(product *models.Product) error) (err error) {
+	ctx := context.Background()
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, ignoreTxDone(tx.Rollback()))
+	}()
+
+	where, args := productFilterClause(filter)
+	query := "DECLARE product_export NO SCROLL CURSOR FOR SELECT id, name, price, created_at, updated_at FROM products" +
+		where + " ORDER BY created_at, id"
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	fetch := fmt.Sprintf("FETCH %d FROM product_export", exportBatchSize)
+	for {
+		batch, err := fetchProducts(ctx, tx, fetch)
+		if err != nil {
+			return err
+		}
+		// O lote é lido por completo antes de chamar fn, liberando a conexão para o próximo FETCH.
+		for _, product := range batch {
+			if err := fn(product); err != nil {
+				return err
+			}
+		}
+		if len(batch) < exportBatchSize {
+			return nil
+		}
+	}
+}
+
+func fetchProducts(ctx context.Context, tx *sql.Tx, query string) (products []*models.Product, err error) {
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = errors.Join(err, rows.Close())
+	}()
+
+	for rows.Next() {
+		var product models.Product
+		if scanErr := rows.Scan(&product.ID, &product.Name, &product.Price, &product.CreatedAt, &product.UpdatedAt); scanErr != nil {
+			return nil, scanErr
+		}
+		products = append(products, &product)
+	}
+
+	err = rows.Err()
+	return products, err
+}
+
+// Stats calcula as estatísticas com funções de agregação do PostgreSQL. Com agrupamento, os totais e os
+// períodos vêm da mesma consulta via GROUPING SETS; a linha de totais é a que tem o período nulo.
+func (r *PostgresProductRepository) Stats(filter models.ProductFilter, groupBy models.StatsGrouping) (stats *models.ProductStats, err error) {
+	const aggregates = `count(*), min(price)::float8, max(price)::float8, avg(price)::float8,
+		percentile_cont(0.5) WITHIN GROUP (ORDER BY price)`
+
+	where, args := productFilterClause(filter)
+	period, grouped := periodExpressions[groupBy]
+	query := "SELECT NULL::text, " + aggregates + " FROM products" + where
+	if grouped {
+		query = fmt.Sprintf("SELECT %s, %s FROM products%s GROUP BY GROUPING SETS ((), (%s)) ORDER BY 1 NULLS FIRST",
+			period, aggregates, where, period)
+	}
+
+	rows, err := r.db.QueryContext(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = errors.Join(err, rows.Close())
+	}()
+
+	stats = &models.ProductStats{GroupBy: groupBy}
+	if grouped {
+		stats.Groups = []models.ProductStatsGroup{}
+	}
+	for rows.Next() {
+		var (
+			periodLabel                               sql.NullString
+			summary                                   models.PriceSummary
+			minPrice, maxPrice, avgPrice, medianPrice sql.NullFloat64
+		)
+		if scanErr := rows.Scan(&periodLabel, &summary.Count, &minPrice, &maxPrice, &avgPrice, &medianPrice); scanErr != nil {
+			return nil, scanErr
+		}
+		summary.MinPrice = nullableFloat(minPrice)
+		summary.MaxPrice = nullableFloat(maxPrice)
+		summary.AvgPrice = nullableFloat(avgPrice)
+		summary.MedianPrice = nullableFloat(medianPrice)
+
+		if periodLabel.Valid {
+			stats.Groups = append(stats.Groups, models.ProductStatsGroup{Period: periodLabel.String, PriceSummary: summary})
+		} else {
+			stats.PriceSummary = summary
+		}
+	}
+
+	err = rows.Err()
+	return stats, err
+}
+
+// productFilterClause monta a cláusula WHERE do filtro de produtos e seus argumentos.
+func productFilterClause(filter models.ProductFilter) (string, []any) {
+	var (
+		conditions []string
+		args       []any
+	)
+	addCondition := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.NameContains != "" {
+		// strpos evita interpretar % e _ do texto informado como curingas de LIKE.
+		addCondition("strpos(lower(name), lower($%d)) > 0", filter.NameContains)
+	}
+	if filter.MinPrice != nil {
+		addCondition("price >= $%d", *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		addCondition("price <= $%d", *filter.MaxPrice)
+	}
+	if !filter.CreatedFrom.IsZero() {
+		addCondition("created_at >= $%d", filter.CreatedFrom)
+	}
+	if !filter.CreatedTo.IsZero() {
+		addCondition("created_at < $%d", filter.CreatedTo)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func nullableFloat(value sql.NullFloat64) *float64 {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Float64
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"log"
+	"time"
 
 	"github.com/danielrios/product-service-go/internal/core/models"
 	"github.com/danielrios/product-service-go/internal/core/ports"
@@ -17,8 +18,9 @@ type PostgresProductRepository struct {
 	db *sql.DB
 }
 
-// NewPostgresProductRepository cria uma nova instância do repositório, conectando ao banco.
-func NewPostgresProductRepository(dataSourceName string) (*PostgresProductRepository, error) {
+// Connect abre um pool de conexões com o PostgreSQL e verifica se o banco está acessível.
+// O pool retornado é compartilhado pelos repositórios deste pacote.
+func Connect(dataSourceName string) (*sql.DB, error) {
 	db, err := sql.Open("pgx", dataSourceName)
 	if err != nil {
 		return nil, err
@@ -26,40 +28,48 @@ func NewPostgresProductRepository(dataSourceName string) (*PostgresProductReposi
 
 	// Verifica se a conexão com o banco de dados está realmente funcionando.
 	if err = db.Ping(); err != nil {
-		return nil, err
+		return nil, errors.Join(err, db.Close())
 	}
 
 	log.Println("Conexão com o banco de dados PostgreSQL estabelecida com sucesso.")
-	return &PostgresProductRepository{db: db}, nil
+	return db, nil
+}
+
+// NewPostgresProductRepository cria uma nova instância do repositório sobre o pool de conexões informado.
+func NewPostgresProductRepository(db *sql.DB) *PostgresProductRepository {
+	return &PostgresProductRepository{db: db}
 }
 
 // Garante em tempo de compilação que PostgresProductRepository implementa a interface.
 var _ ports.ProductRepository = (*PostgresProductRepository)(nil)
 
 // Add adiciona um novo produto ao banco de dados.
-func (r *PostgresProductRepository) Add(product *models.Product) error {
-	query := "INSERT INTO products (id, name, price, created_at) VALUES ($1, $2, $3, $4)"
-	_, err := r.db.ExecContext(context.Background(), query, product.ID, product.Name, product.Price, product.CreatedAt)
-
-	if err != nil {
-		// Verifica se o erro é de violação de chave única (produto já existe).
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // 23505 é o código para unique_violation
-			return models.ErrProductAlreadyExists
+// O produto e o evento correspondente no outbox são gravados na mesma transação.
+func (r *PostgresProductRepository) Add(product *models.Product, mutation models.Mutation) error {
+	return r.withTx(func(tx *sql.Tx) error {
+		query := "INSERT INTO products (id, name, price, created_at, updated_at) VALUES ($1, $2, $3, $4, $5)"
+		_, err := tx.ExecContext(context.Background(), query, product.ID, product.Name, product.Price, product.CreatedAt, product.UpdatedAt)
+
+		if err != nil {
+			// Verifica se o erro é de violação de chave única (produto já existe).
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" { // 23505 é o código para unique_violation
+				return models.ErrProductAlreadyExists
+			}
+			return err
 		}
-		return err
-	}
 
-	return nil
+		return r.recordEvent(tx, models.NewProductEvent(models.EventProductCreated, product), nil, mutation)
+	})
 }
 
 // GetByID busca um produto pelo seu ID no banco de dados.
 func (r *PostgresProductRepository) GetByID(id string) (*models.Product, error) {
-	query := "SELECT id, name, price, created_at FROM products WHERE id = $1"
+	query := "SELECT id, name, price, created_at, updated_at FROM products WHERE id = $1"
 	row := r.db.QueryRowContext(context.Background(), query, id)
 
 	var product models.Product
-	err := row.Scan(&product.ID, &product.Name, &product.Price, &product.CreatedAt)
+	err := row.Scan(&product.ID, &product.Name, &product.Price, &product.CreatedAt, &product.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, models.ErrProductNotFound
@@ -72,7 +82,7 @@ func (r *PostgresProductRepository) GetByID(id string) (*models.Product, error)
 
 // GetAll busca todos os produtos no banco de dados.
 func (r *PostgresProductRepository) GetAll() (products []*models.Product, err error) {
-	query := "SELECT id, name, price, created_at FROM products"
+	query := "SELECT id, name, price, created_at, updated_at FROM products"
 	rows, err := r.db.QueryContext(context.Background(), query)
 	if err != nil {
 		return nil, err
@@ -85,7 +95,7 @@ func (r *PostgresProductRepository) GetAll() (products []*models.Product, err er
 	products = []*models.Product{} // Evita retornar um slice nulo em caso de sucesso sem resultados.
 	for rows.Next() {
 		var product models.Product
-		if scanErr := rows.Scan(&product.ID, &product.Name, &product.Price, &product.CreatedAt); scanErr != nil {
+		if scanErr := rows.Scan(&product.ID, &product.Name, &product.Price, &product.CreatedAt, &product.UpdatedAt); scanErr != nil {
 			return nil, scanErr
 		}
 		products = append(products, &product)
@@ -96,46 +106,77 @@ func (r *PostgresProductRepository) GetAll() (products []*models.Product, err er
 	return products, err
 }
 
-// Update atualiza um produto existente no banco de dados.
-func (r *PostgresProductRepository) Update(product *models.Product) error {
-	query := "UPDATE products SET name = $1, price = $2 WHERE id = $3"
-	result, err := r.db.ExecContext(context.Background(), query, product.Name, product.Price, product.ID)
-	if err != nil {
-		return err
-	}
+// Update atualiza um produto existente no banco de dados. A linha é bloqueada antes da alteração para que
+// a comparação com o estado esperado e o estado anterior registrado na auditoria sejam exatamente o que a
+// atualização substituiu.
+func (r *PostgresProductRepository) Update(product, expected *models.Product, mutation models.Mutation) error {
+	return r.withTx(func(tx *sql.Tx) error {
+		query := "SELECT id, name, price, created_at, updated_at FROM products WHERE id = $1 FOR UPDATE"
+		row := tx.QueryRowContext(context.Background(), query, product.ID)
+
+		var previous models.Product
+		if err := row.Scan(&previous.ID, &previous.Name, &previous.Price, &previous.CreatedAt, &previous.UpdatedAt); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return models.ErrProductNotFound
+			}
+			return err
+		}
+		if expected != nil && models.ProductModified(expected, &previous) {
+			return models.ErrProductModified
+		}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return err
-	}
+		query = "UPDATE products SET name = $1, price = $2, updated_at = $3 WHERE id = $4 RETURNING id, name, price, created_at, updated_at"
+		row = tx.QueryRowContext(context.Background(), query, product.Name, product.Price, time.Now(), product.ID)
 
-	if rowsAffected == 0 {
-		return models.ErrProductNotFound
-	}
+		var updated models.Product
+		if err := row.Scan(&updated.ID, &updated.Name, &updated.Price, &updated.CreatedAt, &updated.UpdatedAt); err != nil {
+			return err
+		}
 
-	return nil
+		return r.recordEvent(tx, models.NewProductEvent(models.EventProductUpdated, &updated), &previous, mutation)
+	})
 }
 
 // Delete remove um produto do banco de dados pelo seu ID.
-func (r *PostgresProductRepository) Delete(id string) error {
-	query := "DELETE FROM products WHERE id = $1"
-	result, err := r.db.ExecContext(context.Background(), query, id)
-	if err != nil {
-		return err
-	}
+func (r *PostgresProductRepository) Delete(id string, mutation models.Mutation) error {
+	return r.withTx(func(tx *sql.Tx) error {
+		query := "DELETE FROM products WHERE id = $1 RETURNING id, name, price, created_at, updated_at"
+		row := tx.QueryRowContext(context.Background(), query, id)
+
+		var deleted models.Product
+		if err := row.Scan(&deleted.ID, &deleted.Name, &deleted.Price, &deleted.CreatedAt, &deleted.UpdatedAt); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return models.ErrProductNotFound
+			}
+			return err
+		}
+
+		return r.recordEvent(tx, models.NewProductEvent(models.EventProductDeleted, &deleted), &deleted, mutation)
+	})
+}
 
-	rowsAffected, err := result.RowsAffected()
+// withTx executa fn dentro de uma transação, confirmando-a em caso de sucesso e desfazendo-a em caso de erro.
+func (r *PostgresProductRepository) withTx(fn func(tx *sql.Tx) error) (err error) {
+	tx, err := r.db.BeginTx(context.Background(), nil)
 	if err != nil {
 		return err
 	}
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, ignoreTxDone(tx.Rollback()))
+		}
+	}()
 
-	if rowsAffected == 0 {
-		return models.ErrProductNotFound
+	if err = fn(tx); err != nil {
+		return err
 	}
-
-	return nil
+	return tx.Commit()
 }
 
-func (r *PostgresProductRepository) Close() error {
-	return r.db.Close()
+// ignoreTxDone descarta o erro sql.ErrTxDone, retornado ao desfazer uma transação já finalizada.
+func ignoreTxDone(err error) error {
+	if errors.Is(err, sql.ErrTxDone) {
+		return nil
+	}
+	return err
 }
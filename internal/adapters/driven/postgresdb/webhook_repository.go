@@ -0,0 +1,234 @@
+package postgresdb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+// PostgresWebhookRepository é a implementação do repositório de webhooks para PostgreSQL.
+type PostgresWebhookRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresWebhookRepository cria uma nova instância do repositório sobre o pool de conexões informado.
+func NewPostgresWebhookRepository(db *sql.DB) *PostgresWebhookRepository {
+	return &PostgresWebhookRepository{db: db}
+}
+
+// Garante em tempo de compilação que PostgresWebhookRepository implementa a interface.
+var _ ports.WebhookRepository = (*PostgresWebhookRepository)(nil)
+
+const deliveryColumns = "id, subscription_id, event, status, attempts, next_attempt_at, last_attempt_at, response_status, last_error, created_at"
+
+// AddSubscription adiciona uma nova inscrição ao banco de dados.
+func (r *PostgresWebhookRepository) AddSubscription(sub *models.WebhookSubscription) error {
+	eventTypes, err := json.Marshal(sub.EventTypes)
+	if err != nil {
+		return err
+	}
+
+	query := "INSERT INTO webhook_subscriptions (id, url, event_types, secret, created_at) VALUES ($1, $2, $3, $4, $5)"
+	_, err = r.db.ExecContext(context.Background(), query, sub.ID, sub.URL, eventTypes, sub.Secret, sub.CreatedAt)
+	return err
+}
+
+// GetSubscription busca uma inscrição pelo ID.
+func (r *PostgresWebhookRepository) GetSubscription(id string) (*models.WebhookSubscription, error) {
+	query := "SELECT id, url, event_types, secret, created_at FROM webhook_subscriptions WHERE id = $1"
+	sub, err := scanSubscription(r.db.QueryRowContext(context.Background(), query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrWebhookNotFound
+	}
+	return sub, err
+}
+
+// ListSubscriptions retorna todas as inscrições, ordenadas pela data de criação.
+func (r *PostgresWebhookRepository) ListSubscriptions() (subs []*models.WebhookSubscription, err error) {
+	query := "SELECT id, url, event_types, secret, created_at FROM webhook_subscriptions ORDER BY created_at"
+	rows, err := r.db.QueryContext(context.Background(), query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = errors.Join(err, rows.Close())
+	}()
+
+	subs = []*models.WebhookSubscription{}
+	for rows.Next() {
+		sub, scanErr := scanSubscription(rows)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+		subs = append(subs, sub)
+	}
+
+	err = rows.Err()
+	return subs, err
+}
+
+// UpdateSubscription atualiza uma inscrição existente.
+func (r *PostgresWebhookRepository) UpdateSubscription(sub *models.WebhookSubscription) error {
+	eventTypes, err := json.Marshal(sub.EventTypes)
+	if err != nil {
+		return err
+	}
+
+	query := "UPDATE webhook_subscriptions SET url = $1, event_types = $2, secret = $3 WHERE id = $4"
+	result, err := r.db.ExecContext(context.Background(), query, sub.URL, eventTypes, sub.Secret, sub.ID)
+	return requireAffected(result, err, models.ErrWebhookNotFound)
+}
+
+// DeleteSubscription remove uma inscrição. As entregas são removidas em cascata pelo banco.
+func (r *PostgresWebhookRepository) DeleteSubscription(id string) error {
+	query := "DELETE FROM webhook_subscriptions WHERE id = $1"
+	result, err := r.db.ExecContext(context.Background(), query, id)
+	return requireAffected(result, err, models.ErrWebhookNotFound)
+}
+
+// EnqueueDeliveries grava as entregas em uma única transação, ignorando pares evento/inscrição repetidos.
+func (r *PostgresWebhookRepository) EnqueueDeliveries(deliveries []*models.WebhookDelivery) error {
+	tx, err := r.db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO webhook_deliveries (id, subscription_id, event_id, event, status, next_attempt_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (subscription_id, event_id) DO NOTHING`
+	for _, delivery := range deliveries {
+		event, err := json.Marshal(delivery.Event)
+		if err != nil {
+			return errors.Join(err, tx.Rollback())
+		}
+		if _, err := tx.ExecContext(context.Background(), query, delivery.ID, delivery.SubscriptionID, delivery.Event.ID,
+			event, string(delivery.Status), delivery.NextAttemptAt, delivery.CreatedAt); err != nil {
+			return errors.Join(err, tx.Rollback())
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ClaimDueDeliveries reserva entregas vencidas com FOR UPDATE SKIP LOCKED e adia sua próxima tentativa em lease,
+// de forma que várias instâncias possam entregar em paralelo sem duplicidade.
+func (r *PostgresWebhookRepository) ClaimDueDeliveries(limit int, lease time.Duration) (deliveries []*models.WebhookDelivery, err error) {
+	query := `UPDATE webhook_deliveries SET next_attempt_at = now() + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= now()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + deliveryColumns
+	return r.queryDeliveries(query, limit, lease.Seconds())
+}
+
+// GetDelivery busca uma entrega pelo ID.
+func (r *PostgresWebhookRepository) GetDelivery(id string) (*models.WebhookDelivery, error) {
+	query := "SELECT " + deliveryColumns + " FROM webhook_deliveries WHERE id = $1"
+	delivery, err := scanDelivery(r.db.QueryRowContext(context.Background(), query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrWebhookDeliveryNotFound
+	}
+	return delivery, err
+}
+
+// UpdateDelivery registra o resultado de uma tentativa de entrega.
+func (r *PostgresWebhookRepository) UpdateDelivery(delivery *models.WebhookDelivery) error {
+	query := `UPDATE webhook_deliveries
+		SET status = $1, attempts = $2, next_attempt_at = $3, last_attempt_at = $4, response_status = $5, last_error = $6
+		WHERE id = $7`
+	result, err := r.db.ExecContext(context.Background(), query, string(delivery.Status), delivery.Attempts,
+		delivery.NextAttemptAt, delivery.LastAttemptAt, delivery.ResponseStatus, delivery.LastError, delivery.ID)
+	return requireAffected(result, err, models.ErrWebhookDeliveryNotFound)
+}
+
+// ListDeliveries retorna as entregas mais recentes da inscrição.
+func (r *PostgresWebhookRepository) ListDeliveries(subscriptionID string, limit int) ([]*models.WebhookDelivery, error) {
+	query := "SELECT " + deliveryColumns + " FROM webhook_deliveries WHERE subscription_id = $1 ORDER BY created_at DESC LIMIT $2"
+	return r.queryDeliveries(query, subscriptionID, limit)
+}
+
+// queryDeliveries executa uma consulta que retorna entregas.
+func (r *PostgresWebhookRepository) queryDeliveries(query string, args ...any) (deliveries []*models.WebhookDelivery, err error) {
+	rows, err := r.db.QueryContext(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = errors.Join(err, rows.Close())
+	}()
+
+	deliveries = []*models.WebhookDelivery{}
+	for rows.Next() {
+		delivery, scanErr := scanDelivery(rows)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	err = rows.Err()
+	return deliveries, err
+}
+
+// rowScanner abstrai *sql.Row e *sql.Rows para reaproveitar as funções de leitura.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSubscription(row rowScanner) (*models.WebhookSubscription, error) {
+	var (
+		sub        models.WebhookSubscription
+		eventTypes []byte
+	)
+	if err := row.Scan(&sub.ID, &sub.URL, &eventTypes, &sub.Secret, &sub.CreatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(eventTypes, &sub.EventTypes); err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+func scanDelivery(row rowScanner) (*models.WebhookDelivery, error) {
+	var (
+		delivery models.WebhookDelivery
+		event    []byte
+		status   string
+	)
+	if err := row.Scan(&delivery.ID, &delivery.SubscriptionID, &event, &status, &delivery.Attempts, &delivery.NextAttemptAt,
+		&delivery.LastAttemptAt, &delivery.ResponseStatus, &delivery.LastError, &delivery.CreatedAt); err != nil {
+		return nil, err
+	}
+	delivery.Status = models.DeliveryStatus(status)
+	if err := json.Unmarshal(event, &delivery.Event); err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// requireAffected converte a ausência de linhas afetadas em notFound.
+func requireAffected(result sql.Result, err error, notFound error) error {
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return notFound
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package webhookclient
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+// HTTPSender é um Adaptador de Saída que envia os callbacks de webhooks via HTTP POST.
+type HTTPSender struct {
+	client *http.Client
+}
+
+// NewHTTPSender cria um novo HTTPSender com o timeout informado por requisição.
+func NewHTTPSender(timeout time.Duration) *HTTPSender {
+	return &HTTPSender{
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+var _ ports.WebhookSender = (*HTTPSender)(nil)
+
+// Send envia o callback e retorna o código de status da resposta.
+func (s *HTTPSender) Send(ctx context.Context, req ports.WebhookRequest) (int, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, req.URL, bytes.NewReader(req.Body))
+	if err != nil {
+		return 0, err
+	}
+	for name, value := range req.Headers {
+		httpReq.Header.Set(name, value)
+	}
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// Descarta (até um limite) o corpo da resposta para permitir o reaproveitamento da conexão.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,116 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/application"
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/go-chi/chi/v5"
+)
+
+// APIKeyHandler é o Adaptador de Entrada HTTP para o gerenciamento das chaves de API.
+type APIKeyHandler struct {
+	service *application.APIKeyService
+}
+
+// NewAPIKeyHandler cria e retorna uma nova instância de APIKeyHandler.
+func NewAPIKeyHandler(service *application.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		service: service,
+	}
+}
+
+// APIKeyRequest é o corpo da criação de uma chave de API. Roles são os papéis do principal autenticado pela
+// chave, que definem suas permissões no catálogo. Sem ExpiresAt, a chave não expira.
+type APIKeyRequest struct {
+	Name      string     `json:"Name" required:"true"`
+	Scopes    []string   `json:"Scopes" required:"true"`
+	Roles     []string   `json:"Roles" required:"true"`
+	ExpiresAt *time.Time `json:"ExpiresAt"`
+}
+
+// APIKeyResponse é a representação de uma chave de API. Key, a chave completa, só é preenchida na criação;
+// o hash do segredo nunca é exposto.
+type APIKeyResponse struct {
+	ID         string     `json:"ID"`
+	Name       string     `json:"Name"`
+	Scopes     []string   `json:"Scopes"`
+	Roles      []string   `json:"Roles"`
+	CreatedBy  string     `json:"CreatedBy"`
+	CreatedAt  time.Time  `json:"CreatedAt"`
+	ExpiresAt  *time.Time `json:"ExpiresAt"`
+	LastUsedAt *time.Time `json:"LastUsedAt"`
+	RevokedAt  *time.Time `json:"RevokedAt"`
+	Key        string     `json:"Key,omitempty"`
+}
+
+func newAPIKeyResponse(key *models.APIKey) *APIKeyResponse {
+	return &APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Scopes:     key.Scopes,
+		Roles:      key.Roles,
+		CreatedBy:  key.CreatedBy,
+		CreatedAt:  key.CreatedAt,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+	}
+}
+
+// CreateAPIKeyHandler lida com a requisição POST /api-keys.
+func (h *APIKeyHandler) CreateAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	var request APIKeyRequest
+	if err := decodeRequestBody(r, &request); err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+
+	key, secret, err := h.service.CreateAPIKey(r.Context(), request.Name, request.Scopes, request.Roles, request.ExpiresAt)
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+
+	response := newAPIKeyResponse(key)
+	response.Key = secret
+	w.Header().Set("Cache-Control", "no-store")
+	writeResponse(w, r, http.StatusCreated, response)
+}
+
+// ListAPIKeysHandler lida com a requisição GET /api-keys.
+func (h *APIKeyHandler) ListAPIKeysHandler(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.service.ListAPIKeys()
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+
+	responses := make([]*APIKeyResponse, len(keys))
+	for i, key := range keys {
+		responses[i] = newAPIKeyResponse(key)
+	}
+	writeResponse(w, r, http.StatusOK, responses)
+}
+
+// GetAPIKeyHandler lida com a requisição GET /api-keys/{id}.
+func (h *APIKeyHandler) GetAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	key, err := h.service.GetAPIKey(chi.URLParam(r, "id"))
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, newAPIKeyResponse(key))
+}
+
+// RevokeAPIKeyHandler lida com a requisição DELETE /api-keys/{id}. A chave é revogada, mas continua listada.
+func (h *APIKeyHandler) RevokeAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.RevokeAPIKey(chi.URLParam(r, "id")); err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
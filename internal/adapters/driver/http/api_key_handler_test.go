@@ -0,0 +1,128 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	httpDriver "github.com/danielrios/product-service-go/internal/adapters/driver/http"
+
+	"github.com/danielrios/product-service-go/internal/adapters/driven/memdb"
+	"github.com/danielrios/product-service-go/internal/application"
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+func serveAuthorized(handler http.Handler, method, target, authorization, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Authorization", authorization)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAPIKeys(t *testing.T) {
+	repo := memdb.NewInMemoryProductRepository()
+	products := application.NewProductService(repo)
+	keys := application.NewAPIKeyService(memdb.NewInMemoryAPIKeyRepository(), application.DefaultAPIKeyConfig())
+	authorizer, err := application.NewAuthorizer(models.DefaultRolePermissions(), models.ProductFieldPolicy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := httpDriver.NewRouter(httpDriver.Handlers{
+		Products:    httpDriver.NewProductHandler(products),
+		ProductsV2:  httpDriver.NewProductHandler(products, httpDriver.WithAPIVersion(httpDriver.APIVersion2)),
+		APIKeys:     httpDriver.NewAPIKeyHandler(keys),
+		Idempotency: application.NewIdempotencyService(memdb.NewInMemoryIdempotencyRepository(), application.DefaultIdempotencyConfig()),
+	}, httpDriver.WithAuthentication(map[string]ports.CredentialVerifier{
+		httpDriver.AuthSchemeBearer: adminVerifier{},
+		httpDriver.AuthSchemeAPIKey: keys,
+	}), httpDriver.WithAuthorization(authorizer))
+
+	create := func(t *testing.T, body string) httpDriver.APIKeyResponse {
+		t.Helper()
+		rec := serveAuthorized(router, http.MethodPost, "/api-keys", "Bearer admin", body)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("Expected 201, got %d %s", rec.Code, rec.Body)
+		}
+		var key httpDriver.APIKeyResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &key); err != nil {
+			t.Fatal(err)
+		}
+		return key
+	}
+	reader := create(t, `{"Name": "batch reader", "Scopes": ["products:read"], "Roles": ["viewer"]}`)
+	writer := create(t, `{"Name": "batch writer", "Scopes": ["products:read", "products:write"], "Roles": ["editor"]}`)
+	editor := create(t, `{"Name": "editor with admin scope", "Scopes": ["admin"], "Roles": ["editor"]}`)
+	if !strings.HasPrefix(reader.Key, models.APIKeyPrefix+reader.ID+"_") || reader.CreatedBy != "admin" {
+		t.Errorf("Unexpected key %+v", reader)
+	}
+
+	t.Run("Scopes", func(t *testing.T) {
+		tests := []struct {
+			name       string
+			method     string
+			target     string
+			key        string
+			body       string
+			wantStatus int
+		}{
+			{"Read With Read Scope", http.MethodGet, "/v2/products", reader.Key, "", http.StatusOK},
+			{"Write Without Write Scope", http.MethodPost, "/v2/products", reader.Key, `{"id": "1", "name": "A", "price": 1}`, http.StatusForbidden},
+			{"Write With Write Scope", http.MethodPost, "/v2/products", writer.Key, `{"id": "1", "name": "A", "price": 1}`, http.StatusCreated},
+			{"Admin Routes", http.MethodGet, "/api-keys", writer.Key, "", http.StatusForbidden},
+			{"Admin Scope Without Permission", http.MethodGet, "/webhooks", editor.Key, "", http.StatusForbidden},
+			{"Editor Cannot Mint Admin Keys", http.MethodPost, "/api-keys", editor.Key, `{"Name": "escalated", "Scopes": ["admin"], "Roles": ["admin"]}`, http.StatusForbidden},
+			{"Wrong Secret", http.MethodGet, "/v2/products", writer.Key[:len(writer.Key)-2] + "xx", "", http.StatusUnauthorized},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if rec := serveAuthorized(router, tt.method, tt.target, "ApiKey "+tt.key, tt.body); rec.Code != tt.wantStatus {
+					t.Errorf("Expected %d, got %d %s", tt.wantStatus, rec.Code, rec.Body)
+				}
+			})
+		}
+	})
+
+	t.Run("Listing Hides Secrets", func(t *testing.T) {
+		rec := serveAuthorized(router, http.MethodGet, "/api-keys", "Bearer admin", "")
+		if strings.Contains(rec.Body.String(), reader.Key) || strings.Contains(rec.Body.String(), `"Key"`) {
+			t.Errorf("Expected no secrets in the listing, got %s", rec.Body)
+		}
+		var listed []httpDriver.APIKeyResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &listed); err != nil || len(listed) != 3 || listed[0].LastUsedAt == nil {
+			t.Errorf("Expected the three keys with the last use of the reader, got %s", rec.Body)
+		}
+	})
+
+	t.Run("Revocation", func(t *testing.T) {
+		if rec := serveAuthorized(router, http.MethodDelete, "/api-keys/"+reader.ID, "Bearer admin", ""); rec.Code != http.StatusNoContent {
+			t.Fatalf("Expected 204, got %d %s", rec.Code, rec.Body)
+		}
+		if rec := serveAuthorized(router, http.MethodGet, "/v2/products", "ApiKey "+reader.Key, ""); rec.Code != http.StatusUnauthorized {
+			t.Errorf("Expected the revoked key to be rejected, got %d", rec.Code)
+		}
+		if rec := serveAuthorized(router, http.MethodDelete, "/api-keys/unknown", "Bearer admin", ""); rec.Code != http.StatusNotFound {
+			t.Errorf("Expected 404, got %d", rec.Code)
+		}
+	})
+
+	t.Run("Invalid Keys", func(t *testing.T) {
+		for _, body := range []string{
+			`{"Name": "", "Scopes": ["products:read"], "Roles": ["viewer"]}`,
+			`{"Name": "unknown scope", "Scopes": ["products:delete"], "Roles": ["viewer"]}`,
+			`{"Name": "no roles", "Scopes": ["products:read"], "Roles": []}`,
+			`{"Name": "unknown role", "Scopes": ["products:read"], "Roles": ["auditor"]}`,
+			`{"Name": "expired", "Scopes": ["products:read"], "Roles": ["viewer"], "ExpiresAt": "2020-01-01T00:00:00Z"}`,
+		} {
+			if rec := serveAuthorized(router, http.MethodPost, "/api-keys", "Bearer admin", body); rec.Code != http.StatusBadRequest {
+				t.Errorf("Expected 400 for %s, got %d %s", body, rec.Code, rec.Body)
+			}
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/application"
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/go-chi/chi/v5"
+)
+
+// AuditHandler é o Adaptador de Entrada HTTP para consultas ao log de auditoria.
+type AuditHandler struct {
+	service *application.AuditService
+}
+
+// NewAuditHandler cria e retorna uma nova instância de AuditHandler.
+func NewAuditHandler(service *application.AuditService) *AuditHandler {
+	return &AuditHandler{
+		service: service,
+	}
+}
+
+// ListAuditHandler lida com a requisição GET /audit?product_id=&actor=&operation=&from=&to=&limit=.
+func (h *AuditHandler) ListAuditHandler(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseAuditFilter(r)
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+
+	h.writeEntries(w, r, filter)
+}
+
+// GetProductAuditHandler lida com a requisição GET /products/{id}/audit, aceitando os mesmos filtros.
+func (h *AuditHandler) GetProductAuditHandler(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseAuditFilter(r)
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+	filter.ProductID = chi.URLParam(r, "id")
+
+	h.writeEntries(w, r, filter)
+}
+
+func (h *AuditHandler) writeEntries(w http.ResponseWriter, r *http.Request, filter models.AuditFilter) {
+	entries, err := h.service.ListEntries(filter)
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, entries)
+}
+
+// parseAuditFilter lê os filtros da query string. Datas seguem o formato RFC 3339.
+func parseAuditFilter(r *http.Request) (models.AuditFilter, error) {
+	query := r.URL.Query()
+	filter := models.AuditFilter{
+		ProductID: query.Get("product_id"),
+		Actor:     query.Get("actor"),
+		Operation: models.AuditOperation(query.Get("operation")),
+	}
+
+	var err error
+	if raw := query.Get("from"); raw != "" {
+		if filter.From, err = time.Parse(time.RFC3339, raw); err != nil {
+			return filter, models.ErrInvalidAuditQuery
+		}
+	}
+	if raw := query.Get("to"); raw != "" {
+		if filter.To, err = time.Parse(time.RFC3339, raw); err != nil {
+			return filter, models.ErrInvalidAuditQuery
+		}
+	}
+	if raw := query.Get("limit"); raw != "" {
+		if filter.Limit, err = strconv.Atoi(raw); err != nil || filter.Limit < 1 {
+			return filter, models.ErrInvalidAuditQuery
+		}
+	}
+	return filter, nil
+}
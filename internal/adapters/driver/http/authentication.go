@@ -0,0 +1,136 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/danielrios/product-service-go/internal/application"
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+// Esquemas do cabeçalho Authorization: "Bearer <token>", usado com tokens JWT, e "ApiKey <chave>", com as
+// chaves de API emitidas pelo serviço.
+const (
+	AuthSchemeBearer = "Bearer"
+	AuthSchemeAPIKey = "ApiKey"
+)
+
+// Authenticate exige em cada requisição o cabeçalho "Authorization: <esquema> <credenciais>", verificadas
+// pelo CredentialVerifier do esquema (sem diferenciar maiúsculas de minúsculas no nome do esquema). O
+// principal autenticado é registrado no contexto da aplicação e passa a identificar o autor das operações,
+// no lugar do cabeçalho X-Actor. Requisições sem credenciais ou com credenciais inválidas recebem 401, com
+// os esquemas aceitos em WWW-Authenticate. public informa as requisições que dispensam autenticação.
+// Deve ser registrado depois de RequestContext.
+func Authenticate(verifiers map[string]ports.CredentialVerifier, public func(r *http.Request) bool) func(http.Handler) http.Handler {
+	byScheme := make(map[string]ports.CredentialVerifier, len(verifiers))
+	schemes := make([]string, 0, len(verifiers))
+	for scheme, verifier := range verifiers {
+		byScheme[strings.ToLower(scheme)] = verifier
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if public != nil && public(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			authorization := r.Header.Get("Authorization")
+			if authorization == "" {
+				writeAuthenticationError(w, r, schemes, "", models.ErrUnauthenticated)
+				return
+			}
+			scheme, credentials, _ := strings.Cut(authorization, " ")
+			verifier, ok := byScheme[strings.ToLower(scheme)]
+			if !ok {
+				writeAuthenticationError(w, r, schemes, "", fmt.Errorf("%w %q: use %s", models.ErrUnsupportedAuthType, scheme, strings.Join(schemes, " or ")))
+				return
+			}
+
+			principal, err := verifier.Verify(r.Context(), strings.TrimSpace(credentials))
+			if err != nil {
+				writeAuthenticationError(w, r, schemes, scheme, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(application.WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
+
+// writeAuthenticationError responde com o problema do erro e, nos erros de autenticação, com um desafio
+// WWW-Authenticate (RFC 9110) para cada esquema aceito. O esquema das credenciais rejeitadas recebe
+// error="invalid_token" (RFC 6750).
+func writeAuthenticationError(w http.ResponseWriter, r *http.Request, schemes []string, rejected string, err error) {
+	if errors.Is(err, models.ErrUnauthenticated) || errors.Is(err, models.ErrUnsupportedAuthType) || errors.Is(err, models.ErrInvalidCredentials) {
+		for _, scheme := range schemes {
+			challenge := scheme + ` realm="product-service"`
+			if strings.EqualFold(scheme, rejected) {
+				challenge += `, error="invalid_token"`
+			}
+			w.Header().Add("WWW-Authenticate", challenge)
+		}
+	}
+	writeErrorResponse(w, r, err)
+}
+
+// RequireScopes exige do principal autenticado o escopo read nas requisições GET e HEAD e o escopo write nas
+// demais, respondendo 403 quando ele falta. Requisições sem principal, quando a autenticação não está
+// habilitada, seguem sem verificação.
+func RequireScopes(read, write string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scope := write
+			if r.Method == http.MethodGet || r.Method == http.MethodHead {
+				scope = read
+			}
+			if principal := application.PrincipalFrom(r.Context()); principal != nil && !principal.HasScope(scope) {
+				writeErrorResponse(w, r, fmt.Errorf("%w: %s", models.ErrInsufficientScope, scope))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireReadableProducts recusa com 403 as requisições de principais que não podem ler todos os campos de
+// produtos, nas rotas cujas respostas trazem produtos completos, como a auditoria e o streaming. Com authz
+// nil, retorna um middleware que não faz nada.
+func RequireReadableProducts(authz *application.Authorizer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if authz == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			readable := authz.ReadableFields(r.Context())
+			if err := models.NewForbiddenFieldsError(models.FieldAccessRead, models.ProductFields, readable); err != nil {
+				writeErrorResponse(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequirePermission exige que os papéis do principal autenticado concedam a permissão da rota, segundo o
+// Authorizer, respondendo 403 quando não concedem. Com authz nil, quando a autorização não está habilitada,
+// retorna um middleware que não faz nada.
+func RequirePermission(authz *application.Authorizer, permission models.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if authz == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := authz.Authorize(r.Context(), permission); err != nil {
+				writeErrorResponse(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package http_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	httpDriver "github.com/danielrios/product-service-go/internal/adapters/driver/http"
+
+	"github.com/danielrios/product-service-go/internal/application"
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+// stubVerifier aceita apenas o token "valid-<sujeito>", com o escopo products:read.
+type stubVerifier struct{}
+
+func (stubVerifier) Verify(ctx context.Context, token string) (*models.Principal, error) {
+	subject, ok := strings.CutPrefix(token, "valid-")
+	if !ok {
+		return nil, fmt.Errorf("%w: token is expired", models.ErrInvalidCredentials)
+	}
+	return &models.Principal{Subject: subject, Method: models.AuthMethodJWT, Scopes: []string{models.ScopeProductsRead}}, nil
+}
+
+// adminVerifier aceita apenas o token "admin", com o escopo e o papel admin.
+type adminVerifier struct{}
+
+func (adminVerifier) Verify(ctx context.Context, token string) (*models.Principal, error) {
+	if token != "admin" {
+		return nil, models.ErrInvalidCredentials
+	}
+	return &models.Principal{Subject: "admin", Method: models.AuthMethodJWT, Scopes: []string{models.ScopeAdmin}, Roles: []string{models.RoleAdmin}}, nil
+}
+
+func TestAuthentication(t *testing.T) {
+	router := newTestRouter(t, httpDriver.WithAuthentication(map[string]ports.CredentialVerifier{
+		httpDriver.AuthSchemeBearer: stubVerifier{},
+	}))
+
+	tests := []struct {
+		name          string
+		target        string
+		authorization string
+		wantStatus    int
+		wantCode      string
+		wantChallenge string
+	}{
+		{"Public Document", "/openapi.json", "", http.StatusOK, "", ""},
+		{"Missing Credentials", "/v2/products", "", http.StatusUnauthorized, "unauthenticated", `Bearer realm="product-service"`},
+		{"Unsupported Scheme", "/v2/products", "Basic dXNlcjpwYXNz", http.StatusUnauthorized, "unsupported-authorization-scheme", `Bearer realm="product-service"`},
+		{"Invalid Token", "/v2/products", "Bearer expired", http.StatusUnauthorized, "invalid-credentials", `Bearer realm="product-service", error="invalid_token"`},
+		{"Valid Token", "/v2/products", "Bearer valid-alice", http.StatusOK, "", ""},
+		{"Scheme Ignores Case", "/v2/products", "bearer valid-alice", http.StatusOK, "", ""},
+		{"Unknown Route", "/unknown", "", http.StatusUnauthorized, "unauthenticated", `Bearer realm="product-service"`},
+		{"Missing Scope", "/webhooks", "Bearer valid-alice", http.StatusForbidden, "insufficient-scope", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("Expected %d, got %d %s", tt.wantStatus, rec.Code, rec.Body)
+			}
+			if tt.wantCode != "" && !strings.Contains(rec.Body.String(), `"code":"`+tt.wantCode+`"`) {
+				t.Errorf("Expected code %q, got %s", tt.wantCode, rec.Body)
+			}
+			if got := rec.Header().Get("WWW-Authenticate"); got != tt.wantChallenge {
+				t.Errorf("Expected WWW-Authenticate %q, got %q", tt.wantChallenge, got)
+			}
+		})
+	}
+}
+
+func TestAuthenticationSetsThePrincipal(t *testing.T) {
+	var (
+		principal *models.Principal
+		actor     string
+	)
+	handler := httpDriver.RequestContext(httpDriver.Authenticate(map[string]ports.CredentialVerifier{
+		httpDriver.AuthSchemeBearer: stubVerifier{},
+	}, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, actor = application.PrincipalFrom(r.Context()), application.ActorFrom(r.Context())
+	})))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer valid-alice")
+	req.Header.Set(httpDriver.ActorHeader, "mallory")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if principal == nil || principal.Subject != "alice" {
+		t.Errorf("Expected the principal on the context, got %+v", principal)
+	}
+	if actor != "alice" {
+		t.Errorf("Expected the subject to be the actor instead of X-Actor, got %q", actor)
+	}
+}
@@ -0,0 +1,161 @@
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	httpDriver "github.com/danielrios/product-service-go/internal/adapters/driver/http"
+
+	"github.com/danielrios/product-service-go/internal/adapters/driven/memdb"
+	"github.com/danielrios/product-service-go/internal/application"
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+// roleVerifier aceita como token a lista de papéis do principal, separados por vírgula, com os escopos de
+// leitura e escrita de produtos.
+type roleVerifier struct{}
+
+func (roleVerifier) Verify(ctx context.Context, token string) (*models.Principal, error) {
+	return &models.Principal{
+		Subject: "user-" + token,
+		Method:  models.AuthMethodJWT,
+		Scopes:  []string{models.ScopeProductsRead, models.ScopeProductsWrite},
+		Roles:   strings.Split(token, ","),
+	}, nil
+}
+
+func TestAuthorization(t *testing.T) {
+	authorizer, err := application.NewAuthorizer(models.DefaultRolePermissions(), models.DefaultProductFieldPolicy())
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := newTestRouter(t,
+		httpDriver.WithAuthentication(map[string]ports.CredentialVerifier{httpDriver.AuthSchemeBearer: roleVerifier{}}),
+		httpDriver.WithAuthorization(authorizer),
+	)
+	product := `{"id": "1", "name": "Mouse", "price": 10}`
+	if rec := serveAuthorized(router, http.MethodPost, "/v2/products", "Bearer editor", product); rec.Code != http.StatusCreated {
+		t.Fatalf("Expected the editor to create the product, got %d %s", rec.Code, rec.Body)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		roles      string
+		body       string
+		wantStatus int
+	}{
+		{"Viewer Reads", http.MethodGet, "/v2/products/1", models.RoleViewer, "", http.StatusOK},
+		{"Viewer Exports", http.MethodGet, "/products/export", models.RoleViewer, "", http.StatusOK},
+		{"Viewer Cannot Create", http.MethodPost, "/v2/products", models.RoleViewer, `{"id": "2", "name": "Pad", "price": 5}`, http.StatusForbidden},
+		{"Viewer Cannot Update", http.MethodPut, "/products/1", models.RoleViewer, `{"ID": "1", "Name": "Mouse", "Price": 12}`, http.StatusForbidden},
+		{"Pricing Manager Updates", http.MethodPut, "/products/1", models.RolePricingManager, `{"ID": "1", "Name": "Mouse", "Price": 12}`, http.StatusOK},
+		{"Pricing Manager Cannot Delete", http.MethodDelete, "/v2/products/1", models.RolePricingManager, "", http.StatusForbidden},
+		{"Pricing Manager Cannot Import", http.MethodPost, "/v2/products/import", models.RolePricingManager, "", http.StatusForbidden},
+		{"Viewer Cannot Cancel Jobs", http.MethodPost, "/jobs/1/cancel", models.RoleViewer, "", http.StatusForbidden},
+		{"Unknown Role", http.MethodGet, "/v2/products", "auditor", "", http.StatusForbidden},
+		{"Roles Combine", http.MethodDelete, "/v2/products/1", "auditor," + models.RoleAdmin, "", http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveAuthorized(router, tt.method, tt.target, "Bearer "+tt.roles, tt.body)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("Expected %d, got %d %s", tt.wantStatus, rec.Code, rec.Body)
+			}
+			if tt.wantStatus == http.StatusForbidden && !strings.Contains(rec.Body.String(), `"code":"forbidden"`) {
+				t.Errorf("Expected the forbidden problem, got %s", rec.Body)
+			}
+		})
+	}
+}
+
+func TestFieldAuthorization(t *testing.T) {
+	policy := models.DefaultProductFieldPolicy()
+	policy.Read = map[string][]models.ProductField{models.RoleViewer: {models.ProductFieldName}}
+	authorizer, err := application.NewAuthorizer(models.DefaultRolePermissions(), policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := memdb.NewInMemoryProductRepository()
+	service := application.NewProductService(repo, application.WithImports(repo), application.WithAuthorizer(authorizer))
+	router := httpDriver.NewRouter(httpDriver.Handlers{
+		Products:   httpDriver.NewProductHandler(service),
+		ProductsV2: httpDriver.NewProductHandler(service, httpDriver.WithAPIVersion(httpDriver.APIVersion2)),
+	},
+		httpDriver.WithAuthentication(map[string]ports.CredentialVerifier{httpDriver.AuthSchemeBearer: roleVerifier{}}),
+		httpDriver.WithAuthorization(authorizer),
+	)
+	if rec := serveAuthorized(router, http.MethodPost, "/v2/products", "Bearer admin", `{"id": "1", "name": "Mouse", "price": 10}`); rec.Code != http.StatusCreated {
+		t.Fatalf("Expected the admin to create the product, got %d %s", rec.Code, rec.Body)
+	}
+
+	t.Run("Forbidden Create", func(t *testing.T) {
+		rec := serveAuthorized(router, http.MethodPost, "/v2/products", "Bearer editor", `{"id": "2", "name": "Keyboard", "price": 30}`)
+		if rec.Code != http.StatusForbidden || !strings.Contains(rec.Body.String(), `"location":"product.Price"`) {
+			t.Fatalf("Expected 403 naming the price, got %d %s", rec.Code, rec.Body)
+		}
+		if rec := serveAuthorized(router, http.MethodPost, "/v2/products", "Bearer editor", `{"id": "2", "name": "Keyboard", "price": 0}`); rec.Code != http.StatusCreated {
+			t.Errorf("Expected the editor to create the product without a price, got %d %s", rec.Code, rec.Body)
+		}
+	})
+
+	t.Run("Forbidden Write", func(t *testing.T) {
+		rec := serveAuthorized(router, http.MethodPut, "/v2/products/1", "Bearer editor", `{"id": "1", "name": "Mouse", "price": 12}`)
+		if rec.Code != http.StatusForbidden {
+			t.Fatalf("Expected 403, got %d %s", rec.Code, rec.Body)
+		}
+		body := rec.Body.String()
+		if !strings.Contains(body, `"code":"forbidden-fields"`) || !strings.Contains(body, `"location":"product.Price"`) {
+			t.Errorf("Expected the forbidden-fields problem naming the price, got %s", body)
+		}
+	})
+
+	t.Run("Read Redaction", func(t *testing.T) {
+		rec := serveAuthorized(router, http.MethodGet, "/v2/products/1", "Bearer viewer", "")
+		if rec.Code != http.StatusOK || strings.Contains(rec.Body.String(), `"price"`) || !strings.Contains(rec.Body.String(), `"name":"Mouse"`) {
+			t.Errorf("Expected the price to be omitted, got %d %s", rec.Code, rec.Body)
+		}
+		if rec := serveAuthorized(router, http.MethodGet, "/v2/products/1?fields=price", "Bearer viewer", ""); rec.Code != http.StatusForbidden {
+			t.Errorf("Expected 403 requesting the price, got %d %s", rec.Code, rec.Body)
+		}
+		if rec := serveAuthorized(router, http.MethodGet, "/products/export", "Bearer viewer", ""); rec.Code != http.StatusForbidden {
+			t.Errorf("Expected 403 exporting full products, got %d %s", rec.Code, rec.Body)
+		}
+		if rec := serveAuthorized(router, http.MethodGet, "/v2/products/1", "Bearer editor", ""); !strings.Contains(rec.Body.String(), `"price"`) {
+			t.Errorf("Expected the editor to read the price, got %s", rec.Body)
+		}
+	})
+}
+
+func TestAuthorization_IdempotencyKeyIsNotBurned(t *testing.T) {
+	authorizer, err := application.NewAuthorizer(models.DefaultRolePermissions(), models.DefaultProductFieldPolicy())
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := newTestRouter(t,
+		httpDriver.WithAuthentication(map[string]ports.CredentialVerifier{httpDriver.AuthSchemeBearer: roleVerifier{}}),
+		httpDriver.WithAuthorization(authorizer),
+	)
+	create := func(roles string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/v2/products", strings.NewReader(`{"id": "1", "name": "Mouse", "price": 0}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+roles)
+		req.Header.Set(httpDriver.IdempotencyKeyHeader, "create-mouse")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := create(models.RoleViewer); rec.Code != http.StatusForbidden {
+		t.Fatalf("Expected 403 for a viewer, got %d %s", rec.Code, rec.Body)
+	}
+	rec := create(models.RoleViewer)
+	if rec.Code != http.StatusForbidden || rec.Header().Get(httpDriver.IdempotentReplayedHeader) != "" {
+		t.Errorf("Expected the 403 to be evaluated again instead of replayed, got %d %v", rec.Code, rec.Header())
+	}
+}
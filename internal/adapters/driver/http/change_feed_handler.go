@@ -0,0 +1,54 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/danielrios/product-service-go/internal/application"
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+// ChangeFeedHandler é o Adaptador de Entrada HTTP para o feed de alterações de produtos.
+type ChangeFeedHandler struct {
+	service *application.ChangeFeedService
+}
+
+// NewChangeFeedHandler cria e retorna uma nova instância de ChangeFeedHandler.
+func NewChangeFeedHandler(service *application.ChangeFeedService) *ChangeFeedHandler {
+	return &ChangeFeedHandler{
+		service: service,
+	}
+}
+
+// GetChangesHandler lida com a requisição GET /changes?since=<seq>&limit=<n>.
+func (h *ChangeFeedHandler) GetChangesHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var since int64
+	if raw := query.Get("since"); raw != "" {
+		parsed, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			writeErrorResponse(w, r, models.ErrInvalidChangeQuery)
+			return
+		}
+		since = parsed
+	}
+
+	limit := application.DefaultChangesLimit
+	if raw := query.Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			writeErrorResponse(w, r, models.ErrInvalidChangeQuery)
+			return
+		}
+		limit = parsed
+	}
+
+	page, err := h.service.GetChanges(since, limit)
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, page)
+}
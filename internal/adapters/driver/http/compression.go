@@ -0,0 +1,376 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+)
+
+// DefaultMaxDecompressionRatio é a razão máxima padrão entre o tamanho descomprimido e o comprimido de um
+// corpo de requisição.
+const DefaultMaxDecompressionRatio int64 = 100
+
+// CompressionConfig configura a compressão das respostas.
+type CompressionConfig struct {
+	// MinSize é o tamanho mínimo do corpo, em bytes, para que a resposta seja comprimida. Corpos menores
+	// costumam crescer com o cabeçalho do formato, e não compensam o custo da compressão.
+	MinSize int
+	// ContentTypes são os tipos de conteúdo comprimidos, sem parâmetros. "text/*" inclui todos os
+	// subtipos de text. Uma lista vazia desativa a compressão.
+	ContentTypes []string
+}
+
+// DefaultCompressionConfig retorna a configuração padrão: respostas a partir de 1 KiB nos formatos
+// textuais da API. Server-Sent Events ficam de fora, pois cada evento precisa chegar ao cliente assim
+// que é enviado, e MessagePack já é compacto.
+func DefaultCompressionConfig() CompressionConfig {
+	return CompressionConfig{
+		MinSize: 1024,
+		ContentTypes: []string{
+			jsonMediaType,
+			ProblemContentType,
+			xmlMediaType,
+			csvMediaType,
+			"application/x-ndjson",
+		},
+	}
+}
+
+// compresses informa se respostas com o Content-Type informado devem ser comprimidas.
+func (c CompressionConfig) compresses(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	mainType, _, _ := strings.Cut(mediaType, "/")
+	return slices.ContainsFunc(c.ContentTypes, func(allowed string) bool {
+		return allowed == mediaType || allowed == mainType+"/*"
+	})
+}
+
+// responseEncoder é um codificador de compressão reaproveitável entre respostas com Reset.
+type responseEncoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// contentEncoding descreve uma codificação de resposta negociável pelo cabeçalho Accept-Encoding.
+// Os codificadores são mantidos em um pool, pois alocá-los custa bem mais que comprimir uma resposta típica.
+type contentEncoding struct {
+	name string
+	pool *sync.Pool
+}
+
+// contentEncodings lista as codificações na ordem de preferência do serviço, usada para desempatar a
+// negociação. O brotli usa o nível 4, mais adequado a respostas geradas a cada requisição que o padrão.
+var contentEncodings = []contentEncoding{
+	{name: "zstd", pool: &sync.Pool{New: func() any {
+		encoder, err := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			panic(err)
+		}
+		return encoder
+	}}},
+	{name: "br", pool: &sync.Pool{New: func() any {
+		return brotli.NewWriterLevel(nil, 4)
+	}}},
+	{name: "gzip", pool: &sync.Pool{New: func() any {
+		return gzip.NewWriter(nil)
+	}}},
+}
+
+// negotiateContentEncoding escolhe a codificação da resposta pelo cabeçalho Accept-Encoding (RFC 9110),
+// ou retorna nil se o cliente não aceitar nenhuma das disponíveis.
+func negotiateContentEncoding(r *http.Request) *contentEncoding {
+	qualities := make(map[string]float64)
+	for _, header := range r.Header.Values("Accept-Encoding") {
+		for _, element := range strings.Split(header, ",") {
+			name, params, _ := strings.Cut(element, ";")
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+			quality := 1.0
+			if key, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(key) == "q" {
+				parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+				if err != nil {
+					continue
+				}
+				quality = parsed
+			}
+			qualities[name] = quality
+		}
+	}
+
+	var (
+		best        *contentEncoding
+		bestQuality float64
+	)
+	for i := range contentEncodings {
+		encoding := &contentEncodings[i]
+		quality, ok := qualities[encoding.name]
+		if !ok {
+			quality = qualities["*"]
+		}
+		if quality > bestQuality {
+			best, bestQuality = encoding, quality
+		}
+	}
+	return best
+}
+
+// Compress comprime as respostas na codificação negociada pelo cabeçalho Accept-Encoding (zstd, br ou
+// gzip), quando o Content-Type consta de config.ContentTypes e o corpo tem pelo menos config.MinSize bytes.
+// O início do corpo fica em memória até que o tamanho mínimo seja atingido ou a resposta seja descarregada
+// com Flush. Deve ser registrado antes dos middlewares que leem a resposta, como o de idempotência e o de
+// validação OpenAPI, para que eles vejam o corpo sem compressão.
+func Compress(config CompressionConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+			encoding := negotiateContentEncoding(r)
+			if encoding == nil || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressResponseWriter{ResponseWriter: w, config: config, encoding: encoding, status: http.StatusOK}
+			defer cw.close()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// compressResponseWriter decide se a resposta será comprimida na primeira escrita que atingir o tamanho
+// mínimo, ou ao fim da resposta. Implementa Flush e Unwrap, como validatingResponseWriter.
+type compressResponseWriter struct {
+	http.ResponseWriter
+	config   CompressionConfig
+	encoding *contentEncoding
+
+	status      int
+	wroteHeader bool
+	decided     bool
+	buffer      []byte
+	encoder     responseEncoder
+}
+
+func (w *compressResponseWriter) WriteHeader(status int) {
+	if w.decided {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	if w.wroteHeader {
+		return
+	}
+	if status < http.StatusOK {
+		// Respostas informativas, como 103 Early Hints, não encerram a resposta.
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	w.status = status
+	w.wroteHeader = true
+	if status == http.StatusNoContent || status == http.StatusNotModified || !w.config.compresses(w.Header().Get("Content-Type")) {
+		w.decide(false)
+	}
+}
+
+func (w *compressResponseWriter) Write(data []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.decided {
+		if len(w.buffer)+len(data) < w.config.MinSize {
+			w.buffer = append(w.buffer, data...)
+			return len(data), nil
+		}
+		w.decide(w.Header().Get("Content-Encoding") == "")
+	}
+	if w.encoder != nil {
+		return w.encoder.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// decide envia o cabeçalho da resposta, comprimida ou não, seguido do que estiver em memória.
+func (w *compressResponseWriter) decide(compress bool) {
+	w.decided = true
+	if compress {
+		header := w.Header()
+		header.Set("Content-Encoding", w.encoding.name)
+		header.Del("Content-Length")
+		// O ETag forte identifica o corpo sem compressão; o comprimido é equivalente, mas não idêntico,
+		// então passa a ser fraco. If-None-Match usa a comparação fraca, e continua funcionando.
+		if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			header.Set("ETag", "W/"+etag)
+		}
+		w.encoder = w.encoding.pool.Get().(responseEncoder)
+		w.encoder.Reset(w.ResponseWriter)
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+
+	if len(w.buffer) > 0 {
+		var err error
+		if w.encoder != nil {
+			_, err = w.encoder.Write(w.buffer)
+		} else {
+			_, err = w.ResponseWriter.Write(w.buffer)
+		}
+		if err != nil {
+			log.Printf("Erro ao escrever resposta: %v", err)
+		}
+	}
+	w.buffer = nil
+}
+
+// Flush envia o que estiver em memória. Antes do tamanho mínimo, a resposta segue sem compressão.
+func (w *compressResponseWriter) Flush() {
+	if !w.decided {
+		if !w.wroteHeader {
+			w.WriteHeader(http.StatusOK)
+		}
+		if !w.decided {
+			w.decide(false)
+		}
+	}
+	if w.encoder != nil {
+		if err := w.encoder.Flush(); err != nil {
+			log.Printf("Erro ao comprimir resposta: %v", err)
+		}
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *compressResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// close encerra a resposta: envia o que ainda estiver em memória e devolve o codificador ao pool.
+func (w *compressResponseWriter) close() {
+	if !w.decided {
+		if !w.wroteHeader {
+			// O handler não escreveu nada; o servidor responde 200 sem corpo, como sem o middleware.
+			return
+		}
+		w.decide(false)
+	}
+	if w.encoder != nil {
+		if err := w.encoder.Close(); err != nil {
+			log.Printf("Erro ao comprimir resposta: %v", err)
+		}
+		w.encoder.Reset(nil)
+		w.encoding.pool.Put(w.encoder)
+		w.encoder = nil
+	}
+}
+
+// DecompressRequestBody descomprime os corpos de requisição enviados com "Content-Encoding: gzip". Para
+// evitar bombas de descompressão, o corpo descomprimido é limitado ao valor retornado por limitFor (o mesmo
+// de MaxBodyBytes, que limita o corpo comprimido) e a maxRatio vezes o tamanho comprimido; valores menores
+// ou iguais a zero desativam cada limite. Outras codificações resultam em 415.
+func DecompressRequestBody(limitFor func(r *http.Request) int64, maxRatio int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch encoding := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding"))); encoding {
+			case "", "identity":
+			case "gzip", "x-gzip":
+				compressed := &countingReader{reader: r.Body}
+				decoder, err := gzip.NewReader(compressed)
+				if err != nil {
+					writeErrorResponse(w, r, readBodyError(err))
+					return
+				}
+				defer decoder.Close()
+
+				r.Body = &decompressedBody{
+					decoder:    decoder,
+					compressed: compressed,
+					closer:     r.Body,
+					limit:      limitFor(r),
+					maxRatio:   maxRatio,
+				}
+				r.Header.Del("Content-Encoding")
+				r.ContentLength = -1
+			default:
+				writeErrorResponse(w, r, fmt.Errorf("%w: got %q", models.ErrUnsupportedContentEncoding, encoding))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// minRatioCheckBytes é o tamanho descomprimido a partir do qual a razão de compressão é verificada. Corpos
+// pequenos e repetitivos têm razões altas sem representar risco.
+const minRatioCheckBytes = 1 << 20
+
+// decompressedBody lê o corpo descomprimido, interrompendo a leitura quando um dos limites é excedido.
+type decompressedBody struct {
+	decoder    io.Reader
+	compressed *countingReader
+	closer     io.Closer
+	limit      int64
+	maxRatio   int64
+	read       int64
+	err        error
+}
+
+func (b *decompressedBody) Read(p []byte) (int, error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+	n, err := b.decoder.Read(p)
+	b.read += int64(n)
+	switch {
+	case b.limit > 0 && b.read > b.limit:
+		b.err = &decompressionLimitError{reason: fmt.Sprintf("the limit is %d bytes", b.limit)}
+	case b.maxRatio > 0 && b.read > minRatioCheckBytes && b.read > b.maxRatio*b.compressed.n:
+		b.err = &decompressionLimitError{reason: fmt.Sprintf("the compression ratio exceeds %d:1", b.maxRatio)}
+	default:
+		return n, err
+	}
+	return 0, b.err
+}
+
+func (b *decompressedBody) Close() error {
+	return b.closer.Close()
+}
+
+// countingReader conta os bytes lidos do corpo comprimido.
+type countingReader struct {
+	reader io.Reader
+	n      int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.reader.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// decompressionLimitError indica qual limite da descompressão foi excedido.
+type decompressionLimitError struct {
+	reason string
+}
+
+func (e *decompressionLimitError) Error() string {
+	return models.ErrDecompressionLimitExceeded.Error() + ": " + e.reason
+}
+
+func (e *decompressionLimitError) Unwrap() error {
+	return models.ErrDecompressionLimitExceeded
+}
@@ -0,0 +1,180 @@
+package http_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	httpDriver "github.com/danielrios/product-service-go/internal/adapters/driver/http"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+)
+
+func serveEncoded(handler http.Handler, target string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func decompress(t *testing.T, encoding string, body []byte) []byte {
+	t.Helper()
+	var reader io.Reader
+	switch encoding {
+	case "gzip":
+		gz, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("Expected a gzip body, got %v", err)
+		}
+		reader = gz
+	case "br":
+		reader = brotli.NewReader(bytes.NewReader(body))
+	case "zstd":
+		decoder, err := zstd.NewReader(bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("Expected a zstd body, got %v", err)
+		}
+		defer decoder.Close()
+		reader = decoder
+	default:
+		return body
+	}
+	decoded, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("Expected a valid %s body, got %v", encoding, err)
+	}
+	return decoded
+}
+
+func TestResponseCompression(t *testing.T) {
+	config := httpDriver.DefaultCompressionConfig()
+	config.MinSize = 400
+	router := newTestRouter(t, httpDriver.WithCompression(config))
+	serve(router, http.MethodPost, "/products", "application/json", `{"ID": "1", "Name": "`+strings.Repeat("Long name ", 50)+`", "Price": 10}`)
+	plain := serveEncoded(router, "/products", nil)
+
+	tests := []struct {
+		name           string
+		target         string
+		header         http.Header
+		wantEncoding   string
+		wantIdentityOf *httptest.ResponseRecorder
+	}{
+		{"Gzip", "/products", http.Header{"Accept-Encoding": {"gzip"}}, "gzip", plain},
+		{"Brotli", "/products", http.Header{"Accept-Encoding": {"br"}}, "br", plain},
+		{"Zstandard", "/products", http.Header{"Accept-Encoding": {"zstd"}}, "zstd", plain},
+		{"Server Preference Breaks Ties", "/products", http.Header{"Accept-Encoding": {"gzip, deflate, br, zstd"}}, "zstd", plain},
+		{"Quality Values Are Honored", "/products", http.Header{"Accept-Encoding": {"zstd;q=0.1, gzip"}}, "gzip", plain},
+		{"Wildcard", "/products", http.Header{"Accept-Encoding": {"*, zstd;q=0"}}, "br", plain},
+		{"Unknown Encodings Are Ignored", "/products", http.Header{"Accept-Encoding": {"deflate"}}, "", plain},
+		{"Small Bodies Are Not Compressed", "/products/2", http.Header{"Accept-Encoding": {"gzip"}}, "", nil},
+		{"Only Allowed Content Types Are Compressed", "/products", http.Header{"Accept-Encoding": {"gzip"}, "Accept": {"application/msgpack"}}, "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveEncoded(router, tt.target, tt.header)
+
+			if rec.Header().Get("Content-Encoding") != tt.wantEncoding {
+				t.Fatalf("Expected encoding %q, got %d %q", tt.wantEncoding, rec.Code, rec.Header().Get("Content-Encoding"))
+			}
+			if !strings.Contains(strings.Join(rec.Header().Values("Vary"), ","), "Accept-Encoding") {
+				t.Errorf("Expected Vary: Accept-Encoding, got %q", rec.Header().Values("Vary"))
+			}
+			if tt.wantIdentityOf != nil {
+				if body := decompress(t, tt.wantEncoding, rec.Body.Bytes()); !bytes.Equal(body, tt.wantIdentityOf.Body.Bytes()) {
+					t.Errorf("Expected the uncompressed body %s, got %s", tt.wantIdentityOf.Body, body)
+				}
+			}
+		})
+	}
+
+	t.Run("Compressed Responses Have Weak ETags", func(t *testing.T) {
+		rec := serveEncoded(router, "/products", http.Header{"Accept-Encoding": {"gzip"}})
+
+		etag := rec.Header().Get("ETag")
+		if etag != "W/"+plain.Header().Get("ETag") {
+			t.Fatalf("Expected the weak form of %s, got %s", plain.Header().Get("ETag"), etag)
+		}
+		rec = serveEncoded(router, "/products", http.Header{"Accept-Encoding": {"gzip"}, "If-None-Match": {etag}})
+		if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 || rec.Header().Get("Content-Encoding") != "" {
+			t.Errorf("Expected an empty 304, got %d %q %s", rec.Code, rec.Header().Get("Content-Encoding"), rec.Body)
+		}
+	})
+
+	t.Run("Streamed Exports Are Compressed", func(t *testing.T) {
+		rec := serveEncoded(router, "/products/export?format=ndjson", http.Header{"Accept-Encoding": {"gzip"}})
+
+		if rec.Header().Get("Content-Encoding") != "gzip" {
+			t.Fatalf("Expected a gzip export, got %q", rec.Header().Get("Content-Encoding"))
+		}
+		if lines := strings.Count(string(decompress(t, "gzip", rec.Body.Bytes())), "\n"); lines != 1 {
+			t.Errorf("Expected 1 exported product, got %d", lines)
+		}
+	})
+
+	t.Run("Compression Can Be Disabled", func(t *testing.T) {
+		router := newTestRouter(t, httpDriver.WithCompression(httpDriver.CompressionConfig{}))
+
+		rec := serveEncoded(router, "/products", http.Header{"Accept-Encoding": {"gzip"}})
+		if rec.Header().Get("Content-Encoding") != "" {
+			t.Errorf("Expected no compression, got %q", rec.Header().Get("Content-Encoding"))
+		}
+	})
+}
+
+func gzipped(t *testing.T, data string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write([]byte(data)); err != nil {
+		t.Fatalf("Expected the body to compress, got %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Expected the body to compress, got %v", err)
+	}
+	return buf.String()
+}
+
+func TestCompressedRequestBodies(t *testing.T) {
+	csv := "ID,Name,Price\n1,Product 1,10\n2,Product 2,20\n"
+	repetitive := "ID,Name,Price\n" + strings.Repeat("1,Product,1\n", 30)
+	bomb := "ID,Name,Price\n" + strings.Repeat("1,Product,1\n", 200_000)
+
+	tests := []struct {
+		name     string
+		opts     []httpDriver.RouterOption
+		encoding string
+		body     string
+		wantCode int
+		wantBody string
+	}{
+		{"Gzip Imports", nil, "gzip", gzipped(t, csv), http.StatusOK, `"Created":2`},
+		{"Identity Imports", nil, "identity", csv, http.StatusOK, `"Created":2`},
+		{"Decompressed Size Is Limited", []httpDriver.RouterOption{httpDriver.WithMaxBodyBytes(0, 100)}, "gzip", gzipped(t, repetitive), http.StatusRequestEntityTooLarge, "decompression-limit-exceeded"},
+		{"Compression Ratio Is Limited", nil, "gzip", gzipped(t, bomb), http.StatusRequestEntityTooLarge, "compression ratio exceeds 100:1"},
+		{"Ratio Limit Can Be Disabled", []httpDriver.RouterOption{httpDriver.WithMaxDecompressionRatio(0)}, "gzip", gzipped(t, bomb), http.StatusUnprocessableEntity, `"ErrorCount":199999`},
+		{"Malformed Gzip", nil, "gzip", csv, http.StatusBadRequest, "invalid-request-body"},
+		{"Unsupported Encoding", nil, "br", csv, http.StatusUnsupportedMediaType, "unsupported-content-encoding"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter(t, tt.opts...)
+			req := httptest.NewRequest(http.MethodPost, "/products/import", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "text/csv")
+			req.Header.Set("Content-Encoding", tt.encoding)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode || !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("Expected %d with %s, got %d %s", tt.wantCode, tt.wantBody, rec.Code, rec.Body)
+			}
+		})
+	}
+}
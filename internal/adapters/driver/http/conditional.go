@@ -0,0 +1,79 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultCacheControl é o valor padrão do cabeçalho Cache-Control das leituras de produtos:
+// as respostas podem ser guardadas, mas precisam ser revalidadas com If-None-Match a cada uso.
+const DefaultCacheControl = "no-cache"
+
+// writeCacheableResponse envia uma resposta, no formato negociado pelo cabeçalho Accept, com validadores
+// de cache. O ETag forte é o hash do corpo serializado, e portanto difere entre os formatos; Last-Modified
+// é omitido quando lastModified é zero. Se a requisição condicional indicar que o cliente já tem a
+// representação atual, responde 304 Not Modified sem corpo.
+func writeCacheableResponse(w http.ResponseWriter, r *http.Request, data any, lastModified time.Time, cacheControl string) {
+	w.Header().Add("Vary", "Accept")
+	format, body, err := encodeResponse(r, data)
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+
+	hash := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(hash[:16]) + `"`
+	w.Header().Set("ETag", etag)
+	if cacheControl != "" {
+		w.Header().Set("Cache-Control", cacheControl)
+	}
+	if !lastModified.IsZero() {
+		w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(r, etag, lastModified) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", format.contentType)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body); err != nil {
+		log.Printf("Erro ao escrever resposta: %v", err)
+	}
+}
+
+// notModified avalia If-None-Match e If-Modified-Since conforme a RFC 9110: quando If-None-Match está
+// presente, If-Modified-Since é ignorado.
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		return etagListMatches(ifNoneMatch, etag)
+	}
+
+	ifModifiedSince := r.Header.Get("If-Modified-Since")
+	if ifModifiedSince == "" || lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(ifModifiedSince)
+	if err != nil {
+		return false
+	}
+	// Last-Modified tem precisão de segundos; frações são descartadas antes da comparação.
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// etagListMatches compara um cabeçalho If-None-Match com o ETag atual usando a comparação fraca,
+// como exigido para If-None-Match.
+func etagListMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,280 @@
+package http
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Tipos de conteúdo aceitos nas respostas negociadas e nos corpos de requisição.
+const (
+	jsonMediaType    = "application/json"
+	xmlMediaType     = "application/xml"
+	csvMediaType     = "text/csv"
+	msgpackMediaType = "application/msgpack"
+)
+
+// mediaTypeAliases associa nomes alternativos de uso comum ao tipo de conteúdo canônico.
+var mediaTypeAliases = map[string]string{
+	"text/xml":                xmlMediaType,
+	"application/x-msgpack":   msgpackMediaType,
+	"application/vnd.msgpack": msgpackMediaType,
+}
+
+// canonicalMediaType normaliza um tipo de conteúdo já sem parâmetros: aliases e tipos "+json" viram
+// o tipo canônico correspondente.
+func canonicalMediaType(mediaType string) string {
+	mediaType = strings.ToLower(mediaType)
+	if canonical, ok := mediaTypeAliases[mediaType]; ok {
+		return canonical
+	}
+	if isJSONMediaType(mediaType) {
+		return jsonMediaType
+	}
+	return mediaType
+}
+
+// xmlEncodable é implementado pelas representações que podem ser enviadas em XML. xmlDocument retorna
+// o valor a codificar com encoding/xml, já com o elemento raiz definido.
+type xmlEncodable interface {
+	xmlDocument() any
+}
+
+// csvEncodable é implementado pelas listas que podem ser enviadas em CSV: um cabeçalho e uma linha por item.
+// Um cabeçalho nil indica que, com o conteúdo atual, a lista não pode ser enviada em CSV.
+type csvEncodable interface {
+	csvHeader() []string
+	csvRecords() [][]string
+}
+
+// responseFormat descreve um formato de resposta negociável pelo cabeçalho Accept.
+type responseFormat struct {
+	mediaType   string
+	contentType string
+	supports    func(data any) bool
+	encode      func(w io.Writer, data any) error
+}
+
+// responseFormats lista os formatos na ordem de preferência do serviço, usada para desempatar a
+// negociação: JSON é o padrão. XML e CSV só são oferecidos para as representações que os implementam.
+var responseFormats = []responseFormat{
+	{mediaType: jsonMediaType, contentType: jsonMediaType, supports: supportsAll, encode: encodeJSON},
+	{mediaType: xmlMediaType, contentType: xmlMediaType + "; charset=utf-8", supports: supportsXML, encode: encodeXML},
+	{mediaType: csvMediaType, contentType: csvMediaType + "; charset=utf-8", supports: supportsCSV, encode: encodeCSV},
+	{mediaType: msgpackMediaType, contentType: msgpackMediaType, supports: supportsAll, encode: encodeMessagePack},
+}
+
+func supportsAll(any) bool { return true }
+
+func supportsXML(data any) bool {
+	_, ok := data.(xmlEncodable)
+	return ok
+}
+
+func supportsCSV(data any) bool {
+	list, ok := data.(csvEncodable)
+	return ok && list.csvHeader() != nil
+}
+
+func encodeJSON(w io.Writer, data any) error {
+	return json.NewEncoder(w).Encode(data)
+}
+
+func encodeXML(w io.Writer, data any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	if err := encoder.Encode(data.(xmlEncodable).xmlDocument()); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func encodeCSV(w io.Writer, data any) error {
+	list := data.(csvEncodable)
+	writer := csv.NewWriter(w)
+	if err := writer.Write(list.csvHeader()); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(list.csvRecords()); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// encodeMessagePack usa os nomes das tags json, para que os campos sejam os mesmos das respostas JSON.
+func encodeMessagePack(w io.Writer, data any) error {
+	encoder := msgpack.NewEncoder(w)
+	encoder.SetCustomStructTag("json")
+	encoder.UseCompactInts(true)
+	return encoder.Encode(data)
+}
+
+func init() {
+	// Campos json.RawMessage, como os parâmetros e o resultado dos jobs, são convertidos em valores
+	// MessagePack em vez de enviados como bytes com o texto JSON.
+	msgpack.Register(json.RawMessage(nil),
+		func(e *msgpack.Encoder, v reflect.Value) error {
+			raw := v.Interface().(json.RawMessage)
+			if len(raw) == 0 {
+				return e.EncodeNil()
+			}
+			var value any
+			if err := json.Unmarshal(raw, &value); err != nil {
+				return err
+			}
+			return e.Encode(value)
+		},
+		func(d *msgpack.Decoder, v reflect.Value) error {
+			value, err := d.DecodeInterface()
+			if err != nil {
+				return err
+			}
+			raw, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			v.SetBytes(raw)
+			return nil
+		})
+}
+
+// negotiateResponseFormat escolhe o formato da resposta pelo cabeçalho Accept (RFC 9110), entre os que
+// suportam data. Sem Accept, a resposta é JSON; se nenhum formato for aceitável, retorna ErrNotAcceptable.
+func negotiateResponseFormat(r *http.Request, data any) (*responseFormat, error) {
+	ranges := parseAccept(r.Header.Values("Accept"))
+	if len(ranges) == 0 {
+		return &responseFormats[0], nil
+	}
+
+	var (
+		best        *responseFormat
+		bestQuality float64
+	)
+	var offered []string
+	for i := range responseFormats {
+		format := &responseFormats[i]
+		if data != nil && !format.supports(data) {
+			continue
+		}
+		offered = append(offered, format.mediaType)
+		if quality := acceptQuality(ranges, format.mediaType); quality > bestQuality {
+			best, bestQuality = format, quality
+		}
+	}
+	if best == nil {
+		return nil, &notAcceptableError{offered: offered}
+	}
+	return best, nil
+}
+
+// notAcceptableError informa os formatos disponíveis para o recurso quando nenhum é aceito pelo cliente.
+type notAcceptableError struct {
+	offered []string
+}
+
+func (e *notAcceptableError) Error() string {
+	return models.ErrNotAcceptable.Error() + ": available formats are " + strings.Join(e.offered, ", ")
+}
+
+func (e *notAcceptableError) Unwrap() error {
+	return models.ErrNotAcceptable
+}
+
+// acceptRange é um intervalo de tipos de conteúdo do cabeçalho Accept, como "application/*;q=0.5".
+type acceptRange struct {
+	mediaType string
+	quality   float64
+}
+
+func parseAccept(headers []string) []acceptRange {
+	var ranges []acceptRange
+	for _, header := range headers {
+		for _, element := range strings.Split(header, ",") {
+			if strings.TrimSpace(element) == "" {
+				continue
+			}
+			mediaType, params, err := mime.ParseMediaType(element)
+			if err != nil {
+				continue
+			}
+			quality := 1.0
+			if rawQuality, ok := params["q"]; ok {
+				if quality, err = strconv.ParseFloat(rawQuality, 64); err != nil {
+					continue
+				}
+			}
+			ranges = append(ranges, acceptRange{mediaType: canonicalMediaType(mediaType), quality: quality})
+		}
+	}
+	return ranges
+}
+
+// acceptQuality retorna a qualidade que o cliente atribui ao tipo de conteúdo: a do intervalo mais
+// específico que o inclui (tipo exato, depois "tipo/*", depois "*/*"), ou zero se nenhum o incluir.
+func acceptQuality(ranges []acceptRange, mediaType string) float64 {
+	mainType, _, _ := strings.Cut(mediaType, "/")
+	quality, specificity := 0.0, -1
+	for _, accepted := range ranges {
+		current := -1
+		switch accepted.mediaType {
+		case mediaType:
+			current = 2
+		case mainType + "/*":
+			current = 1
+		case "*/*":
+			current = 0
+		}
+		if current > specificity {
+			quality, specificity = accepted.quality, current
+		}
+	}
+	return quality
+}
+
+// writeResponse envia data no formato negociado pelo cabeçalho Accept. A resposta é codificada antes
+// do envio, para que uma falha na codificação ainda possa ser respondida como erro.
+func writeResponse(w http.ResponseWriter, r *http.Request, statusCode int, data any) {
+	w.Header().Add("Vary", "Accept")
+	format, body, err := encodeResponse(r, data)
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", format.contentType)
+	w.WriteHeader(statusCode)
+	if _, err := w.Write(body); err != nil {
+		log.Printf("Erro ao escrever resposta: %v", err)
+	}
+}
+
+// encodeResponse negocia o formato da resposta e codifica data nele. Quem a envia deve acrescentar
+// Accept ao cabeçalho Vary, pois a representação depende dele.
+func encodeResponse(r *http.Request, data any) (*responseFormat, []byte, error) {
+	format, err := negotiateResponseFormat(r, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	var body bytes.Buffer
+	if data != nil {
+		if err := format.encode(&body, data); err != nil {
+			log.Printf("Erro ao codificar resposta em %s: %v", format.mediaType, err)
+			return nil, nil, err
+		}
+	}
+	return format, body.Bytes(), nil
+}
@@ -0,0 +1,135 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func serveAccept(handler http.Handler, target, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set("Accept", accept)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestContentNegotiation(t *testing.T) {
+	router := newTestRouter(t)
+	rec := serve(router, http.MethodPost, "/products", "application/json", `{"ID": "1", "Name": "Product 1", "Price": 10}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d %s", rec.Code, rec.Body)
+	}
+
+	tests := []struct {
+		name            string
+		target          string
+		accept          string
+		wantStatus      int
+		wantContentType string
+	}{
+		{"JSON Is The Default", "/products/1", "", http.StatusOK, "application/json"},
+		{"Wildcards Get JSON", "/products/1", "*/*", http.StatusOK, "application/json"},
+		{"XML", "/products/1", "application/xml", http.StatusOK, "application/xml; charset=utf-8"},
+		{"Quality Values Are Honored", "/products/1", "application/xml;q=0.5, application/msgpack", http.StatusOK, "application/msgpack"},
+		{"CSV For Lists", "/products", "text/csv", http.StatusOK, "text/csv; charset=utf-8"},
+		{"CSV Is Not Offered For Single Products", "/products/1", "text/csv", http.StatusNotAcceptable, "application/problem+json"},
+		{"CSV Is Not Offered For Stats", "/products/stats", "text/csv", http.StatusNotAcceptable, "application/problem+json"},
+		{"Explicitly Refused Formats Are Skipped", "/products/1", "application/json;q=0, */*", http.StatusOK, "application/xml; charset=utf-8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveAccept(router, tt.target, tt.accept)
+
+			if rec.Code != tt.wantStatus || rec.Header().Get("Content-Type") != tt.wantContentType {
+				t.Errorf("Expected %d %s, got %d %s: %s", tt.wantStatus, tt.wantContentType, rec.Code, rec.Header().Get("Content-Type"), rec.Body)
+			}
+			if !strings.Contains(rec.Header().Get("Vary"), "Accept") {
+				t.Errorf("Expected Vary: Accept, got %q", rec.Header().Get("Vary"))
+			}
+		})
+	}
+
+	t.Run("Representations Keep The Version Field Names", func(t *testing.T) {
+		var v1 struct {
+			XMLName xml.Name `xml:"Product"`
+			ID      string   `xml:"ID"`
+		}
+		if err := xml.Unmarshal(serveAccept(router, "/products/1", "application/xml").Body.Bytes(), &v1); err != nil || v1.ID != "1" {
+			t.Errorf("Expected a v1 XML product, got %+v (%v)", v1, err)
+		}
+
+		var v2 map[string]any
+		if err := msgpack.Unmarshal(serveAccept(router, "/v2/products/1", "application/msgpack").Body.Bytes(), &v2); err != nil {
+			t.Fatalf("Expected a MessagePack body, got %v", err)
+		}
+		if v2["id"] != "1" || v2["price"] != 10.0 {
+			t.Errorf("Expected a v2 product, got %+v", v2)
+		}
+
+		body := serveAccept(router, "/v2/products", "text/csv").Body.String()
+		if !strings.HasPrefix(body, "id,name,price,created_at,updated_at\n1,Product 1,10,") {
+			t.Errorf("Expected the export CSV columns, got %q", body)
+		}
+	})
+
+	t.Run("Conditional Requests Depend On The Format", func(t *testing.T) {
+		jsonETag := serveAccept(router, "/products/1", "application/json").Header().Get("ETag")
+		xmlETag := serveAccept(router, "/products/1", "application/xml").Header().Get("ETag")
+		if jsonETag == "" || jsonETag == xmlETag {
+			t.Errorf("Expected distinct ETags per format, got %q and %q", jsonETag, xmlETag)
+		}
+	})
+}
+
+func TestRequestBodyFormats(t *testing.T) {
+	router := newTestRouter(t)
+
+	encoded, err := msgpack.Marshal(map[string]any{"id": "3", "name": "Product 3", "price": 30})
+	if err != nil {
+		t.Fatalf("Expected the body to encode, got %v", err)
+	}
+	tests := []struct {
+		name        string
+		target      string
+		contentType string
+		body        string
+	}{
+		{"XML", "/products", "application/xml", `<Product><ID>1</ID><Name>Product &amp; Co</Name><Price>10.5</Price></Product>`},
+		{"CSV", "/v2/products", "text/csv", "id,name,price\n2,Product 2,20\n"},
+		{"MessagePack", "/v2/products", "application/msgpack", string(encoded)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(router, http.MethodPost, tt.target, tt.contentType, tt.body)
+
+			if rec.Code != http.StatusCreated {
+				t.Errorf("Expected 201, got %d %s", rec.Code, rec.Body)
+			}
+		})
+	}
+
+	t.Run("Errors Are Located As In JSON", func(t *testing.T) {
+		rec := serve(router, http.MethodPost, "/products", "application/xml", `<Product><ID>4</ID><Price>abc</Price><prce>1</prce></Product>`)
+
+		body := rec.Body.String()
+		for _, location := range []string{`"body.Name"`, `"body.Price"`, `"body.prce"`} {
+			if rec.Code != http.StatusBadRequest || !strings.Contains(body, location) {
+				t.Errorf("Expected 400 with an error at %s, got %d %s", location, rec.Code, body)
+			}
+		}
+	})
+
+	t.Run("CSV Bodies Have A Single Row", func(t *testing.T) {
+		rec := serve(router, http.MethodPost, "/v2/products", "text/csv", "id,name,price\n5,A,1\n6,B,2\n")
+
+		if rec.Code != http.StatusBadRequest || !bytes.Contains(rec.Body.Bytes(), []byte("exactly one data row")) {
+			t.Errorf("Expected 400 for a multi-row CSV body, got %d %s", rec.Code, rec.Body)
+		}
+	})
+}
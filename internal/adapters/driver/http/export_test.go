@@ -0,0 +1,14 @@
+package http
+
+// WriteErrorResponse expõe aos testes externos a conversão de erros em problemas, pois a maioria dos
+// erros do catálogo não é alcançável por uma única rota.
+var WriteErrorResponse = writeErrorResponse
+
+// ProblemCodes retorna os códigos do catálogo de problemas, na ordem da busca.
+func ProblemCodes() []string {
+	codes := make([]string, len(problemCatalog))
+	for i, entry := range problemCatalog {
+		codes[i] = entry.problem.code
+	}
+	return codes
+}
@@ -0,0 +1,147 @@
+package http
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/danielrios/product-service-go/internal/application"
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// Cabeçalhos do controle de idempotência.
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	idempotencyRetryAfterSecs = "1"
+)
+
+// Idempotency torna idempotentes as requisições não seguras (POST, PUT, PATCH, DELETE) que enviam
+// o cabeçalho Idempotency-Key. A primeira requisição é executada e sua resposta é gravada; repetições com
+// a mesma chave e o mesmo corpo recebem a resposta original com o cabeçalho Idempotent-Replayed.
+// Reutilizar a chave com outra requisição resulta em 422, e uma repetição enquanto a original ainda está
+// em andamento resulta em 409. Respostas 5xx não são gravadas, para que o cliente possa tentar novamente,
+// nem as 401 e 403, que dependem das credenciais e dos papéis do principal, e não da requisição.
+// Deve ser registrado depois de RequestContext, pois as chaves são isoladas por autor.
+func Idempotency(service *application.IdempotencyService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" || isSafeMethod(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				writeErrorResponse(w, r, readBodyError(err))
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			fingerprint := requestFingerprint(r, body)
+
+			stored, err := service.Begin(r.Context(), key, fingerprint)
+			if errors.Is(err, models.ErrIdempotencyRequestInFlight) {
+				w.Header().Set("Retry-After", idempotencyRetryAfterSecs)
+			}
+			if err != nil {
+				writeErrorResponse(w, r, err)
+				return
+			}
+			if stored != nil {
+				replayResponse(w, stored)
+				return
+			}
+
+			recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+			completed := false
+			defer func() {
+				// Se o handler falhar (inclusive com panic), a chave é liberada para uma nova tentativa.
+				if !completed {
+					if err := service.Release(r.Context(), key); err != nil {
+						log.Printf("Erro ao liberar a chave de idempotência: %v", err)
+					}
+				}
+			}()
+
+			next.ServeHTTP(recorder, r)
+
+			if !isStorableStatus(recorder.status) {
+				return
+			}
+			headers := recorder.Header().Clone()
+			headers.Del(middleware.RequestIDHeader)
+			if err := service.Complete(r.Context(), key, fingerprint, recorder.status, headers, recorder.body.Bytes()); err != nil {
+				log.Printf("Erro ao gravar a resposta da chave de idempotência: %v", err)
+				return
+			}
+			completed = true
+		})
+	}
+}
+
+// isStorableStatus informa se a resposta pode ser repetida para a mesma chave. Um 401 ou 403 deixaria de
+// valer quando o principal ganhasse a permissão, e um 5xx é uma falha transitória.
+func isStorableStatus(status int) bool {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return false
+	}
+	return status < http.StatusInternalServerError
+}
+
+// isSafeMethod informa se o método não altera o estado do servidor e, portanto, dispensa idempotência.
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// requestFingerprint identifica a requisição pelo método, caminho e corpo.
+func requestFingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// replayResponse repete a resposta gravada para a chave de idempotência.
+func replayResponse(w http.ResponseWriter, record *models.IdempotencyRecord) {
+	for name, values := range record.ResponseHeaders {
+		w.Header()[name] = values
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(record.ResponseStatus)
+	if _, err := w.Write(record.ResponseBody); err != nil {
+		log.Printf("Erro ao repetir resposta idempotente: %v", err)
+	}
+}
+
+// responseRecorder repassa a resposta ao cliente e guarda uma cópia do status e do corpo.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
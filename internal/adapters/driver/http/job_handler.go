@@ -0,0 +1,77 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/danielrios/product-service-go/internal/application"
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/go-chi/chi/v5"
+)
+
+// JobHandler é o Adaptador de Entrada HTTP para a consulta e o cancelamento de jobs assíncronos.
+type JobHandler struct {
+	service *application.JobService
+}
+
+// NewJobHandler cria e retorna uma nova instância de JobHandler.
+func NewJobHandler(service *application.JobService) *JobHandler {
+	return &JobHandler{
+		service: service,
+	}
+}
+
+// GetJobHandler lida com a requisição GET /jobs/{id}, que mostra a situação, o progresso e,
+// ao final, o resultado ou o erro do job.
+func (h *JobHandler) GetJobHandler(w http.ResponseWriter, r *http.Request) {
+	job, err := h.service.GetJob(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, withoutInput(job))
+}
+
+// CancelJobHandler lida com a requisição POST /jobs/{id}/cancel. Um job na fila é cancelado
+// imediatamente (200); um job em execução é cancelado assim que o worker observar o pedido (202).
+func (h *JobHandler) CancelJobHandler(w http.ResponseWriter, r *http.Request) {
+	job, err := h.service.CancelJob(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+
+	status := http.StatusOK
+	if !job.Status.IsFinal() {
+		status = http.StatusAccepted
+	}
+	writeResponse(w, r, status, withoutInput(job))
+}
+
+// writeJobAccepted responde 202 Accepted a uma operação submetida como job, com a URL de consulta em Location.
+func writeJobAccepted(w http.ResponseWriter, r *http.Request, job *models.Job) {
+	w.Header().Set("Location", "/jobs/"+job.ID)
+	w.Header().Set("Preference-Applied", "respond-async")
+	writeResponse(w, r, http.StatusAccepted, withoutInput(job))
+}
+
+// withoutInput retorna uma cópia do job sem os dados de entrada, que podem ser grandes.
+func withoutInput(job *models.Job) *models.Job {
+	masked := *job
+	masked.Input = nil
+	return &masked
+}
+
+// prefersAsync informa se o cliente pediu processamento assíncrono com "Prefer: respond-async" (RFC 7240).
+func prefersAsync(r *http.Request) bool {
+	for _, header := range r.Header.Values("Prefer") {
+		for _, preference := range strings.Split(header, ",") {
+			token, _, _ := strings.Cut(preference, ";")
+			if strings.EqualFold(strings.TrimSpace(token), "respond-async") {
+				return true
+			}
+		}
+	}
+	return false
+}
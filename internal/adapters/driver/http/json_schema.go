@@ -0,0 +1,225 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// jsonSchema é o subconjunto de JSON Schema (draft 2020-12, usado pelo OpenAPI 3.1) suportado na validação:
+// $ref para os schemas dos componentes, type (inclusive listas com "null"), enum, anyOf, properties,
+// required, additionalProperties (apenas como booleano), items, minimum, maximum, minLength, maxLength
+// e o formato date-time.
+type jsonSchema struct {
+	Ref        string                 `json:"$ref"`
+	Type       schemaTypes            `json:"type"`
+	Format     string                 `json:"format"`
+	Enum       []any                  `json:"enum"`
+	AnyOf      []*jsonSchema          `json:"anyOf"`
+	Properties map[string]*jsonSchema `json:"properties"`
+	Required   []string               `json:"required"`
+	Additional *bool                  `json:"additionalProperties"`
+	Items      *jsonSchema            `json:"items"`
+	Minimum    *float64               `json:"minimum"`
+	Maximum    *float64               `json:"maximum"`
+	MinLength  *int                   `json:"minLength"`
+	MaxLength  *int                   `json:"maxLength"`
+
+	target   *jsonSchema // Schema referenciado por Ref, preenchido por resolve.
+	resolved bool
+}
+
+// schemaTypes aceita o type do JSON Schema tanto como texto quanto como lista.
+type schemaTypes []string
+
+func (t *schemaTypes) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = schemaTypes{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("invalid schema type %s", data)
+	}
+	*t = list
+	return nil
+}
+
+// resolve associa as referências do schema e de seus subschemas aos schemas dos componentes.
+func (s *jsonSchema) resolve(components map[string]*jsonSchema) error {
+	if s == nil || s.resolved {
+		return nil
+	}
+	s.resolved = true
+
+	if s.Ref != "" {
+		target, ok := components[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+		if !ok {
+			return fmt.Errorf("unresolved OpenAPI reference %q", s.Ref)
+		}
+		s.target = target
+		return target.resolve(components)
+	}
+	for _, property := range s.Properties {
+		if err := property.resolve(components); err != nil {
+			return err
+		}
+	}
+	for _, alternative := range s.AnyOf {
+		if err := alternative.resolve(components); err != nil {
+			return err
+		}
+	}
+	return s.Items.resolve(components)
+}
+
+// validate confere um valor decodificado de JSON contra o schema, acumulando em errs
+// os erros encontrados com sua localização.
+func (s *jsonSchema) validate(value any, location string, errs *[]ProblemError) {
+	if s == nil {
+		return
+	}
+	if s.target != nil {
+		s.target.validate(value, location, errs)
+		return
+	}
+	report := func(format string, args ...any) {
+		*errs = append(*errs, ProblemError{Location: location, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(s.AnyOf) > 0 {
+		for _, alternative := range s.AnyOf {
+			var alternativeErrs []ProblemError
+			alternative.validate(value, location, &alternativeErrs)
+			if len(alternativeErrs) == 0 {
+				return
+			}
+		}
+		report("does not match any of the allowed schemas")
+		return
+	}
+	if len(s.Type) > 0 && !s.matchesType(value) {
+		report("must be of type %s", strings.Join(s.Type, " or "))
+		return
+	}
+	if len(s.Enum) > 0 && !containsValue(s.Enum, value) {
+		report("must be one of %s", formatEnum(s.Enum))
+		return
+	}
+
+	switch typed := value.(type) {
+	case string:
+		if s.MinLength != nil && len([]rune(typed)) < *s.MinLength {
+			report("must have at least %d characters", *s.MinLength)
+		}
+		if s.MaxLength != nil && len([]rune(typed)) > *s.MaxLength {
+			report("must have at most %d characters", *s.MaxLength)
+		}
+		if s.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, typed); err != nil {
+				report("must be an RFC 3339 timestamp")
+			}
+		}
+	case float64:
+		if s.Minimum != nil && typed < *s.Minimum {
+			report("must be greater than or equal to %v", *s.Minimum)
+		}
+		if s.Maximum != nil && typed > *s.Maximum {
+			report("must be less than or equal to %v", *s.Maximum)
+		}
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := typed[name]; !ok {
+				*errs = append(*errs, ProblemError{Location: location + "." + name, Message: "is required"})
+			}
+		}
+		names := make([]string, 0, len(typed))
+		for name := range typed {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if property, ok := s.Properties[name]; ok {
+				property.validate(typed[name], location+"."+name, errs)
+			} else if s.Additional != nil && !*s.Additional {
+				*errs = append(*errs, ProblemError{Location: location + "." + name, Message: "is not a known field"})
+			}
+		}
+	case []any:
+		for i, item := range typed {
+			s.Items.validate(item, fmt.Sprintf("%s[%d]", location, i), errs)
+		}
+	}
+}
+
+// matchesType informa se o valor corresponde a algum dos tipos do schema.
+func (s *jsonSchema) matchesType(value any) bool {
+	for _, typeName := range s.Type {
+		switch typed := value.(type) {
+		case nil:
+			if typeName == "null" {
+				return true
+			}
+		case bool:
+			if typeName == "boolean" {
+				return true
+			}
+		case float64:
+			if typeName == "number" || (typeName == "integer" && typed == math.Trunc(typed)) {
+				return true
+			}
+		case string:
+			if typeName == "string" {
+				return true
+			}
+		case []any:
+			if typeName == "array" {
+				return true
+			}
+		case map[string]any:
+			if typeName == "object" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// primaryType retorna o primeiro tipo do schema diferente de "null", usado para converter parâmetros.
+func (s *jsonSchema) primaryType() string {
+	if s == nil {
+		return ""
+	}
+	if s.target != nil {
+		return s.target.primaryType()
+	}
+	for _, typeName := range s.Type {
+		if typeName != "null" {
+			return typeName
+		}
+	}
+	return ""
+}
+
+func containsValue(values []any, value any) bool {
+	for _, candidate := range values {
+		if reflect.DeepEqual(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatEnum(values []any) string {
+	formatted := make([]string, len(values))
+	for i, value := range values {
+		encoded, _ := json.Marshal(value)
+		formatted[i] = string(encoded)
+	}
+	return strings.Join(formatted, ", ")
+}
@@ -0,0 +1,49 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/application"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// ActorHeader é o cabeçalho que identifica o autor de uma requisição.
+const ActorHeader = "X-Actor"
+
+// RequestContext propaga para o contexto da aplicação o ID gerado por middleware.RequestID
+// e o autor informado no cabeçalho X-Actor, usados no log de auditoria. O ID da requisição também
+// é devolvido no cabeçalho X-Request-Id, permitindo ao cliente correlacioná-lo com a auditoria.
+// Com autenticação, o autor passa a ser o principal registrado por Authenticate. Deve ser registrado
+// depois de middleware.RequestID.
+func RequestContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		w.Header().Set(middleware.RequestIDHeader, requestID)
+
+		ctx := application.WithRequestID(r.Context(), requestID)
+		if actor := r.Header.Get(ActorHeader); actor != "" {
+			ctx = application.WithActor(ctx, actor)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Deprecated marca as respostas como depreciadas (Deprecation, RFC 9745) e, se sunset não for zero,
+// informa a data de desativação (Sunset, RFC 8594). O cabeçalho Link aponta a rota equivalente da API v2.
+func Deprecated(deprecatedAt, sunset time.Time) func(http.Handler) http.Handler {
+	deprecation := "@" + strconv.FormatInt(deprecatedAt.Unix(), 10)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", deprecation)
+			if !sunset.IsZero() {
+				w.Header().Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+			}
+			successor := "/v2" + strings.TrimPrefix(r.URL.Path, "/v1")
+			w.Header().Add("Link", "<"+successor+`>; rel="successor-version"`)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package http
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// openAPIDocument é a especificação OpenAPI 3.1 da API, servida em /openapi.json. Deve descrever
+// exatamente as rotas registradas por NewRouter; um teste compara as duas.
+//
+//go:embed openapi.json
+var openAPIDocument []byte
+
+// OpenAPIHandler lida com a requisição GET /openapi.json.
+func OpenAPIHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(openAPIDocument); err != nil {
+		log.Printf("Erro ao enviar o documento OpenAPI: %v", err)
+	}
+}
+
+// openAPISpec é a parte da especificação usada na validação das requisições e respostas.
+type openAPISpec struct {
+	Paths      map[string]*openAPIPathItem `json:"paths"`
+	Components struct {
+		Schemas    map[string]*jsonSchema       `json:"schemas"`
+		Responses  map[string]*openAPIResponse  `json:"responses"`
+		Parameters map[string]*openAPIParameter `json:"parameters"`
+	} `json:"components"`
+}
+
+// openAPIPathItem reúne as operações de um caminho e os parâmetros comuns a elas.
+type openAPIPathItem struct {
+	Parameters []*openAPIParameter `json:"parameters"`
+	Get        *openAPIOperation   `json:"get"`
+	Post       *openAPIOperation   `json:"post"`
+	Put        *openAPIOperation   `json:"put"`
+	Patch      *openAPIOperation   `json:"patch"`
+	Delete     *openAPIOperation   `json:"delete"`
+}
+
+// operations retorna as operações do caminho indexadas pelo método HTTP.
+func (p *openAPIPathItem) operations() map[string]*openAPIOperation {
+	operations := make(map[string]*openAPIOperation)
+	for method, operation := range map[string]*openAPIOperation{
+		http.MethodGet: p.Get, http.MethodPost: p.Post, http.MethodPut: p.Put,
+		http.MethodPatch: p.Patch, http.MethodDelete: p.Delete,
+	} {
+		if operation != nil {
+			operations[method] = operation
+		}
+	}
+	return operations
+}
+
+type openAPIOperation struct {
+	OperationID string                      `json:"operationId"`
+	Parameters  []*openAPIParameter         `json:"parameters"`
+	RequestBody *openAPIRequestBody         `json:"requestBody"`
+	Responses   map[string]*openAPIResponse `json:"responses"`
+}
+
+type openAPIParameter struct {
+	Ref      string      `json:"$ref"`
+	Name     string      `json:"name"`
+	In       string      `json:"in"`
+	Required bool        `json:"required"`
+	Schema   *jsonSchema `json:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                         `json:"required"`
+	Content  map[string]*openAPIMediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Ref     string                       `json:"$ref"`
+	Content map[string]*openAPIMediaType `json:"content"`
+}
+
+type openAPIMediaType struct {
+	Schema *jsonSchema `json:"schema"`
+}
+
+// parseOpenAPISpec lê a especificação e resolve as referências a parâmetros, respostas e schemas dos componentes.
+func parseOpenAPISpec(document []byte) (*openAPISpec, error) {
+	var spec openAPISpec
+	if err := json.Unmarshal(document, &spec); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+
+	resolveParameters := func(parameters []*openAPIParameter) error {
+		for i, parameter := range parameters {
+			if parameter.Ref == "" {
+				continue
+			}
+			resolved, ok := spec.Components.Parameters[strings.TrimPrefix(parameter.Ref, "#/components/parameters/")]
+			if !ok {
+				return fmt.Errorf("unresolved OpenAPI reference %q", parameter.Ref)
+			}
+			parameters[i] = resolved
+		}
+		return nil
+	}
+
+	for path, item := range spec.Paths {
+		if err := resolveParameters(item.Parameters); err != nil {
+			return nil, err
+		}
+		for method, operation := range item.operations() {
+			if err := resolveParameters(operation.Parameters); err != nil {
+				return nil, err
+			}
+			for status, response := range operation.Responses {
+				if response.Ref == "" {
+					continue
+				}
+				resolved, ok := spec.Components.Responses[strings.TrimPrefix(response.Ref, "#/components/responses/")]
+				if !ok {
+					return nil, fmt.Errorf("%s %s: unresolved OpenAPI reference %q", method, path, response.Ref)
+				}
+				operation.Responses[status] = resolved
+			}
+		}
+	}
+
+	// Os schemas são percorridos uma única vez, a partir das raízes: componentes, parâmetros e conteúdos.
+	var roots []*jsonSchema
+	for _, schema := range spec.Components.Schemas {
+		roots = append(roots, schema)
+	}
+	for _, parameter := range spec.Components.Parameters {
+		roots = append(roots, parameter.Schema)
+	}
+	for _, response := range spec.Components.Responses {
+		for _, media := range response.Content {
+			roots = append(roots, media.Schema)
+		}
+	}
+	for _, item := range spec.Paths {
+		for _, parameter := range item.Parameters {
+			roots = append(roots, parameter.Schema)
+		}
+		for _, operation := range item.operations() {
+			for _, parameter := range operation.Parameters {
+				roots = append(roots, parameter.Schema)
+			}
+			if operation.RequestBody != nil {
+				for _, media := range operation.RequestBody.Content {
+					roots = append(roots, media.Schema)
+				}
+			}
+			for _, response := range operation.Responses {
+				for _, media := range response.Content {
+					roots = append(roots, media.Schema)
+				}
+			}
+		}
+	}
+	for _, schema := range roots {
+		if err := schema.resolve(spec.Components.Schemas); err != nil {
+			return nil, err
+		}
+	}
+	return &spec, nil
+}
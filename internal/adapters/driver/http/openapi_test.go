@@ -0,0 +1,177 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	httpDriver "github.com/danielrios/product-service-go/internal/adapters/driver/http"
+
+	"github.com/danielrios/product-service-go/internal/adapters/driven/memdb"
+	"github.com/danielrios/product-service-go/internal/application"
+)
+
+// newTestRouter cria o roteador com serviços em memória.
+func newTestRouter(t *testing.T, opts ...httpDriver.RouterOption) http.Handler {
+	t.Helper()
+	repo := memdb.NewInMemoryProductRepository()
+	service := application.NewProductService(repo, application.WithImports(repo))
+	return httpDriver.NewRouter(httpDriver.Handlers{
+		Products:    httpDriver.NewProductHandler(service),
+		ProductsV2:  httpDriver.NewProductHandler(service, httpDriver.WithAPIVersion(httpDriver.APIVersion2)),
+		Idempotency: application.NewIdempotencyService(memdb.NewInMemoryIdempotencyRepository(), application.DefaultIdempotencyConfig()),
+	}, opts...)
+}
+
+func serve(handler http.Handler, method, target, contentType, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestOpenAPIDocumentMatchesRoutes(t *testing.T) {
+	router := httpDriver.NewRouter(httpDriver.Handlers{})
+
+	rec := serve(router, http.MethodGet, "/openapi.json", "", "")
+	var document struct {
+		OpenAPI string                                `json:"openapi"`
+		Paths   map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &document); err != nil {
+		t.Fatalf("Expected a valid OpenAPI document, got %v", err)
+	}
+	if document.OpenAPI != "3.1.0" {
+		t.Errorf("Expected OpenAPI 3.1.0, got %q", document.OpenAPI)
+	}
+
+	var documented []string
+	for path, item := range document.Paths {
+		for method := range item {
+			if method != "parameters" {
+				documented = append(documented, strings.ToUpper(method)+" "+path)
+			}
+		}
+	}
+	var routed []string
+	err := chi.Walk(router, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if route != "/" {
+			route = strings.TrimSuffix(route, "/")
+		}
+		// As rotas sem prefixo de versão são aliases das de /v1, documentadas apenas uma vez.
+		if !strings.HasPrefix(route, "/v1/") && !strings.HasPrefix(route, "/v2/") && route != "/openapi.json" {
+			route = "/v1" + route
+		}
+		if slices.Contains(routed, method+" "+route) {
+			return nil
+		}
+		routed = append(routed, method+" "+route)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error walking the routes, got %v", err)
+	}
+
+	slices.Sort(documented)
+	slices.Sort(routed)
+	for _, route := range routed {
+		if !slices.Contains(documented, route) {
+			t.Errorf("Route %s is not documented in openapi.json", route)
+		}
+	}
+	for _, route := range documented {
+		if !slices.Contains(routed, route) {
+			t.Errorf("Documented operation %s has no route", route)
+		}
+	}
+}
+
+func TestOpenAPIValidator(t *testing.T) {
+	validator, err := httpDriver.NewOpenAPIValidator()
+	if err != nil {
+		t.Fatalf("Expected the embedded document to load, got %v", err)
+	}
+	router := newTestRouter(t, httpDriver.WithOpenAPIValidation(validator))
+
+	t.Run("Rejects Invalid Bodies With Locations", func(t *testing.T) {
+		rec := serve(router, http.MethodPost, "/products", "application/json", `{"Name": 5}`)
+
+		var problem httpDriver.Problem
+		_ = json.Unmarshal(rec.Body.Bytes(), &problem)
+		if rec.Code != http.StatusBadRequest || problem.Code != "request-validation-failed" {
+			t.Fatalf("Expected 400 request-validation-failed, got %d %s", rec.Code, rec.Body)
+		}
+		locations := make([]string, len(problem.Errors))
+		for i, problemErr := range problem.Errors {
+			locations[i] = problemErr.Location
+		}
+		if !slices.Equal(locations, []string{"body.ID", "body.Name", "body.Price"}) {
+			t.Errorf("Expected errors at body.ID, body.Name and body.Price, got %+v", problem.Errors)
+		}
+	})
+
+	t.Run("Rejects Invalid Query Parameters", func(t *testing.T) {
+		rec := serve(router, http.MethodGet, "/products/stats?group_by=year", "", "")
+
+		if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), `"query.group_by"`) {
+			t.Errorf("Expected 400 for query.group_by, got %d %s", rec.Code, rec.Body)
+		}
+	})
+
+	t.Run("Rejects Unknown Fields", func(t *testing.T) {
+		rec := serve(router, http.MethodPost, "/v2/products", "application/json", `{"id": "1", "name": "A", "price": 1, "prce": 1}`)
+
+		if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), `"body.prce"`) {
+			t.Errorf("Expected 400 for body.prce, got %d %s", rec.Code, rec.Body)
+		}
+	})
+
+	t.Run("Accepts Valid Requests", func(t *testing.T) {
+		rec := serve(router, http.MethodPost, "/products", "application/json", `{"ID": "1", "Name": "Product 1", "Price": 10}`)
+
+		if rec.Code != http.StatusCreated {
+			t.Errorf("Expected 201, got %d %s", rec.Code, rec.Body)
+		}
+	})
+}
+
+func TestProblemResponses(t *testing.T) {
+	router := newTestRouter(t)
+
+	t.Run("Malformed JSON Is A Bad Request", func(t *testing.T) {
+		rec := serve(router, http.MethodPost, "/products", "application/json", `{"ID":`)
+
+		if rec.Code != http.StatusBadRequest || rec.Header().Get("Content-Type") != httpDriver.ProblemContentType {
+			t.Errorf("Expected a 400 problem, got %d %s", rec.Code, rec.Header().Get("Content-Type"))
+		}
+		if !strings.Contains(rec.Body.String(), `"code":"invalid-request-body"`) {
+			t.Errorf("Expected code invalid-request-body, got %s", rec.Body)
+		}
+	})
+
+	t.Run("Domain Errors Use The Catalog", func(t *testing.T) {
+		rec := serve(router, http.MethodGet, "/products/missing", "", "")
+
+		var problem httpDriver.Problem
+		_ = json.Unmarshal(rec.Body.Bytes(), &problem)
+		if problem.Status != http.StatusNotFound || problem.Code != "product-not-found" || problem.Instance == "" {
+			t.Errorf("Unexpected problem: %+v", problem)
+		}
+	})
+
+	t.Run("Unknown Methods List The Allowed Ones", func(t *testing.T) {
+		rec := serve(router, http.MethodPost, "/products/1", "", "")
+
+		if rec.Code != http.StatusMethodNotAllowed || !slices.Contains(rec.Header().Values("Allow"), http.MethodPatch) {
+			t.Errorf("Expected 405 allowing PATCH, got %d %v", rec.Code, rec.Header().Values("Allow"))
+		}
+	})
+}
@@ -0,0 +1,320 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxValidatedResponseBytes limita a cópia das respostas guardada para validação. Respostas maiores,
+// como exportações do catálogo, são enviadas normalmente, mas não são validadas.
+const maxValidatedResponseBytes = 1 << 20
+
+// OpenAPIValidator valida requisições e respostas contra a especificação servida em /openapi.json.
+type OpenAPIValidator struct {
+	routes []openAPIRoute
+}
+
+// openAPIRoute é um caminho da especificação dividido em segmentos; os parâmetros ficam entre chaves.
+type openAPIRoute struct {
+	segments []string
+	literals int
+	item     *openAPIPathItem
+}
+
+// NewOpenAPIValidator cria um validador a partir da especificação embutida no serviço. Os caminhos de /v1
+// também valem sem o prefixo de versão, como no roteador.
+func NewOpenAPIValidator() (*OpenAPIValidator, error) {
+	spec, err := parseOpenAPISpec(openAPIDocument)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &OpenAPIValidator{}
+	for path, item := range spec.Paths {
+		v.addRoute(path, item)
+		if alias, ok := strings.CutPrefix(path, "/v1/"); ok {
+			v.addRoute("/"+alias, item)
+		}
+	}
+	return v, nil
+}
+
+func (v *OpenAPIValidator) addRoute(path string, item *openAPIPathItem) {
+	route := openAPIRoute{segments: splitPath(path), item: item}
+	for _, segment := range route.segments {
+		if !isPathParameter(segment) {
+			route.literals++
+		}
+	}
+	v.routes = append(v.routes, route)
+}
+
+// Middleware rejeita com 400 as requisições que violam a especificação (parâmetros e corpos JSON), com a
+// localização de cada erro, e registra no log as respostas fora do contrato. As respostas já foram enviadas
+// quando são validadas, então as violações apenas são registradas. Requisições para caminhos ou métodos
+// ausentes da especificação seguem sem validação, para que o roteador responda 404 ou 405.
+func (v *OpenAPIValidator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		item, pathParams := v.findPath(r.URL.Path)
+		if item == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		operation := item.operations()[r.Method]
+		if operation == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		errs, err := validateRequest(r, item, operation, pathParams)
+		if err != nil {
+			writeErrorResponse(w, r, err)
+			return
+		}
+		if len(errs) > 0 {
+			writeErrorResponse(w, r, &validationError{kind: errRequestValidation, errors: errs})
+			return
+		}
+
+		recorder := &validatingResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		if err := validateResponse(operation, recorder); err != nil {
+			log.Printf("Resposta fora da especificação OpenAPI em %s %s: %v", r.Method, r.URL.Path, err)
+		}
+	})
+}
+
+// findPath encontra o caminho da especificação que corresponde à requisição, preferindo o que tem mais
+// segmentos literais (por exemplo, /products/stats em vez de /products/{id}).
+func (v *OpenAPIValidator) findPath(path string) (*openAPIPathItem, map[string]string) {
+	segments := splitPath(path)
+
+	var best *openAPIRoute
+	for i := range v.routes {
+		route := &v.routes[i]
+		if len(route.segments) != len(segments) || (best != nil && route.literals <= best.literals) {
+			continue
+		}
+		matches := true
+		for j, segment := range route.segments {
+			if !isPathParameter(segment) && segment != segments[j] {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			best = route
+		}
+	}
+	if best == nil {
+		return nil, nil
+	}
+
+	params := make(map[string]string)
+	for i, segment := range best.segments {
+		if isPathParameter(segment) {
+			params[strings.Trim(segment, "{}")] = segments[i]
+		}
+	}
+	return best.item, params
+}
+
+// validateRequest confere os parâmetros e, para conteúdos JSON, o corpo da requisição. O corpo lido
+// é devolvido à requisição para o handler.
+func validateRequest(r *http.Request, item *openAPIPathItem, operation *openAPIOperation, pathParams map[string]string) ([]ProblemError, error) {
+	var errs []ProblemError
+
+	// Parâmetros da operação substituem os de mesmo nome e localização definidos no caminho.
+	parameters := make(map[string]*openAPIParameter)
+	for _, parameter := range append(append([]*openAPIParameter{}, item.Parameters...), operation.Parameters...) {
+		parameters[parameter.In+"."+parameter.Name] = parameter
+	}
+	query := r.URL.Query()
+	for location, parameter := range parameters {
+		var raw string
+		switch parameter.In {
+		case "path":
+			raw = pathParams[parameter.Name]
+		case "query":
+			raw = query.Get(parameter.Name)
+		case "header":
+			raw = r.Header.Get(parameter.Name)
+		}
+		if raw == "" {
+			if parameter.Required {
+				errs = append(errs, ProblemError{Location: location, Message: "is required"})
+			}
+			continue
+		}
+		value, err := parseParameterValue(raw, parameter.Schema.primaryType())
+		if err != nil {
+			errs = append(errs, ProblemError{Location: location, Message: err.Error()})
+			continue
+		}
+		parameter.Schema.validate(value, location, &errs)
+	}
+
+	if operation.RequestBody == nil {
+		return sortProblemErrors(errs), nil
+	}
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	media, ok := operation.RequestBody.Content[mediaType]
+	if !ok || !isJSONMediaType(mediaType) {
+		// Outros tipos de conteúdo são tratados pelos próprios handlers, que respondem 415 quando necessário.
+		return sortProblemErrors(errs), nil
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, readBodyError(err)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		if operation.RequestBody.Required {
+			errs = append(errs, ProblemError{Location: "body", Message: "is required"})
+		}
+		return sortProblemErrors(errs), nil
+	}
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		errs = append(errs, ProblemError{Location: "body", Message: "is not valid JSON: " + err.Error()})
+		return sortProblemErrors(errs), nil
+	}
+	media.Schema.validate(value, "body", &errs)
+	return sortProblemErrors(errs), nil
+}
+
+// validateResponse confere o status, o tipo de conteúdo e, para respostas JSON, o corpo da resposta.
+func validateResponse(operation *openAPIOperation, recorder *validatingResponseWriter) error {
+	response, ok := operation.Responses[strconv.Itoa(recorder.status)]
+	if !ok {
+		return fmt.Errorf("status %d is not documented", recorder.status)
+	}
+	if len(response.Content) == 0 {
+		return nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(recorder.Header().Get("Content-Type"))
+	media, ok := response.Content[mediaType]
+	if !ok {
+		return fmt.Errorf("content type %q is not documented for status %d", mediaType, recorder.status)
+	}
+	if !recorder.capturing || recorder.overflow {
+		return nil
+	}
+
+	var value any
+	if err := json.Unmarshal(recorder.body.Bytes(), &value); err != nil {
+		return fmt.Errorf("body is not valid JSON: %w", err)
+	}
+	var errs []ProblemError
+	media.Schema.validate(value, "body", &errs)
+	if len(errs) > 0 {
+		return &validationError{kind: fmt.Errorf("status %d", recorder.status), errors: sortProblemErrors(errs)}
+	}
+	return nil
+}
+
+// parseParameterValue converte o valor textual de um parâmetro para o tipo declarado no schema.
+func parseParameterValue(raw, typeName string) (any, error) {
+	switch typeName {
+	case "integer":
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("must be an integer")
+		}
+		return float64(value), nil
+	case "number":
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("must be a number")
+		}
+		return value, nil
+	case "boolean":
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("must be a boolean")
+		}
+		return value, nil
+	}
+	return raw, nil
+}
+
+// sortProblemErrors ordena os erros pela localização, para respostas determinísticas.
+func sortProblemErrors(errs []ProblemError) []ProblemError {
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Location < errs[j].Location })
+	return errs
+}
+
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func isPathParameter(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+// splitPath divide o caminho em segmentos, ignorando a barra final, como o roteador.
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+// validatingResponseWriter repassa a resposta ao cliente e guarda uma cópia dos corpos JSON para validação.
+// Implementa Flush e Unwrap, para não atrapalhar respostas em streaming como as de Server-Sent Events.
+type validatingResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	capturing   bool
+	overflow    bool
+	body        bytes.Buffer
+}
+
+func (w *validatingResponseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+		mediaType, _, _ := mime.ParseMediaType(w.Header().Get("Content-Type"))
+		w.capturing = isJSONMediaType(mediaType)
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *validatingResponseWriter) Write(data []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.capturing && !w.overflow {
+		if w.body.Len()+len(data) > maxValidatedResponseBytes {
+			w.overflow = true
+			w.body.Reset()
+		} else {
+			w.body.Write(data)
+		}
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *validatingResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *validatingResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
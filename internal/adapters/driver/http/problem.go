@@ -0,0 +1,264 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// ProblemContentType é o media type das respostas de erro (RFC 7807).
+const ProblemContentType = "application/problem+json"
+
+// problemTypeBase prefixa o código do problema para formar o URI do campo type.
+const problemTypeBase = "urn:product-service:problem:"
+
+// Problem é o corpo das respostas de erro no formato application/problem+json (RFC 7807).
+// Code é uma extensão com o código estável do erro, adequado para tratamento automático pelos clientes;
+// Errors, outra extensão, detalha os erros de validação com a localização de cada um.
+type Problem struct {
+	Type     string         `json:"type"`
+	Title    string         `json:"title"`
+	Status   int            `json:"status"`
+	Detail   string         `json:"detail,omitempty"`
+	Instance string         `json:"instance,omitempty"`
+	Code     string         `json:"code"`
+	Errors   []ProblemError `json:"errors,omitempty"`
+}
+
+// ProblemError é um erro de validação localizado, como "body.Price" ou "query.limit".
+type ProblemError struct {
+	Location string `json:"location"`
+	Message  string `json:"message"`
+}
+
+// validationError reúne os erros de validação de uma requisição. Unwrap devolve a categoria do erro,
+// usada na busca do catálogo.
+type validationError struct {
+	kind   error
+	errors []ProblemError
+}
+
+func (e *validationError) Error() string {
+	messages := make([]string, len(e.errors))
+	for i, problemErr := range e.errors {
+		messages[i] = problemErr.Location + ": " + problemErr.Message
+	}
+	return e.kind.Error() + ": " + strings.Join(messages, "; ")
+}
+
+func (e *validationError) Unwrap() error {
+	return e.kind
+}
+
+// problemType descreve uma categoria de erro da API: o código estável, o título e o status HTTP.
+type problemType struct {
+	code   string
+	title  string
+	status int
+}
+
+// Erros do próprio adaptador HTTP: nenhuma rota atende a requisição, ou a requisição viola a especificação OpenAPI.
+var (
+	errRouteNotFound     = errors.New("no route matches the request path")
+	errMethodNotAllowed  = errors.New("method not allowed for this route")
+	errRequestValidation = errors.New("request does not match the API specification")
+)
+
+// internalProblem é usado para os erros que não constam do catálogo. O detalhe não é exposto ao cliente.
+var internalProblem = problemType{"internal-error", "Internal server error", http.StatusInternalServerError}
+
+// problemCatalog associa os erros de domínio aos problemas expostos pela API. Os códigos fazem parte
+// do contrato público: podem ser acrescentados, mas não renomeados. A busca usa errors.Is na ordem abaixo.
+var problemCatalog = []struct {
+	err     error
+	problem problemType
+}{
+	{models.ErrRequestBodyTooLarge, problemType{"request-body-too-large", "Request body too large", http.StatusRequestEntityTooLarge}},
+	{models.ErrUnsupportedContentType, problemType{"unsupported-content-type", "Unsupported content type", http.StatusUnsupportedMediaType}},
+	{models.ErrUnsupportedContentEncoding, problemType{"unsupported-content-encoding", "Unsupported content encoding", http.StatusUnsupportedMediaType}},
+	{models.ErrDecompressionLimitExceeded, problemType{"decompression-limit-exceeded", "Decompressed request body too large", http.StatusRequestEntityTooLarge}},
+	{models.ErrNotAcceptable, problemType{"not-acceptable", "Not acceptable", http.StatusNotAcceptable}},
+	{models.ErrInvalidRequestBody, problemType{"invalid-request-body", "Malformed request body", http.StatusBadRequest}},
+
+	{models.ErrUnauthenticated, problemType{"unauthenticated", "Authentication required", http.StatusUnauthorized}},
+	{models.ErrUnsupportedAuthType, problemType{"unsupported-authorization-scheme", "Unsupported authorization scheme", http.StatusUnauthorized}},
+	{models.ErrInvalidCredentials, problemType{"invalid-credentials", "Invalid credentials", http.StatusUnauthorized}},
+	{models.ErrInsufficientScope, problemType{"insufficient-scope", "Insufficient scope", http.StatusForbidden}},
+	{models.ErrForbidden, problemType{"forbidden", "Forbidden", http.StatusForbidden}},
+	{models.ErrForbiddenFields, problemType{"forbidden-fields", "Forbidden product fields", http.StatusForbidden}},
+
+	{models.ErrAPIKeyNotFound, problemType{"api-key-not-found", "API key not found", http.StatusNotFound}},
+	{models.ErrInvalidAPIKey, problemType{"invalid-api-key", "Invalid API key", http.StatusBadRequest}},
+
+	{models.ErrProductNotFound, problemType{"product-not-found", "Product not found", http.StatusNotFound}},
+	{models.ErrProductAlreadyExists, problemType{"product-already-exists", "Product already exists", http.StatusConflict}},
+	{models.ErrInvalidProductID, problemType{"invalid-product-id", "Invalid product ID", http.StatusBadRequest}},
+	{models.ErrProductIDMismatch, problemType{"product-id-mismatch", "Product ID mismatch", http.StatusBadRequest}},
+	{models.ErrProductModified, problemType{"product-modified", "Product modified concurrently", http.StatusConflict}},
+	{models.ErrInvalidProductQuery, problemType{"invalid-product-query", "Invalid product query", http.StatusBadRequest}},
+	{models.ErrInvalidProductFields, problemType{"invalid-product-fields", "Invalid product fields", http.StatusBadRequest}},
+	{models.ErrInvalidProductExpansion, problemType{"invalid-product-expansion", "Invalid product expansion", http.StatusBadRequest}},
+	{models.ErrInvalidAsOf, problemType{"invalid-as-of", "Invalid as_of timestamp", http.StatusBadRequest}},
+	{models.ErrHistoryNotConfigured, problemType{"history-not-available", "Product history not available", http.StatusNotImplemented}},
+
+	{models.ErrInvalidPatch, problemType{"invalid-patch", "Invalid patch document", http.StatusBadRequest}},
+	{models.ErrPatchConflict, problemType{"patch-conflict", "Patch conflicts with the current product", http.StatusConflict}},
+	{models.ErrUnsupportedPatchType, problemType{"unsupported-patch-type", "Unsupported patch media type", http.StatusUnsupportedMediaType}},
+	{models.ErrImmutableProductField, problemType{"immutable-product-field", "Immutable product field", http.StatusBadRequest}},
+
+	{models.ErrInvalidExportFormat, problemType{"invalid-export-format", "Invalid export format", http.StatusBadRequest}},
+	{models.ErrInvalidImportOptions, problemType{"invalid-import-options", "Invalid import options", http.StatusBadRequest}},
+	{models.ErrInvalidImportFile, problemType{"invalid-import-file", "Invalid import file", http.StatusBadRequest}},
+	{models.ErrImportNotConfigured, problemType{"import-not-available", "Product import not available", http.StatusNotImplemented}},
+
+	{models.ErrWebhookNotFound, problemType{"webhook-not-found", "Webhook subscription not found", http.StatusNotFound}},
+	{models.ErrWebhookDeliveryNotFound, problemType{"webhook-delivery-not-found", "Webhook delivery not found", http.StatusNotFound}},
+	{models.ErrInvalidWebhookURL, problemType{"invalid-webhook-url", "Invalid webhook URL", http.StatusBadRequest}},
+	{models.ErrInvalidWebhookSecret, problemType{"invalid-webhook-secret", "Invalid webhook secret", http.StatusBadRequest}},
+	{models.ErrInvalidEventType, problemType{"invalid-event-type", "Invalid event type", http.StatusBadRequest}},
+	{models.ErrInvalidDeliveryQuery, problemType{"invalid-delivery-query", "Invalid delivery query", http.StatusBadRequest}},
+
+	{models.ErrInvalidChangeID, problemType{"invalid-change-id", "Invalid change notification ID", http.StatusBadRequest}},
+	{models.ErrInvalidChangeQuery, problemType{"invalid-change-query", "Invalid change feed query", http.StatusBadRequest}},
+	{models.ErrInvalidAuditQuery, problemType{"invalid-audit-query", "Invalid audit query", http.StatusBadRequest}},
+
+	{models.ErrInvalidIdempotencyKey, problemType{"invalid-idempotency-key", "Invalid Idempotency-Key", http.StatusBadRequest}},
+	{models.ErrIdempotencyKeyReused, problemType{"idempotency-key-reused", "Idempotency-Key reused", http.StatusUnprocessableEntity}},
+	{models.ErrIdempotencyRequestInFlight, problemType{"idempotency-request-in-flight", "Request still in progress", http.StatusConflict}},
+
+	{models.ErrJobNotFound, problemType{"job-not-found", "Job not found", http.StatusNotFound}},
+	{models.ErrJobAlreadyFinished, problemType{"job-already-finished", "Job already finished", http.StatusConflict}},
+	{models.ErrJobCancelled, problemType{"job-cancelled", "Job cancelled", http.StatusConflict}},
+	{models.ErrJobInterrupted, problemType{"job-interrupted", "Job interrupted by shutdown", http.StatusServiceUnavailable}},
+	{models.ErrUnknownJobType, problemType{"unknown-job-type", "Unknown job type", http.StatusBadRequest}},
+	{models.ErrJobsNotConfigured, problemType{"jobs-not-available", "Asynchronous jobs not available", http.StatusNotImplemented}},
+
+	{errRouteNotFound, problemType{"route-not-found", "Route not found", http.StatusNotFound}},
+	{errMethodNotAllowed, problemType{"method-not-allowed", "Method not allowed", http.StatusMethodNotAllowed}},
+	{errRequestValidation, problemType{"request-validation-failed", "Request validation failed", http.StatusBadRequest}},
+}
+
+// lookupProblem retorna o problema do catálogo correspondente ao erro.
+func lookupProblem(err error) (problemType, bool) {
+	for _, entry := range problemCatalog {
+		if errors.Is(err, entry.err) {
+			return entry.problem, true
+		}
+	}
+	return internalProblem, false
+}
+
+// newProblem monta o corpo da resposta de erro. O instance é o ID da requisição, o mesmo devolvido
+// em X-Request-Id e gravado na auditoria, para que o cliente possa citá-lo ao reportar o erro.
+func newProblem(r *http.Request, err error) *Problem {
+	// Um corpo acima do limite de MaxBodyBytes pode chegar embrulhado em outros erros, como os de leitura
+	// do corpo ou de importação; o limite excedido prevalece.
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		err = fmt.Errorf("%w: the limit is %d bytes", models.ErrRequestBodyTooLarge, tooLarge.Limit)
+	}
+
+	// O mesmo vale para os limites da descompressão de corpos, verificados durante a leitura.
+	var decompressionErr *decompressionLimitError
+	if errors.As(err, &decompressionErr) {
+		err = decompressionErr
+	}
+
+	problemType, known := lookupProblem(err)
+	detail := "internal server error"
+	if known {
+		detail = err.Error()
+	} else {
+		log.Printf("Erro interno não mapeado no handler: %v", err)
+	}
+
+	problem := &Problem{
+		Type:     problemTypeBase + problemType.code,
+		Title:    problemType.title,
+		Status:   problemType.status,
+		Detail:   detail,
+		Instance: middleware.GetReqID(r.Context()),
+		Code:     problemType.code,
+	}
+	var validationErr *validationError
+	if errors.As(err, &validationErr) {
+		problem.Errors = validationErr.errors
+	}
+	var fieldsErr *models.ForbiddenFieldsError
+	if errors.As(err, &fieldsErr) {
+		message := "the principal's roles do not allow reading this field"
+		if fieldsErr.Access == models.FieldAccessWrite {
+			message = "the principal's roles do not allow changing this field"
+		}
+		for _, field := range fieldsErr.Fields {
+			problem.Errors = append(problem.Errors, ProblemError{Location: "product." + string(field), Message: message})
+		}
+	}
+	return problem
+}
+
+// writeErrorResponse é um helper para enviar respostas de erro padronizadas no formato problem+json.
+func writeErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
+	problem := newProblem(r, err)
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(problem.Status)
+	if err := json.NewEncoder(w).Encode(problem); err != nil {
+		log.Printf("Erro ao codificar resposta de erro: %v", err)
+	}
+}
+
+// NotFound responde com problem+json às requisições que não correspondem a nenhuma rota.
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	writeErrorResponse(w, r, errRouteNotFound)
+}
+
+// MethodNotAllowed responde com problem+json às requisições com um método não suportado pela rota.
+// Como o handler padrão do chi, informa no cabeçalho Allow os métodos aceitos pelo caminho.
+func MethodNotAllowed(routes chi.Routes) http.HandlerFunc {
+	var (
+		once sync.Once
+		flat *chi.Mux
+	)
+	return func(w http.ResponseWriter, r *http.Request) {
+		// As rotas são registradas depois da criação do handler, então a tabela é montada no primeiro uso.
+		once.Do(func() { flat = flattenRoutes(routes) })
+
+		for _, method := range []string{
+			http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+			http.MethodPatch, http.MethodDelete, http.MethodOptions,
+		} {
+			if flat.Match(chi.NewRouteContext(), method, r.URL.Path) {
+				w.Header().Add("Allow", method)
+			}
+		}
+		writeErrorResponse(w, r, errMethodNotAllowed)
+	}
+}
+
+// flattenRoutes copia as rotas, inclusive as dos sub-roteadores, para um único roteador sem aninhamento,
+// pois a busca do chi não distingue os métodos de rotas montadas em sub-roteadores. As rotas registradas
+// como "/" em um sub-roteador também atendem o caminho sem a barra final.
+func flattenRoutes(routes chi.Routes) *chi.Mux {
+	flat := chi.NewRouter()
+	noop := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+	err := chi.Walk(routes, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		flat.Method(method, route, noop)
+		if trimmed := strings.TrimSuffix(route, "/"); trimmed != "" && trimmed != route {
+			flat.Method(method, trimmed, noop)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Erro ao percorrer as rotas: %v", err)
+	}
+	return flat
+}
@@ -0,0 +1,172 @@
+package http_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	httpDriver "github.com/danielrios/product-service-go/internal/adapters/driver/http"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+// decodeProblem verifica o tipo de conteúdo e o status da resposta e decodifica o problema.
+func decodeProblem(t *testing.T, rec *httptest.ResponseRecorder, status int) httpDriver.Problem {
+	t.Helper()
+	if rec.Code != status {
+		t.Fatalf("Expected status %d, got %d %s", status, rec.Code, rec.Body)
+	}
+	if contentType := rec.Header().Get("Content-Type"); contentType != httpDriver.ProblemContentType {
+		t.Errorf("Expected Content-Type %s, got %q", httpDriver.ProblemContentType, contentType)
+	}
+	var problem httpDriver.Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("Expected a problem document, got %s: %v", rec.Body, err)
+	}
+	if problem.Status != status || problem.Type != "urn:product-service:problem:"+problem.Code || problem.Title == "" {
+		t.Errorf("Inconsistent problem: %+v", problem)
+	}
+	return problem
+}
+
+func TestProblemCatalog(t *testing.T) {
+	// Os códigos e status fazem parte do contrato público da API e não devem mudar.
+	tests := []struct {
+		err    error
+		code   string
+		status int
+	}{
+		{models.ErrRequestBodyTooLarge, "request-body-too-large", http.StatusRequestEntityTooLarge},
+		{models.ErrUnsupportedContentType, "unsupported-content-type", http.StatusUnsupportedMediaType},
+		{models.ErrUnsupportedContentEncoding, "unsupported-content-encoding", http.StatusUnsupportedMediaType},
+		{models.ErrDecompressionLimitExceeded, "decompression-limit-exceeded", http.StatusRequestEntityTooLarge},
+		{models.ErrNotAcceptable, "not-acceptable", http.StatusNotAcceptable},
+		{models.ErrInvalidRequestBody, "invalid-request-body", http.StatusBadRequest},
+		{models.ErrUnauthenticated, "unauthenticated", http.StatusUnauthorized},
+		{models.ErrUnsupportedAuthType, "unsupported-authorization-scheme", http.StatusUnauthorized},
+		{models.ErrInvalidCredentials, "invalid-credentials", http.StatusUnauthorized},
+		{models.ErrInsufficientScope, "insufficient-scope", http.StatusForbidden},
+		{models.ErrForbidden, "forbidden", http.StatusForbidden},
+		{models.ErrForbiddenFields, "forbidden-fields", http.StatusForbidden},
+		{models.ErrAPIKeyNotFound, "api-key-not-found", http.StatusNotFound},
+		{models.ErrInvalidAPIKey, "invalid-api-key", http.StatusBadRequest},
+		{models.ErrProductNotFound, "product-not-found", http.StatusNotFound},
+		{models.ErrProductAlreadyExists, "product-already-exists", http.StatusConflict},
+		{models.ErrInvalidProductID, "invalid-product-id", http.StatusBadRequest},
+		{models.ErrProductIDMismatch, "product-id-mismatch", http.StatusBadRequest},
+		{models.ErrProductModified, "product-modified", http.StatusConflict},
+		{models.ErrInvalidProductQuery, "invalid-product-query", http.StatusBadRequest},
+		{models.ErrInvalidProductFields, "invalid-product-fields", http.StatusBadRequest},
+		{models.ErrInvalidProductExpansion, "invalid-product-expansion", http.StatusBadRequest},
+		{models.ErrInvalidAsOf, "invalid-as-of", http.StatusBadRequest},
+		{models.ErrHistoryNotConfigured, "history-not-available", http.StatusNotImplemented},
+		{models.ErrInvalidPatch, "invalid-patch", http.StatusBadRequest},
+		{models.ErrPatchConflict, "patch-conflict", http.StatusConflict},
+		{models.ErrUnsupportedPatchType, "unsupported-patch-type", http.StatusUnsupportedMediaType},
+		{models.ErrImmutableProductField, "immutable-product-field", http.StatusBadRequest},
+		{models.ErrInvalidExportFormat, "invalid-export-format", http.StatusBadRequest},
+		{models.ErrInvalidImportOptions, "invalid-import-options", http.StatusBadRequest},
+		{models.ErrInvalidImportFile, "invalid-import-file", http.StatusBadRequest},
+		{models.ErrImportNotConfigured, "import-not-available", http.StatusNotImplemented},
+		{models.ErrWebhookNotFound, "webhook-not-found", http.StatusNotFound},
+		{models.ErrWebhookDeliveryNotFound, "webhook-delivery-not-found", http.StatusNotFound},
+		{models.ErrInvalidWebhookURL, "invalid-webhook-url", http.StatusBadRequest},
+		{models.ErrInvalidWebhookSecret, "invalid-webhook-secret", http.StatusBadRequest},
+		{models.ErrInvalidEventType, "invalid-event-type", http.StatusBadRequest},
+		{models.ErrInvalidDeliveryQuery, "invalid-delivery-query", http.StatusBadRequest},
+		{models.ErrInvalidChangeID, "invalid-change-id", http.StatusBadRequest},
+		{models.ErrInvalidChangeQuery, "invalid-change-query", http.StatusBadRequest},
+		{models.ErrInvalidAuditQuery, "invalid-audit-query", http.StatusBadRequest},
+		{models.ErrInvalidIdempotencyKey, "invalid-idempotency-key", http.StatusBadRequest},
+		{models.ErrIdempotencyKeyReused, "idempotency-key-reused", http.StatusUnprocessableEntity},
+		{models.ErrIdempotencyRequestInFlight, "idempotency-request-in-flight", http.StatusConflict},
+		{models.ErrJobNotFound, "job-not-found", http.StatusNotFound},
+		{models.ErrJobAlreadyFinished, "job-already-finished", http.StatusConflict},
+		{models.ErrJobCancelled, "job-cancelled", http.StatusConflict},
+		{models.ErrJobInterrupted, "job-interrupted", http.StatusServiceUnavailable},
+		{models.ErrUnknownJobType, "unknown-job-type", http.StatusBadRequest},
+		{models.ErrJobsNotConfigured, "jobs-not-available", http.StatusNotImplemented},
+	}
+	// Erros do próprio adaptador, cobertos pelos testes do roteador.
+	tested := map[string]bool{"route-not-found": true, "method-not-allowed": true, "request-validation-failed": true}
+
+	for _, tt := range tests {
+		tested[tt.code] = true
+		t.Run(tt.code, func(t *testing.T) {
+			// Os erros chegam embrulhados com contexto, como nos serviços.
+			err := fmt.Errorf("%w: some context", tt.err)
+			rec := httptest.NewRecorder()
+			httpDriver.WriteErrorResponse(rec, httptest.NewRequest(http.MethodGet, "/", nil), err)
+
+			problem := decodeProblem(t, rec, tt.status)
+			if problem.Code != tt.code || problem.Detail != err.Error() {
+				t.Errorf("Expected code %s with the error as detail, got %+v", tt.code, problem)
+			}
+		})
+	}
+	for _, code := range httpDriver.ProblemCodes() {
+		if !tested[code] {
+			t.Errorf("Catalog code %s has no test case", code)
+		}
+	}
+}
+
+func TestProblemDocuments(t *testing.T) {
+	// O produto não chega ao serviço: o corpo malformado é recusado na decodificação.
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	r.NotFound(httpDriver.NotFound)
+	r.MethodNotAllowed(httpDriver.MethodNotAllowed(r))
+	r.Post("/products", httpDriver.NewProductHandler(nil).CreateProductHandler)
+
+	serve := func(method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(middleware.RequestIDHeader, "req-123")
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("Unmapped Errors Do Not Leak Details", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		httpDriver.WriteErrorResponse(rec, httptest.NewRequest(http.MethodGet, "/", nil), errors.New("pq: password authentication failed"))
+
+		problem := decodeProblem(t, rec, http.StatusInternalServerError)
+		if problem.Code != "internal-error" || strings.Contains(rec.Body.String(), "password") {
+			t.Errorf("Expected an opaque internal-error, got %s", rec.Body)
+		}
+	})
+
+	t.Run("Malformed JSON", func(t *testing.T) {
+		rec := serve(http.MethodPost, "/products", `{"ID": "1",`)
+
+		if problem := decodeProblem(t, rec, http.StatusBadRequest); problem.Code != "invalid-request-body" {
+			t.Errorf("Expected invalid-request-body, got %+v", problem)
+		}
+	})
+
+	t.Run("Unknown Routes", func(t *testing.T) {
+		rec := serve(http.MethodGet, "/nope", "")
+
+		problem := decodeProblem(t, rec, http.StatusNotFound)
+		if problem.Code != "route-not-found" || problem.Instance != "req-123" {
+			t.Errorf("Expected route-not-found with the request ID as instance, got %+v", problem)
+		}
+	})
+
+	t.Run("Unsupported Methods", func(t *testing.T) {
+		rec := serve(http.MethodDelete, "/products", "")
+
+		if problem := decodeProblem(t, rec, http.StatusMethodNotAllowed); problem.Code != "method-not-allowed" || rec.Header().Get("Allow") != http.MethodPost {
+			t.Errorf("Expected method-not-allowed with Allow: POST, got %+v %v", problem, rec.Header())
+		}
+	})
+}
@@ -0,0 +1,481 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/application"
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+// APIVersion identifica a versão da representação pública dos produtos.
+type APIVersion int
+
+const (
+	// APIVersion1 mantém os nomes de campo originais ("ID", "CreatedAt"). Está depreciada.
+	APIVersion1 APIVersion = 1
+	// APIVersion2 usa campos em snake_case ("id", "created_at").
+	APIVersion2 APIVersion = 2
+)
+
+// productRepresentation converte produtos entre o modelo de domínio e os DTOs de uma versão da API.
+// Os DTOs fixam o contrato público: mudanças no modelo de domínio não alteram o JSON das respostas.
+type productRepresentation interface {
+	// decodeProduct lê o produto do corpo da requisição. id é o ID do caminho, vazio na criação.
+	decodeProduct(r *http.Request, id string) (*models.Product, error)
+	// translatePatch converte um documento de patch para os nomes de campo do modelo de domínio.
+	translatePatch(mediaType string, body []byte) ([]byte, error)
+	// field retorna o campo do modelo de domínio correspondente a um campo dos DTOs de produto.
+	field(name string) (models.ProductField, bool)
+	// embeddedName retorna o nome, na convenção da versão, de um recurso embutido com ?expand=.
+	embeddedName(expansion string) string
+	product(product *models.Product) any
+	products(products []*models.Product) any
+	revisions(revisions []*models.ProductRevision) any
+	stats(stats *models.ProductStats) any
+	importReport(report *models.ImportReport) any
+}
+
+func newProductRepresentation(version APIVersion) productRepresentation {
+	if version == APIVersion2 {
+		return productRepresentationV2{}
+	}
+	return productRepresentationV1{}
+}
+
+// --- API v1 ---
+
+// ProductV1 é a representação de produto da API v1.
+type ProductV1 struct {
+	ID        string    `json:"ID"`
+	Name      string    `json:"Name"`
+	Price     float64   `json:"Price"`
+	CreatedAt time.Time `json:"CreatedAt"`
+	UpdatedAt time.Time `json:"UpdatedAt"`
+}
+
+// ProductListV1 é a representação de uma lista de produtos da API v1.
+type ProductListV1 []*ProductV1
+
+// ProductRequestV1 é o corpo das requisições de criação e atualização de produtos da API v1.
+// CreatedAt e UpdatedAt, se enviados, são ignorados, para que o produto lido possa ser reenviado.
+type ProductRequestV1 struct {
+	ID        string     `json:"ID" required:"true"`
+	Name      string     `json:"Name" required:"true"`
+	Price     float64    `json:"Price" required:"true"`
+	CreatedAt *time.Time `json:"CreatedAt"`
+	UpdatedAt *time.Time `json:"UpdatedAt"`
+}
+
+// ProductRevisionV1 é a representação de uma revisão de produto da API v1.
+type ProductRevisionV1 struct {
+	ProductID string     `json:"ProductID"`
+	Revision  int        `json:"Revision"`
+	Product   *ProductV1 `json:"Product"`
+	Deleted   bool       `json:"Deleted"`
+	ValidFrom time.Time  `json:"ValidFrom"`
+	ValidTo   *time.Time `json:"ValidTo"`
+}
+
+// ProductRevisionListV1 é a representação do histórico de revisões de um produto da API v1.
+type ProductRevisionListV1 []ProductRevisionV1
+
+// PriceSummaryV1 é o resumo de preços das estatísticas da API v1.
+type PriceSummaryV1 struct {
+	Count       int      `json:"Count"`
+	MinPrice    *float64 `json:"MinPrice"`
+	MaxPrice    *float64 `json:"MaxPrice"`
+	AvgPrice    *float64 `json:"AvgPrice"`
+	MedianPrice *float64 `json:"MedianPrice"`
+}
+
+// ProductStatsGroupV1 é um grupo das estatísticas da API v1.
+type ProductStatsGroupV1 struct {
+	Period string `json:"Period"`
+	PriceSummaryV1
+}
+
+// ProductStatsV1 é a representação das estatísticas do catálogo da API v1.
+type ProductStatsV1 struct {
+	PriceSummaryV1
+	GroupBy string                `json:"GroupBy"`
+	Groups  []ProductStatsGroupV1 `json:"Groups" xml:"Groups>Group"`
+}
+
+// ImportRowErrorV1 é um erro de linha do relatório de importação da API v1.
+type ImportRowErrorV1 struct {
+	Row     int    `json:"Row"`
+	Field   string `json:"Field"`
+	Message string `json:"Message"`
+}
+
+// ImportReportV1 é a representação do relatório de importação da API v1.
+type ImportReportV1 struct {
+	Mode       string             `json:"Mode"`
+	DryRun     bool               `json:"DryRun"`
+	Applied    bool               `json:"Applied"`
+	TotalRows  int                `json:"TotalRows"`
+	ValidRows  int                `json:"ValidRows"`
+	Created    int                `json:"Created"`
+	Updated    int                `json:"Updated"`
+	ErrorCount int                `json:"ErrorCount"`
+	Errors     []ImportRowErrorV1 `json:"Errors" xml:"Errors>Error"`
+}
+
+type productRepresentationV1 struct{}
+
+func (productRepresentationV1) decodeProduct(r *http.Request, _ string) (*models.Product, error) {
+	var request ProductRequestV1
+	if err := decodeRequestBody(r, &request); err != nil {
+		return nil, err
+	}
+	return &models.Product{ID: request.ID, Name: request.Name, Price: request.Price}, nil
+}
+
+// translatePatch não altera o documento: os campos da v1 coincidem com os do modelo de domínio.
+func (productRepresentationV1) translatePatch(_ string, body []byte) ([]byte, error) {
+	return body, nil
+}
+
+// field reconhece os nomes do modelo de domínio, que são os mesmos dos DTOs da v1.
+func (productRepresentationV1) field(name string) (models.ProductField, bool) {
+	field := models.ProductField(name)
+	return field, slices.Contains(models.ProductFields, field)
+}
+
+func (productRepresentationV1) embeddedName(expansion string) string {
+	return strings.ToUpper(expansion[:1]) + expansion[1:]
+}
+
+func (productRepresentationV1) product(product *models.Product) any {
+	return toProductV1(product)
+}
+
+func (productRepresentationV1) products(products []*models.Product) any {
+	dtos := make(ProductListV1, len(products))
+	for i, product := range products {
+		dtos[i] = toProductV1(product)
+	}
+	return dtos
+}
+
+func (productRepresentationV1) revisions(revisions []*models.ProductRevision) any {
+	dtos := make(ProductRevisionListV1, len(revisions))
+	for i, revision := range revisions {
+		dtos[i] = ProductRevisionV1{
+			ProductID: revision.ProductID,
+			Revision:  revision.Revision,
+			Product:   toProductV1(revision.Product),
+			Deleted:   revision.Deleted,
+			ValidFrom: revision.ValidFrom,
+			ValidTo:   revision.ValidTo,
+		}
+	}
+	return dtos
+}
+
+func (productRepresentationV1) stats(stats *models.ProductStats) any {
+	dto := ProductStatsV1{PriceSummaryV1: toPriceSummaryV1(stats.PriceSummary), GroupBy: string(stats.GroupBy)}
+	// A v1 mantém o null original para estatísticas sem agrupamento.
+	if stats.Groups != nil {
+		dto.Groups = make([]ProductStatsGroupV1, len(stats.Groups))
+		for i, group := range stats.Groups {
+			dto.Groups[i] = ProductStatsGroupV1{Period: group.Period, PriceSummaryV1: toPriceSummaryV1(group.PriceSummary)}
+		}
+	}
+	return dto
+}
+
+func (productRepresentationV1) importReport(report *models.ImportReport) any {
+	dto := ImportReportV1{
+		Mode:       string(report.Mode),
+		DryRun:     report.DryRun,
+		Applied:    report.Applied,
+		TotalRows:  report.TotalRows,
+		ValidRows:  report.ValidRows,
+		Created:    report.Created,
+		Updated:    report.Updated,
+		ErrorCount: report.ErrorCount,
+	}
+	if report.Errors != nil {
+		dto.Errors = make([]ImportRowErrorV1, len(report.Errors))
+		for i, rowErr := range report.Errors {
+			dto.Errors[i] = ImportRowErrorV1{Row: rowErr.Row, Field: rowErr.Field, Message: rowErr.Message}
+		}
+	}
+	return dto
+}
+
+func toProductV1(product *models.Product) *ProductV1 {
+	if product == nil {
+		return nil
+	}
+	return &ProductV1{
+		ID:        product.ID,
+		Name:      product.Name,
+		Price:     product.Price,
+		CreatedAt: product.CreatedAt,
+		UpdatedAt: product.UpdatedAt,
+	}
+}
+
+func toPriceSummaryV1(summary models.PriceSummary) PriceSummaryV1 {
+	return PriceSummaryV1{
+		Count:       summary.Count,
+		MinPrice:    summary.MinPrice,
+		MaxPrice:    summary.MaxPrice,
+		AvgPrice:    summary.AvgPrice,
+		MedianPrice: summary.MedianPrice,
+	}
+}
+
+// --- API v2 ---
+
+// ProductV2 é a representação de produto da API v2.
+type ProductV2 struct {
+	ID        string    `json:"id" xml:"id"`
+	Name      string    `json:"name" xml:"name"`
+	Price     float64   `json:"price" xml:"price"`
+	CreatedAt time.Time `json:"created_at" xml:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" xml:"updated_at"`
+}
+
+// ProductListV2 é a representação de uma lista de produtos da API v2.
+type ProductListV2 []*ProductV2
+
+// ProductRequestV2 é o corpo das requisições de criação e atualização de produtos da API v2.
+// Na atualização, o id pode ser omitido; se enviado, deve coincidir com o do caminho.
+type ProductRequestV2 struct {
+	ID    string  `json:"id"`
+	Name  string  `json:"name" required:"true"`
+	Price float64 `json:"price" required:"true"`
+}
+
+// ProductRevisionV2 é a representação de uma revisão de produto da API v2.
+type ProductRevisionV2 struct {
+	ProductID string     `json:"product_id" xml:"product_id"`
+	Revision  int        `json:"revision" xml:"revision"`
+	Product   *ProductV2 `json:"product" xml:"product"`
+	Deleted   bool       `json:"deleted" xml:"deleted"`
+	ValidFrom time.Time  `json:"valid_from" xml:"valid_from"`
+	ValidTo   *time.Time `json:"valid_to" xml:"valid_to"`
+}
+
+// ProductRevisionListV2 é a representação do histórico de revisões de um produto da API v2.
+type ProductRevisionListV2 []ProductRevisionV2
+
+// PriceSummaryV2 é o resumo de preços das estatísticas da API v2.
+type PriceSummaryV2 struct {
+	Count       int      `json:"count" xml:"count"`
+	MinPrice    *float64 `json:"min_price" xml:"min_price"`
+	MaxPrice    *float64 `json:"max_price" xml:"max_price"`
+	AvgPrice    *float64 `json:"avg_price" xml:"avg_price"`
+	MedianPrice *float64 `json:"median_price" xml:"median_price"`
+}
+
+// ProductStatsGroupV2 é um grupo das estatísticas da API v2.
+type ProductStatsGroupV2 struct {
+	Period string `json:"period" xml:"period"`
+	PriceSummaryV2
+}
+
+// ProductStatsV2 é a representação das estatísticas do catálogo da API v2. Groups é sempre uma lista.
+type ProductStatsV2 struct {
+	PriceSummaryV2
+	GroupBy string                `json:"group_by" xml:"group_by"`
+	Groups  []ProductStatsGroupV2 `json:"groups" xml:"groups>group"`
+}
+
+// ImportRowErrorV2 é um erro de linha do relatório de importação da API v2.
+type ImportRowErrorV2 struct {
+	Row     int    `json:"row" xml:"row"`
+	Field   string `json:"field" xml:"field"`
+	Message string `json:"message" xml:"message"`
+}
+
+// ImportReportV2 é a representação do relatório de importação da API v2. Errors é sempre uma lista.
+type ImportReportV2 struct {
+	Mode       string             `json:"mode" xml:"mode"`
+	DryRun     bool               `json:"dry_run" xml:"dry_run"`
+	Applied    bool               `json:"applied" xml:"applied"`
+	TotalRows  int                `json:"total_rows" xml:"total_rows"`
+	ValidRows  int                `json:"valid_rows" xml:"valid_rows"`
+	Created    int                `json:"created" xml:"created"`
+	Updated    int                `json:"updated" xml:"updated"`
+	ErrorCount int                `json:"error_count" xml:"error_count"`
+	Errors     []ImportRowErrorV2 `json:"errors" xml:"errors>error"`
+}
+
+// productFieldsV2 associa os campos de produto da API v2 aos do modelo de domínio, para a tradução de patches
+// e para ?fields=.
+var productFieldsV2 = map[string]string{
+	"id":         "ID",
+	"name":       "Name",
+	"price":      "Price",
+	"created_at": "CreatedAt",
+	"updated_at": "UpdatedAt",
+}
+
+type productRepresentationV2 struct{}
+
+func (productRepresentationV2) decodeProduct(r *http.Request, id string) (*models.Product, error) {
+	var request ProductRequestV2
+	if err := decodeRequestBody(r, &request); err != nil {
+		return nil, err
+	}
+	if request.ID == "" {
+		request.ID = id
+	}
+	return &models.Product{ID: request.ID, Name: request.Name, Price: request.Price}, nil
+}
+
+// translatePatch renomeia os campos do patch para os do modelo de domínio: as chaves do JSON Merge Patch
+// e o primeiro segmento de path e from no JSON Patch. Campos desconhecidos resultam em ErrInvalidPatch.
+// Documentos malformados seguem inalterados, para que o erro seja apontado pela aplicação do patch.
+func (productRepresentationV2) translatePatch(mediaType string, body []byte) ([]byte, error) {
+	switch mediaType {
+	case application.MergePatchMediaType:
+		var document map[string]json.RawMessage
+		if err := json.Unmarshal(body, &document); err != nil {
+			return body, nil
+		}
+		translated := make(map[string]json.RawMessage, len(document))
+		for field, value := range document {
+			domainField, ok := productFieldsV2[field]
+			if !ok {
+				return nil, fmt.Errorf("%w: unknown field %q", models.ErrInvalidPatch, field)
+			}
+			translated[domainField] = value
+		}
+		return json.Marshal(translated)
+	case application.JSONPatchMediaType:
+		var operations []map[string]json.RawMessage
+		if err := json.Unmarshal(body, &operations); err != nil {
+			return body, nil
+		}
+		for i, operation := range operations {
+			for _, member := range []string{"path", "from"} {
+				var pointer string
+				if raw, ok := operation[member]; !ok || json.Unmarshal(raw, &pointer) != nil {
+					continue
+				}
+				translated, err := translatePointerV2(pointer)
+				if err != nil {
+					return nil, fmt.Errorf("%w: operation %d: %v", models.ErrInvalidPatch, i, err)
+				}
+				operation[member], _ = json.Marshal(translated)
+			}
+		}
+		return json.Marshal(operations)
+	}
+	return body, nil
+}
+
+// translatePointerV2 traduz o primeiro segmento de um JSON Pointer para o campo do modelo de domínio.
+func translatePointerV2(pointer string) (string, error) {
+	if pointer == "" {
+		return pointer, nil
+	}
+	field, rest, _ := strings.Cut(strings.TrimPrefix(pointer, "/"), "/")
+	domainField, ok := productFieldsV2[field]
+	if !ok {
+		return "", fmt.Errorf("unknown field %q", field)
+	}
+	if rest != "" {
+		return "/" + domainField + "/" + rest, nil
+	}
+	return "/" + domainField, nil
+}
+
+func (productRepresentationV2) field(name string) (models.ProductField, bool) {
+	field, ok := productFieldsV2[name]
+	return models.ProductField(field), ok
+}
+
+func (productRepresentationV2) embeddedName(expansion string) string {
+	return expansion
+}
+
+func (productRepresentationV2) product(product *models.Product) any {
+	return toProductV2(product)
+}
+
+func (productRepresentationV2) products(products []*models.Product) any {
+	dtos := make(ProductListV2, len(products))
+	for i, product := range products {
+		dtos[i] = toProductV2(product)
+	}
+	return dtos
+}
+
+func (productRepresentationV2) revisions(revisions []*models.ProductRevision) any {
+	dtos := make(ProductRevisionListV2, len(revisions))
+	for i, revision := range revisions {
+		dtos[i] = ProductRevisionV2{
+			ProductID: revision.ProductID,
+			Revision:  revision.Revision,
+			Product:   toProductV2(revision.Product),
+			Deleted:   revision.Deleted,
+			ValidFrom: revision.ValidFrom,
+			ValidTo:   revision.ValidTo,
+		}
+	}
+	return dtos
+}
+
+func (productRepresentationV2) stats(stats *models.ProductStats) any {
+	dto := ProductStatsV2{
+		PriceSummaryV2: toPriceSummaryV2(stats.PriceSummary),
+		GroupBy:        string(stats.GroupBy),
+		Groups:         make([]ProductStatsGroupV2, len(stats.Groups)),
+	}
+	for i, group := range stats.Groups {
+		dto.Groups[i] = ProductStatsGroupV2{Period: group.Period, PriceSummaryV2: toPriceSummaryV2(group.PriceSummary)}
+	}
+	return dto
+}
+
+func (productRepresentationV2) importReport(report *models.ImportReport) any {
+	dto := ImportReportV2{
+		Mode:       string(report.Mode),
+		DryRun:     report.DryRun,
+		Applied:    report.Applied,
+		TotalRows:  report.TotalRows,
+		ValidRows:  report.ValidRows,
+		Created:    report.Created,
+		Updated:    report.Updated,
+		ErrorCount: report.ErrorCount,
+		Errors:     make([]ImportRowErrorV2, len(report.Errors)),
+	}
+	for i, rowErr := range report.Errors {
+		dto.Errors[i] = ImportRowErrorV2{Row: rowErr.Row, Field: rowErr.Field, Message: rowErr.Message}
+	}
+	return dto
+}
+
+func toProductV2(product *models.Product) *ProductV2 {
+	if product == nil {
+		return nil
+	}
+	return &ProductV2{
+		ID:        product.ID,
+		Name:      product.Name,
+		Price:     product.Price,
+		CreatedAt: product.CreatedAt,
+		UpdatedAt: product.UpdatedAt,
+	}
+}
+
+func toPriceSummaryV2(summary models.PriceSummary) PriceSummaryV2 {
+	return PriceSummaryV2{
+		Count:       summary.Count,
+		MinPrice:    summary.MinPrice,
+		MaxPrice:    summary.MaxPrice,
+		AvgPrice:    summary.AvgPrice,
+		MedianPrice: summary.MedianPrice,
+	}
+}
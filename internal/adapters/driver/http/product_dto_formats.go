@@ -0,0 +1,189 @@
+package http
+
+import (
+	"encoding/xml"
+	"strconv"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+// Os DTOs de produtos também podem ser enviados em XML e, no caso das listas, em CSV. Em XML, os elementos
+// têm os mesmos nomes dos campos JSON de cada versão, e as listas ganham um elemento raiz no plural. As
+// colunas CSV são as mesmas em todas as versões, como na exportação.
+
+// csvProductColumns associa os campos do modelo de domínio às colunas de csvProductHeader, usadas também
+// nas listas restritas por ?fields=.
+var csvProductColumns = map[models.ProductField]string{
+	models.ProductFieldID:        "id",
+	models.ProductFieldName:      "name",
+	models.ProductFieldPrice:     "price",
+	models.ProductFieldCreatedAt: "created_at",
+	models.ProductFieldUpdatedAt: "updated_at",
+}
+
+// csvRevisionHeader são as colunas do histórico de revisões em CSV. name e price ficam vazios nas
+// revisões de remoção.
+var csvRevisionHeader = []string{"product_id", "revision", "deleted", "valid_from", "valid_to", "name", "price"}
+
+// productCSVRecord monta a linha CSV de um produto, com as colunas de csvProductHeader.
+func productCSVRecord(id, name string, price float64, createdAt, updatedAt time.Time) []string {
+	return []string{
+		id,
+		name,
+		strconv.FormatFloat(price, 'f', -1, 64),
+		createdAt.Format(time.RFC3339Nano),
+		updatedAt.Format(time.RFC3339Nano),
+	}
+}
+
+// revisionCSVRecord monta a linha CSV de uma revisão, com as colunas de csvRevisionHeader.
+func revisionCSVRecord(productID string, revision int, deleted bool, validFrom time.Time, validTo *time.Time, name *string, price *float64) []string {
+	record := []string{productID, strconv.Itoa(revision), strconv.FormatBool(deleted), validFrom.Format(time.RFC3339Nano), "", "", ""}
+	if validTo != nil {
+		record[4] = validTo.Format(time.RFC3339Nano)
+	}
+	if name != nil {
+		record[5] = *name
+	}
+	if price != nil {
+		record[6] = strconv.FormatFloat(*price, 'f', -1, 64)
+	}
+	return record
+}
+
+// --- API v1 ---
+
+func (p *ProductV1) xmlDocument() any {
+	return struct {
+		XMLName xml.Name `xml:"Product"`
+		*ProductV1
+	}{ProductV1: p}
+}
+
+func (l ProductListV1) xmlDocument() any {
+	return struct {
+		XMLName  xml.Name     `xml:"Products"`
+		Products []*ProductV1 `xml:"Product"`
+	}{Products: l}
+}
+
+func (l ProductListV1) csvHeader() []string {
+	return csvProductHeader
+}
+
+func (l ProductListV1) csvRecords() [][]string {
+	records := make([][]string, len(l))
+	for i, p := range l {
+		records[i] = productCSVRecord(p.ID, p.Name, p.Price, p.CreatedAt, p.UpdatedAt)
+	}
+	return records
+}
+
+func (l ProductRevisionListV1) xmlDocument() any {
+	return struct {
+		XMLName   xml.Name            `xml:"Revisions"`
+		Revisions []ProductRevisionV1 `xml:"Revision"`
+	}{Revisions: l}
+}
+
+func (l ProductRevisionListV1) csvHeader() []string {
+	return csvRevisionHeader
+}
+
+func (l ProductRevisionListV1) csvRecords() [][]string {
+	records := make([][]string, len(l))
+	for i, revision := range l {
+		var (
+			name  *string
+			price *float64
+		)
+		if revision.Product != nil {
+			name, price = &revision.Product.Name, &revision.Product.Price
+		}
+		records[i] = revisionCSVRecord(revision.ProductID, revision.Revision, revision.Deleted, revision.ValidFrom, revision.ValidTo, name, price)
+	}
+	return records
+}
+
+func (s ProductStatsV1) xmlDocument() any {
+	return struct {
+		XMLName xml.Name `xml:"ProductStats"`
+		ProductStatsV1
+	}{ProductStatsV1: s}
+}
+
+func (r ImportReportV1) xmlDocument() any {
+	return struct {
+		XMLName xml.Name `xml:"ImportReport"`
+		ImportReportV1
+	}{ImportReportV1: r}
+}
+
+// --- API v2 ---
+
+func (p *ProductV2) xmlDocument() any {
+	return struct {
+		XMLName xml.Name `xml:"product"`
+		*ProductV2
+	}{ProductV2: p}
+}
+
+func (l ProductListV2) xmlDocument() any {
+	return struct {
+		XMLName  xml.Name     `xml:"products"`
+		Products []*ProductV2 `xml:"product"`
+	}{Products: l}
+}
+
+func (l ProductListV2) csvHeader() []string {
+	return csvProductHeader
+}
+
+func (l ProductListV2) csvRecords() [][]string {
+	records := make([][]string, len(l))
+	for i, p := range l {
+		records[i] = productCSVRecord(p.ID, p.Name, p.Price, p.CreatedAt, p.UpdatedAt)
+	}
+	return records
+}
+
+func (l ProductRevisionListV2) xmlDocument() any {
+	return struct {
+		XMLName   xml.Name            `xml:"revisions"`
+		Revisions []ProductRevisionV2 `xml:"revision"`
+	}{Revisions: l}
+}
+
+func (l ProductRevisionListV2) csvHeader() []string {
+	return csvRevisionHeader
+}
+
+func (l ProductRevisionListV2) csvRecords() [][]string {
+	records := make([][]string, len(l))
+	for i, revision := range l {
+		var (
+			name  *string
+			price *float64
+		)
+		if revision.Product != nil {
+			name, price = &revision.Product.Name, &revision.Product.Price
+		}
+		records[i] = revisionCSVRecord(revision.ProductID, revision.Revision, revision.Deleted, revision.ValidFrom, revision.ValidTo, name, price)
+	}
+	return records
+}
+
+func (s ProductStatsV2) xmlDocument() any {
+	return struct {
+		XMLName xml.Name `xml:"product_stats"`
+		ProductStatsV2
+	}{ProductStatsV2: s}
+}
+
+func (r ImportReportV2) xmlDocument() any {
+	return struct {
+		XMLName xml.Name `xml:"import_report"`
+		ImportReportV2
+	}{ImportReportV2: r}
+}
@@ -0,0 +1,68 @@
+package http_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	httpDriver "github.com/danielrios/product-service-go/internal/adapters/driver/http"
+)
+
+func TestAPIVersions(t *testing.T) {
+	sunset := time.Date(2027, time.April, 1, 0, 0, 0, 0, time.UTC)
+	router := newTestRouter(t, httpDriver.WithV1Sunset(sunset))
+
+	rec := serve(router, http.MethodPost, "/v2/products", "application/json", `{"id": "1", "name": "Product 1", "price": 10}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d %s", rec.Code, rec.Body)
+	}
+
+	t.Run("V2 Uses Snake Case Fields", func(t *testing.T) {
+		rec := serve(router, http.MethodGet, "/v2/products/1", "", "")
+
+		body := rec.Body.String()
+		if rec.Code != http.StatusOK || !strings.Contains(body, `"created_at"`) || strings.Contains(body, `"CreatedAt"`) {
+			t.Errorf("Expected a v2 product, got %d %s", rec.Code, body)
+		}
+		if rec.Header().Get("Deprecation") != "" {
+			t.Errorf("Expected no Deprecation header on v2, got %q", rec.Header().Get("Deprecation"))
+		}
+	})
+
+	t.Run("V1 Keeps The Legacy Shape And Is Deprecated", func(t *testing.T) {
+		for _, target := range []string{"/v1/products/1", "/products/1"} {
+			rec := serve(router, http.MethodGet, target, "", "")
+
+			if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"CreatedAt"`) {
+				t.Errorf("Expected a v1 product at %s, got %d %s", target, rec.Code, rec.Body)
+			}
+			if !strings.HasPrefix(rec.Header().Get("Deprecation"), "@") {
+				t.Errorf("Expected a Deprecation header at %s, got %q", target, rec.Header().Get("Deprecation"))
+			}
+			if rec.Header().Get("Sunset") != sunset.Format(http.TimeFormat) {
+				t.Errorf("Expected Sunset %s at %s, got %q", sunset.Format(http.TimeFormat), target, rec.Header().Get("Sunset"))
+			}
+			if rec.Header().Get("Link") != `</v2/products/1>; rel="successor-version"` {
+				t.Errorf("Expected a successor-version link at %s, got %q", target, rec.Header().Get("Link"))
+			}
+		}
+	})
+
+	t.Run("V2 Patches Use V2 Field Names", func(t *testing.T) {
+		rec := serve(router, http.MethodPatch, "/v2/products/1", "application/merge-patch+json", `{"price": 12.5}`)
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"price":12.5`) {
+			t.Errorf("Expected the merge patch to update the price, got %d %s", rec.Code, rec.Body)
+		}
+
+		rec = serve(router, http.MethodPatch, "/v2/products/1", "application/json-patch+json", `[{"op": "replace", "path": "/name", "value": "Renamed"}]`)
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"name":"Renamed"`) {
+			t.Errorf("Expected the JSON patch to update the name, got %d %s", rec.Code, rec.Body)
+		}
+
+		rec = serve(router, http.MethodPatch, "/v2/products/1", "application/merge-patch+json", `{"Price": 1}`)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for a v1 field name, got %d %s", rec.Code, rec.Body)
+		}
+	})
+}
@@ -0,0 +1,186 @@
+package http
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+// exportBufferSize é o tamanho do buffer de escrita da exportação; a memória usada não depende do tamanho do catálogo.
+const exportBufferSize = 32 * 1024
+
+// productEncoder serializa produtos, um a um, em um formato de exportação.
+type productEncoder interface {
+	Encode(product *models.Product) error
+	// Close escreve o que o formato exige ao final, mesmo que nenhum produto tenha sido exportado.
+	Close() error
+}
+
+// exportFormat descreve um formato aceito pelo parâmetro format da exportação.
+type exportFormat struct {
+	contentType string
+	extension   string
+	newEncoder  func(w io.Writer, representation productRepresentation) productEncoder
+}
+
+var exportFormats = map[string]exportFormat{
+	"csv":    {contentType: "text/csv; charset=utf-8", extension: "csv", newEncoder: newCSVProductEncoder},
+	"ndjson": {contentType: "application/x-ndjson", extension: "ndjson", newEncoder: newNDJSONProductEncoder},
+	"json":   {contentType: "application/json", extension: "json", newEncoder: newJSONArrayProductEncoder},
+}
+
+// ExportProductsHandler lida com a requisição GET /products/export?format=csv|ndjson|json (padrão json),
+// aceitando os mesmos filtros da listagem. Os produtos são escritos à medida que são lidos do repositório.
+// Se a leitura falhar depois que a resposta começou a ser enviada, a conexão é abortada para que o cliente
+// não confunda um arquivo truncado com uma exportação completa.
+func (h *ProductHandler) ExportProductsHandler(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseProductFilter(r)
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+	formatName := r.URL.Query().Get("format")
+	if formatName == "" {
+		formatName = "json"
+	}
+	format, ok := exportFormats[formatName]
+	if !ok {
+		writeErrorResponse(w, r, models.ErrInvalidExportFormat)
+		return
+	}
+
+	filename := fmt.Sprintf("products-%s.%s", time.Now().UTC().Format("20060102T150405Z"), format.extension)
+	w.Header().Set("Content-Type", format.contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	sent := &sentTracker{w: w}
+	buffered := bufio.NewWriterSize(sent, exportBufferSize)
+	encoder := format.newEncoder(buffered, h.representation)
+
+	err = h.service.ExportProducts(r.Context(), filter, encoder.Encode)
+	if err == nil {
+		err = encoder.Close()
+	}
+	if err == nil {
+		err = buffered.Flush()
+	}
+	if err == nil {
+		return
+	}
+
+	if !sent.started {
+		w.Header().Del("Content-Disposition")
+		writeErrorResponse(w, r, err)
+		return
+	}
+	log.Printf("Exportação de produtos interrompida: %v", err)
+	panic(http.ErrAbortHandler)
+}
+
+// sentTracker registra se algum byte já foi enviado ao cliente, o que impede trocar a resposta por um erro.
+type sentTracker struct {
+	w       io.Writer
+	started bool
+}
+
+func (t *sentTracker) Write(data []byte) (int, error) {
+	t.started = true
+	return t.w.Write(data)
+}
+
+type ndjsonProductEncoder struct {
+	encoder        *json.Encoder
+	representation productRepresentation
+}
+
+func newNDJSONProductEncoder(w io.Writer, representation productRepresentation) productEncoder {
+	return &ndjsonProductEncoder{encoder: json.NewEncoder(w), representation: representation}
+}
+
+func (e *ndjsonProductEncoder) Encode(product *models.Product) error {
+	return e.encoder.Encode(e.representation.product(product))
+}
+
+func (e *ndjsonProductEncoder) Close() error {
+	return nil
+}
+
+// jsonArrayProductEncoder escreve um único array JSON, com o mesmo formato de GET /products.
+type jsonArrayProductEncoder struct {
+	w              io.Writer
+	representation productRepresentation
+	count          int
+}
+
+func newJSONArrayProductEncoder(w io.Writer, representation productRepresentation) productEncoder {
+	return &jsonArrayProductEncoder{w: w, representation: representation}
+}
+
+func (e *jsonArrayProductEncoder) Encode(product *models.Product) error {
+	data, err := json.Marshal(e.representation.product(product))
+	if err != nil {
+		return err
+	}
+	separator := ","
+	if e.count == 0 {
+		separator = "["
+	}
+	e.count++
+	if _, err := io.WriteString(e.w, separator); err != nil {
+		return err
+	}
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *jsonArrayProductEncoder) Close() error {
+	closing := "]\n"
+	if e.count == 0 {
+		closing = "[]\n"
+	}
+	_, err := io.WriteString(e.w, closing)
+	return err
+}
+
+// csvProductEncoder escreve um cabeçalho seguido de uma linha por produto, com datas em RFC 3339.
+// As colunas são as mesmas em todas as versões da API.
+type csvProductEncoder struct {
+	writer      *csv.Writer
+	wroteHeader bool
+}
+
+var csvProductHeader = []string{"id", "name", "price", "created_at", "updated_at"}
+
+func newCSVProductEncoder(w io.Writer, _ productRepresentation) productEncoder {
+	return &csvProductEncoder{writer: csv.NewWriter(w)}
+}
+
+func (e *csvProductEncoder) writeHeader() error {
+	if e.wroteHeader {
+		return nil
+	}
+	e.wroteHeader = true
+	return e.writer.Write(csvProductHeader)
+}
+
+func (e *csvProductEncoder) Encode(product *models.Product) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	return e.writer.Write(productCSVRecord(product.ID, product.Name, product.Price, product.CreatedAt, product.UpdatedAt))
+}
+
+func (e *csvProductEncoder) Close() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	e.writer.Flush()
+	return e.writer.Error()
+}
@@ -1,10 +1,11 @@
 package http
 
 import (
-	"encoding/json"
-	"errors"
-	"log"
+	"io"
+	"mime"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/danielrios/product-service-go/internal/application"
 	"github.com/danielrios/product-service-go/internal/core/models"
@@ -13,112 +14,265 @@ import (
 
 // ProductHandler define a estrutura do nosso Adaptador de Entrada HTTP.
 type ProductHandler struct {
-	service *application.ProductService
+	service        *application.ProductService
+	cacheControl   string
+	representation productRepresentation
 }
 
-// NewProductHandler cria e retorna uma nova instância de ProductHandler.
-func NewProductHandler(service *application.ProductService) *ProductHandler {
-	return &ProductHandler{
-		service: service,
+// ProductHandlerOption configura parâmetros opcionais do ProductHandler.
+type ProductHandlerOption func(*ProductHandler)
+
+// WithCacheControl define o cabeçalho Cache-Control das leituras de produtos. Um valor vazio omite o cabeçalho.
+func WithCacheControl(cacheControl string) ProductHandlerOption {
+	return func(h *ProductHandler) {
+		h.cacheControl = cacheControl
 	}
 }
 
-// writeJSONResponse é um helper para enviar respostas JSON padronizadas.
-func writeJSONResponse(w http.ResponseWriter, statusCode int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	if data != nil {
-		if err := json.NewEncoder(w).Encode(data); err != nil {
-			log.Printf("Erro ao codificar resposta JSON: %v", err)
-		}
+// WithAPIVersion define a versão da representação JSON dos produtos (padrão APIVersion1).
+func WithAPIVersion(version APIVersion) ProductHandlerOption {
+	return func(h *ProductHandler) {
+		h.representation = newProductRepresentation(version)
 	}
 }
 
-// writeErrorResponse é um helper para enviar respostas de erro padronizadas.
-func writeErrorResponse(w http.ResponseWriter, err error) {
-	statusCode := http.StatusInternalServerError
-	message := "internal server error"
-
-	if errors.Is(err, models.ErrProductNotFound) {
-		statusCode = http.StatusNotFound
-		message = err.Error()
-	} else if errors.Is(err, models.ErrProductAlreadyExists) {
-		statusCode = http.StatusConflict
-		message = err.Error()
-	} else if errors.Is(err, models.ErrInvalidProductID) {
-		statusCode = http.StatusBadRequest
-		message = err.Error()
-	} else {
-		log.Printf("Erro interno não mapeado no handler: %v", err)
+// NewProductHandler cria e retorna uma nova instância de ProductHandler.
+func NewProductHandler(service *application.ProductService, opts ...ProductHandlerOption) *ProductHandler {
+	h := &ProductHandler{
+		service:        service,
+		cacheControl:   DefaultCacheControl,
+		representation: newProductRepresentation(APIVersion1),
 	}
-
-	writeJSONResponse(w, statusCode, map[string]string{"error": message})
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // CreateProductHandler lida com a requisição POST /products.
 func (h *ProductHandler) CreateProductHandler(w http.ResponseWriter, r *http.Request) {
-	var product models.Product
-	if err := json.NewDecoder(r.Body).Decode(&product); err != nil {
-		writeErrorResponse(w, errors.New("invalid request body"))
+	product, err := h.representation.decodeProduct(r, "")
+	if err != nil {
+		writeErrorResponse(w, r, err)
 		return
 	}
 
-	createdProduct, err := h.service.CreateProduct(&product)
+	createdProduct, err := h.service.CreateProduct(r.Context(), product)
 	if err != nil {
-		writeErrorResponse(w, err)
+		writeErrorResponse(w, r, err)
 		return
 	}
 
-	writeJSONResponse(w, http.StatusCreated, createdProduct)
+	data, err := h.writtenProductView(r.Context(), createdProduct)
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+	writeResponse(w, r, http.StatusCreated, data)
 }
 
 // GetProductByIDHandler lida com a requisição GET /products/{id}.
+// Com o parâmetro as_of (RFC 3339), retorna o produto como estava naquele instante.
+// A resposta traz ETag e Last-Modified, e requisições condicionais recebem 304 quando o produto não mudou.
 func (h *ProductHandler) GetProductByIDHandler(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
-	product, err := h.service.GetProductByID(id)
+	viewOpts, err := h.parseProductViewOptions(r)
 	if err != nil {
-		writeErrorResponse(w, err)
+		writeErrorResponse(w, r, err)
 		return
 	}
 
-	writeJSONResponse(w, http.StatusOK, product)
+	var product *models.Product
+	if rawAsOf := r.URL.Query().Get("as_of"); rawAsOf != "" {
+		asOf, parseErr := time.Parse(time.RFC3339, rawAsOf)
+		if parseErr != nil {
+			writeErrorResponse(w, r, models.ErrInvalidAsOf)
+			return
+		}
+		product, err = h.service.GetProductAsOf(r.Context(), id, asOf)
+	} else {
+		product, err = h.service.GetProductByID(r.Context(), id)
+	}
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+
+	data, err := h.productView(r.Context(), product, viewOpts)
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+	writeCacheableResponse(w, r, data, product.UpdatedAt, h.cacheControl)
 }
 
-// GetAllProductsHandler lida com a requisição GET /products (listagem)
+// GetProductRevisionsHandler lida com a requisição GET /products/{id}/revisions.
+func (h *ProductHandler) GetProductRevisionsHandler(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	revisions, err := h.service.ListProductRevisions(r.Context(), id)
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, h.representation.revisions(revisions))
+}
+
+// GetAllProductsHandler lida com a requisição GET /products (listagem), aceitando os filtros de parseProductFilter.
+// A listagem é validada apenas pelo ETag: exclusões não deixam data de alteração nos produtos restantes,
+// então um Last-Modified derivado deles permitiria respostas 304 desatualizadas após uma exclusão.
 func (h *ProductHandler) GetAllProductsHandler(w http.ResponseWriter, r *http.Request) {
-	products, err := h.service.GetAllProducts()
+	filter, err := parseProductFilter(r)
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+
+	viewOpts, err := h.parseProductViewOptions(r)
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+
+	products, err := h.service.GetAllProducts(r.Context(), filter, viewOpts.projection)
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+
+	data, err := h.productListView(r.Context(), products, viewOpts)
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+	writeCacheableResponse(w, r, data, time.Time{}, h.cacheControl)
+}
+
+// GetProductStatsHandler lida com a requisição GET /products/stats, aceitando os filtros da listagem
+// e o parâmetro group_by (day ou month) para agrupar pela data de criação.
+func (h *ProductHandler) GetProductStatsHandler(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseProductFilter(r)
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+
+	groupBy := models.StatsGrouping(r.URL.Query().Get("group_by"))
+	stats, err := h.service.GetProductStats(r.Context(), filter, groupBy)
 	if err != nil {
-		writeErrorResponse(w, err)
+		writeErrorResponse(w, r, err)
 		return
 	}
 
-	writeJSONResponse(w, http.StatusOK, products)
+	writeResponse(w, r, http.StatusOK, h.representation.stats(stats))
+}
+
+// parseProductFilter lê os filtros de produtos da query string: name (trecho do nome), min_price,
+// max_price, created_from e created_to (RFC 3339).
+func parseProductFilter(r *http.Request) (models.ProductFilter, error) {
+	query := r.URL.Query()
+	filter := models.ProductFilter{
+		NameContains: query.Get("name"),
+	}
+
+	for param, target := range map[string]**float64{"min_price": &filter.MinPrice, "max_price": &filter.MaxPrice} {
+		if raw := query.Get(param); raw != "" {
+			price, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return filter, models.ErrInvalidProductQuery
+			}
+			*target = &price
+		}
+	}
+
+	var err error
+	if raw := query.Get("created_from"); raw != "" {
+		if filter.CreatedFrom, err = time.Parse(time.RFC3339, raw); err != nil {
+			return filter, models.ErrInvalidProductQuery
+		}
+	}
+	if raw := query.Get("created_to"); raw != "" {
+		if filter.CreatedTo, err = time.Parse(time.RFC3339, raw); err != nil {
+			return filter, models.ErrInvalidProductQuery
+		}
+	}
+	return filter, nil
 }
 
 // UpdateProductHandler lida com a requisição PUT /products/{id}
 func (h *ProductHandler) UpdateProductHandler(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
-	var product models.Product
-	if err := json.NewDecoder(r.Body).Decode(&product); err != nil {
-		writeErrorResponse(w, errors.New("invalid request body"))
+	product, err := h.representation.decodeProduct(r, id)
+	if err != nil {
+		writeErrorResponse(w, r, err)
 		return
 	}
 
-	updatedProduct, err := h.service.UpdateProduct(id, &product)
+	updatedProduct, err := h.service.UpdateProduct(r.Context(), id, product)
 	if err != nil {
-		writeErrorResponse(w, err)
+		writeErrorResponse(w, r, err)
 		return
 	}
 
-	writeJSONResponse(w, http.StatusOK, updatedProduct)
+	data, err := h.writtenProductView(r.Context(), updatedProduct)
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, data)
+}
+
+// PatchProductHandler lida com a requisição PATCH /products/{id}, aceitando JSON Merge Patch (RFC 7396)
+// ou JSON Patch (RFC 6902) conforme o Content-Type.
+func (h *ProductHandler) PatchProductHandler(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	w.Header().Set("Accept-Patch", application.MergePatchMediaType+", "+application.JSONPatchMediaType)
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeErrorResponse(w, r, readBodyError(err))
+		return
+	}
+
+	var patch application.ProductPatch
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if body, err = h.representation.translatePatch(mediaType, body); err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+	switch mediaType {
+	case application.MergePatchMediaType:
+		patch, err = application.NewMergePatch(body)
+	case application.JSONPatchMediaType:
+		patch, err = application.ParseJSONPatch(body)
+	default:
+		err = models.ErrUnsupportedPatchType
+	}
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+
+	patchedProduct, err := h.service.PatchProduct(r.Context(), id, patch)
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+
+	data, err := h.writtenProductView(r.Context(), patchedProduct)
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, data)
 }
 
 // DeleteProductHandler lida com a requisição DELETE /products/{id}
 func (h *ProductHandler) DeleteProductHandler(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
-	err := h.service.DeleteProduct(id)
+	err := h.service.DeleteProduct(r.Context(), id)
 	if err != nil {
-		writeErrorResponse(w, err)
+		writeErrorResponse(w, r, err)
 		return
 	}
 
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/danielrios/product-service-go/internal/application"
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+// ImportProductsHandler lida com a requisição POST /products/import, cujo corpo é um arquivo CSV.
+// Parâmetros: mode (create ou upsert, padrão create), dry_run=true para apenas validar, delimiter
+// (um caractere, "tab" ou "semicolon"), decimal=comma para preços como 1.234,56 e id_column,
+// name_column e price_column para mapear colunas com outros nomes. Responde 200 com o relatório da
+// importação, ou 422 com o mesmo relatório quando alguma linha é inválida e nada foi gravado.
+// Com "Prefer: respond-async", a importação é executada como job e a resposta é 202 Accepted.
+func (h *ProductHandler) ImportProductsHandler(w http.ResponseWriter, r *http.Request) {
+	opts, err := parseImportOptions(r)
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+
+	if prefersAsync(r) {
+		job, err := h.service.SubmitImportProducts(r.Context(), r.Body, opts)
+		if err != nil {
+			writeErrorResponse(w, r, err)
+			return
+		}
+		writeJobAccepted(w, r, job)
+		return
+	}
+
+	report, err := h.service.ImportProducts(r.Context(), r.Body, opts)
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+
+	status := http.StatusOK
+	if report.ErrorCount > 0 && !report.DryRun {
+		status = http.StatusUnprocessableEntity
+	}
+	writeResponse(w, r, status, h.representation.importReport(report))
+}
+
+// parseImportOptions lê as opções da importação da query string.
+func parseImportOptions(r *http.Request) (application.ProductImportOptions, error) {
+	query := r.URL.Query()
+	opts := application.DefaultProductImportOptions()
+
+	if mode := query.Get("mode"); mode != "" {
+		opts.Mode = models.ImportMode(mode)
+	}
+	if dryRun := query.Get("dry_run"); dryRun != "" {
+		value, err := strconv.ParseBool(dryRun)
+		if err != nil {
+			return opts, models.ErrInvalidImportOptions
+		}
+		opts.DryRun = value
+	}
+
+	switch delimiter := query.Get("delimiter"); {
+	case delimiter == "":
+	case delimiter == "tab":
+		opts.Delimiter = '\t'
+	case delimiter == "semicolon":
+		// O net/url descarta pares com ";" sem codificação; o nome evita exigir %3B do cliente.
+		opts.Delimiter = ';'
+	case utf8.RuneCountInString(delimiter) == 1:
+		opts.Delimiter, _ = utf8.DecodeRuneInString(delimiter)
+	default:
+		return opts, models.ErrInvalidImportOptions
+	}
+
+	switch query.Get("decimal") {
+	case "", "dot":
+	case "comma":
+		opts.DecimalComma = true
+	default:
+		return opts, models.ErrInvalidImportOptions
+	}
+
+	if column := query.Get("id_column"); column != "" {
+		opts.Columns.ID = column
+	}
+	if column := query.Get("name_column"); column != "" {
+		opts.Columns.Name = column
+	}
+	if column := query.Get("price_column"); column != "" {
+		opts.Columns.Price = column
+	}
+	return opts, nil
+}
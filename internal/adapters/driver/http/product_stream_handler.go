@@ -0,0 +1,134 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/application"
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+// sseRetryMillis é o intervalo de reconexão sugerido aos clientes de Server-Sent Events.
+const sseRetryMillis = 3000
+
+// ProductStreamHandler é o Adaptador de Entrada HTTP que transmite as alterações de produtos via Server-Sent Events.
+type ProductStreamHandler struct {
+	hub       *application.ChangeHub
+	heartbeat time.Duration
+}
+
+// NewProductStreamHandler cria e retorna uma nova instância de ProductStreamHandler.
+// Um comentário SSE é enviado a cada heartbeat para manter a conexão aberta através de proxies.
+func NewProductStreamHandler(hub *application.ChangeHub, heartbeat time.Duration) *ProductStreamHandler {
+	return &ProductStreamHandler{
+		hub:       hub,
+		heartbeat: heartbeat,
+	}
+}
+
+// StreamHandler lida com a requisição GET /products/stream?ids=1,2&types=product.updated.
+// A retomada usa o cabeçalho Last-Event-ID (ou o parâmetro last_event_id). Se o histórico não cobrir o
+// ponto de retomada, um evento "reset" é enviado para que o cliente recarregue a listagem completa.
+func (h *ProductStreamHandler) StreamHandler(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseChangeFilter(r)
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+
+	lastID := r.Header.Get("Last-Event-ID")
+	if lastID == "" {
+		lastID = r.URL.Query().Get("last_event_id")
+	}
+
+	sub, missed, resumed, err := h.hub.Subscribe(lastID, filter)
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+	defer h.hub.Unsubscribe(sub)
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // Desativa o buffering em proxies como o nginx.
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", sseRetryMillis)
+	if !resumed {
+		fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+	}
+	for _, notification := range missed {
+		if err := writeSSEEvent(w, notification); err != nil {
+			return
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case notification, ok := <-sub.C:
+			if !ok {
+				return // Desconectado pelo hub; o cliente reconecta usando o último ID recebido.
+			}
+			if err := writeSSEEvent(w, notification); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// writeSSEEvent escreve uma notificação no formato de evento SSE.
+func writeSSEEvent(w io.Writer, notification application.ChangeNotification) error {
+	data, err := json.Marshal(notification.Event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", notification.ID, notification.Event.Type, data)
+	return err
+}
+
+// parseChangeFilter lê os filtros opcionais ids e types, ambos separados por vírgula.
+func parseChangeFilter(r *http.Request) (application.ChangeFilter, error) {
+	var filter application.ChangeFilter
+	query := r.URL.Query()
+	filter.ProductIDs = splitCommaList(query.Get("ids"))
+	for _, raw := range splitCommaList(query.Get("types")) {
+		eventType := models.EventType(raw)
+		if !eventType.IsValid() {
+			return filter, models.ErrInvalidEventType
+		}
+		filter.Types = append(filter.Types, eventType)
+	}
+	return filter, nil
+}
+
+// splitCommaList divide uma lista separada por vírgulas, descartando itens vazios.
+func splitCommaList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,376 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Nas leituras de produtos, ?fields= restringe cada produto aos campos pedidos, com os nomes da versão da
+// API, e ?expand= embute recursos relacionados. Sem esses parâmetros, as respostas usam os DTOs completos,
+// exceto quando o principal não pode ler todos os campos: os demais são omitidos, como em ?fields=.
+
+// productExpansion carrega um recurso relacionado ao produto, já na representação da versão da API.
+type productExpansion func(ctx context.Context, h *ProductHandler, product *models.Product) (any, error)
+
+// productExpansions são os recursos que podem ser embutidos com ?expand=. Cada um aparece no produto com
+// o nome da expansão, na convenção de nomes da versão da API. Novos relacionamentos do modelo entram aqui.
+var productExpansions = map[string]productExpansion{
+	"revisions": func(ctx context.Context, h *ProductHandler, product *models.Product) (any, error) {
+		revisions, err := h.service.ListProductRevisions(ctx, product.ID)
+		if err != nil {
+			return nil, err
+		}
+		return h.representation.revisions(revisions), nil
+	},
+}
+
+// productViewOptions são as opções de ?fields= e ?expand= de uma leitura de produtos.
+type productViewOptions struct {
+	fields     []string // Nomes dos campos nos DTOs da versão da API; vazio inclui todos.
+	projection models.ProductProjection
+	expand     []string
+}
+
+// isDefault informa se a resposta usa os DTOs completos, sem projeção nem expansões.
+func (o productViewOptions) isDefault() bool {
+	return len(o.fields) == 0 && len(o.expand) == 0
+}
+
+// parseProductViewOptions lê ?fields= e ?expand=, que aceitam listas separadas por vírgula ou parâmetros
+// repetidos. Os campos são os dos DTOs da versão da API, sem diferenciar maiúsculas de minúsculas. Pedir
+// um campo que o principal não pode ler resulta em um ForbiddenFieldsError.
+func (h *ProductHandler) parseProductViewOptions(r *http.Request) (productViewOptions, error) {
+	query := r.URL.Query()
+	var opts productViewOptions
+
+	known := dtoFieldNames(h.representation.product(&models.Product{}))
+	var errs []ProblemError
+	for _, name := range splitQueryList(query["fields"]) {
+		index := slices.IndexFunc(known, func(field string) bool { return strings.EqualFold(field, name) })
+		if index < 0 {
+			errs = append(errs, ProblemError{Location: "query.fields", Message: fmt.Sprintf("%q is not a product field; use %s", name, strings.Join(known, ", "))})
+			continue
+		}
+		if !slices.Contains(opts.fields, known[index]) {
+			field, _ := h.representation.field(known[index])
+			opts.fields = append(opts.fields, known[index])
+			opts.projection = append(opts.projection, field)
+		}
+	}
+	if len(errs) > 0 {
+		return opts, &validationError{kind: models.ErrInvalidProductFields, errors: errs}
+	}
+	if readable := h.service.ReadableProductFields(r.Context()); readable != nil {
+		if err := models.NewForbiddenFieldsError(models.FieldAccessRead, opts.projection, readable); err != nil {
+			return opts, err
+		}
+		if len(opts.fields) == 0 {
+			opts.fields, opts.projection = h.readableFields(readable)
+		}
+	}
+
+	for _, name := range splitQueryList(query["expand"]) {
+		if _, ok := productExpansions[name]; !ok {
+			errs = append(errs, ProblemError{Location: "query.expand", Message: fmt.Sprintf("%q is not an expandable resource; use %s", name, strings.Join(expansionNames(), ", "))})
+			continue
+		}
+		if !slices.Contains(opts.expand, name) {
+			opts.expand = append(opts.expand, name)
+		}
+	}
+	if len(errs) > 0 {
+		return opts, &validationError{kind: models.ErrInvalidProductExpansion, errors: errs}
+	}
+	return opts, nil
+}
+
+// readableFields retorna os campos dos DTOs, e a projeção correspondente, que o principal pode ler.
+func (h *ProductHandler) readableFields(readable models.ProductProjection) ([]string, models.ProductProjection) {
+	var (
+		fields     []string
+		projection models.ProductProjection
+	)
+	for _, name := range dtoFieldNames(h.representation.product(&models.Product{})) {
+		if field, _ := h.representation.field(name); readable.Includes(field) {
+			fields = append(fields, name)
+			projection = append(projection, field)
+		}
+	}
+	return fields, projection
+}
+
+// writtenProductView retorna a representação do produto criado ou alterado: o DTO completo ou, quando o
+// principal não pode ler todos os campos, um productView apenas com os que ele pode.
+func (h *ProductHandler) writtenProductView(ctx context.Context, product *models.Product) (any, error) {
+	readable := h.service.ReadableProductFields(ctx)
+	if readable == nil {
+		return h.representation.product(product), nil
+	}
+	var opts productViewOptions
+	opts.fields, opts.projection = h.readableFields(readable)
+	return h.productView(ctx, product, opts)
+}
+
+func splitQueryList(values []string) []string {
+	var items []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+func expansionNames() []string {
+	names := make([]string, 0, len(productExpansions))
+	for name := range productExpansions {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// productView retorna a representação do produto conforme as opções: o DTO completo ou um productView.
+func (h *ProductHandler) productView(ctx context.Context, product *models.Product, opts productViewOptions) (any, error) {
+	dto := h.representation.product(product)
+	if opts.isDefault() {
+		return dto, nil
+	}
+
+	view := &productView{root: xmlRootName(dto)}
+	for _, field := range dtoFields(dto) {
+		if len(opts.fields) == 0 || slices.Contains(opts.fields, field.name) {
+			view.entries = append(view.entries, productViewEntry{name: field.name, value: field.value})
+		}
+	}
+	for _, name := range opts.expand {
+		value, err := productExpansions[name](ctx, h, product)
+		if err != nil {
+			return nil, err
+		}
+		view.entries = append(view.entries, productViewEntry{name: h.representation.embeddedName(name), value: value})
+	}
+	return view, nil
+}
+
+// productListView retorna a representação da lista conforme as opções: o DTO completo ou um productViewList.
+// As expansões são carregadas produto a produto.
+func (h *ProductHandler) productListView(ctx context.Context, products []*models.Product, opts productViewOptions) (any, error) {
+	if opts.isDefault() {
+		return h.representation.products(products), nil
+	}
+
+	list := &productViewList{
+		root:  xmlRootName(h.representation.products(nil)),
+		items: make([]*productView, len(products)),
+	}
+	for i, product := range products {
+		view, err := h.productView(ctx, product, opts)
+		if err != nil {
+			return nil, err
+		}
+		list.items[i] = view.(*productView)
+	}
+	// Recursos embutidos não cabem em uma linha CSV; sem eles, as colunas são as dos campos pedidos.
+	if len(opts.expand) == 0 {
+		for _, name := range dtoFieldNames(h.representation.product(&models.Product{})) {
+			if slices.Contains(opts.fields, name) {
+				field, _ := h.representation.field(name)
+				list.columns = append(list.columns, csvProductColumns[field])
+			}
+		}
+	}
+	return list, nil
+}
+
+// dtoField é um campo de um DTO de produto, com o nome da tag json.
+type dtoField struct {
+	name  string
+	value any
+}
+
+// dtoFields lista os campos de um DTO (um ponteiro para struct) na ordem da struct.
+func dtoFields(dto any) []dtoField {
+	value := reflect.ValueOf(dto).Elem()
+	var fields []dtoField
+	for i := range value.NumField() {
+		name, _, _ := strings.Cut(value.Type().Field(i).Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		fields = append(fields, dtoField{name: name, value: value.Field(i).Interface()})
+	}
+	return fields
+}
+
+func dtoFieldNames(dto any) []string {
+	fields := dtoFields(dto)
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.name
+	}
+	return names
+}
+
+// xmlRootName retorna o nome do elemento raiz da representação XML de data.
+func xmlRootName(data any) string {
+	document := reflect.TypeOf(data.(xmlEncodable).xmlDocument())
+	field, _ := document.FieldByName("XMLName")
+	return field.Tag.Get("xml")
+}
+
+// productViewEntry é um campo ou recurso embutido de productView.
+type productViewEntry struct {
+	name  string
+	value any
+}
+
+// productView é um produto restrito aos campos pedidos, na ordem do DTO, seguidos dos recursos embutidos.
+// Em JSON, XML e MessagePack, os campos são codificados como no DTO.
+type productView struct {
+	root    string
+	entries []productViewEntry
+}
+
+func (v *productView) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, entry := range v.entries {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(entry.name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(entry.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+var _ msgpack.CustomEncoder = (*productView)(nil)
+
+func (v *productView) EncodeMsgpack(encoder *msgpack.Encoder) error {
+	if err := encoder.EncodeMapLen(len(v.entries)); err != nil {
+		return err
+	}
+	for _, entry := range v.entries {
+		if err := encoder.EncodeString(entry.name); err != nil {
+			return err
+		}
+		if err := encoder.Encode(entry.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *productView) xmlDocument() any {
+	return v
+}
+
+// MarshalXML escreve os recursos embutidos com o elemento raiz da sua própria representação XML, que
+// coincide com o nome da expansão.
+func (v *productView) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	start = xml.StartElement{Name: xml.Name{Local: v.root}}
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, entry := range v.entries {
+		var err error
+		if embedded, ok := entry.value.(xmlEncodable); ok {
+			err = encoder.Encode(embedded.xmlDocument())
+		} else {
+			err = encoder.EncodeElement(entry.value, xml.StartElement{Name: xml.Name{Local: entry.name}})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return encoder.EncodeToken(start.End())
+}
+
+// productViewList é uma lista de productView. columns são as colunas CSV, nil quando há recursos embutidos.
+type productViewList struct {
+	root    string
+	items   []*productView
+	columns []string
+}
+
+func (l *productViewList) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.items)
+}
+
+var _ msgpack.CustomEncoder = (*productViewList)(nil)
+
+func (l *productViewList) EncodeMsgpack(encoder *msgpack.Encoder) error {
+	return encoder.Encode(l.items)
+}
+
+func (l *productViewList) xmlDocument() any {
+	return l
+}
+
+func (l *productViewList) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	start = xml.StartElement{Name: xml.Name{Local: l.root}}
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, item := range l.items {
+		if err := encoder.Encode(item); err != nil {
+			return err
+		}
+	}
+	return encoder.EncodeToken(start.End())
+}
+
+func (l *productViewList) csvHeader() []string {
+	return l.columns
+}
+
+func (l *productViewList) csvRecords() [][]string {
+	records := make([][]string, len(l.items))
+	for i, item := range l.items {
+		record := make([]string, len(item.entries))
+		for j, entry := range item.entries {
+			record[j] = csvValue(entry.value)
+		}
+		records[i] = record
+	}
+	return records
+}
+
+// csvValue formata um campo de produto como em productCSVRecord.
+func csvValue(value any) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case time.Time:
+		return value.Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(value)
+	}
+}
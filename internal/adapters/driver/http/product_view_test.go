@@ -0,0 +1,106 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	httpDriver "github.com/danielrios/product-service-go/internal/adapters/driver/http"
+
+	"github.com/danielrios/product-service-go/internal/adapters/driven/memdb"
+	"github.com/danielrios/product-service-go/internal/application"
+)
+
+// newViewTestRouter cria o roteador com histórico e consultas em memória, necessários para ?expand=revisions.
+func newViewTestRouter() http.Handler {
+	repo := memdb.NewInMemoryProductRepository()
+	service := application.NewProductService(repo, application.WithHistory(repo), application.WithQueries(repo))
+	return httpDriver.NewRouter(httpDriver.Handlers{
+		Products:   httpDriver.NewProductHandler(service),
+		ProductsV2: httpDriver.NewProductHandler(service, httpDriver.WithAPIVersion(httpDriver.APIVersion2)),
+	})
+}
+
+func TestProductViews(t *testing.T) {
+	router := newViewTestRouter()
+	serve(router, http.MethodPost, "/v2/products", "application/json", `{"id": "1", "name": "Product 1", "price": 10}`)
+	serve(router, http.MethodPut, "/v2/products/1", "application/json", `{"name": "Product 1", "price": 12}`)
+
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus int
+		wantKeys   []string
+	}{
+		{"Fields On A Product", "/v2/products/1?fields=id,name,price", http.StatusOK, []string{"id", "name", "price"}},
+		{"Fields Keep The DTO Order", "/v2/products/1?fields=price&fields=id", http.StatusOK, []string{"id", "price"}},
+		{"V1 Field Names", "/products/1?fields=Name", http.StatusOK, []string{"Name"}},
+		{"Field Names Ignore Case", "/products/1?fields=name,price", http.StatusOK, []string{"Name", "Price"}},
+		{"Expand Only", "/v2/products/1?expand=revisions", http.StatusOK, []string{"id", "name", "price", "created_at", "updated_at", "revisions"}},
+		{"Fields And Expand", "/products/1?fields=ID&expand=revisions", http.StatusOK, []string{"ID", "Revisions"}},
+		{"Unknown Field", "/v2/products/1?fields=id,colour", http.StatusBadRequest, nil},
+		{"V2 Names Are Not V1 Names", "/v2/products/1?fields=CreatedAt", http.StatusBadRequest, nil},
+		{"Unknown Expansion", "/v2/products/1?expand=owner", http.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(router, http.MethodGet, tt.target, "", "")
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("Expected %d, got %d %s", tt.wantStatus, rec.Code, rec.Body)
+			}
+			if tt.wantKeys == nil {
+				return
+			}
+			var body map[string]json.RawMessage
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Expected a JSON object, got %v", err)
+			}
+			if len(body) != len(tt.wantKeys) {
+				t.Errorf("Expected keys %v, got %s", tt.wantKeys, rec.Body)
+			}
+			for _, key := range tt.wantKeys {
+				if _, ok := body[key]; !ok {
+					t.Errorf("Expected key %q, got %s", key, rec.Body)
+				}
+			}
+			if !strings.HasPrefix(rec.Body.String(), `{"`+tt.wantKeys[0]+`"`) {
+				t.Errorf("Expected %q first, got %s", tt.wantKeys[0], rec.Body)
+			}
+		})
+	}
+
+	t.Run("Expanded Revisions", func(t *testing.T) {
+		rec := serve(router, http.MethodGet, "/v2/products?fields=id&expand=revisions", "", "")
+
+		var body []struct {
+			ID        string `json:"id"`
+			Revisions []struct {
+				Revision int `json:"revision"`
+			} `json:"revisions"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || len(body) != 1 || len(body[0].Revisions) != 2 {
+			t.Errorf("Expected one product with two revisions, got %s (%v)", rec.Body, err)
+		}
+	})
+
+	t.Run("Lists Keep Their Formats", func(t *testing.T) {
+		if body := serveAccept(router, "/v2/products?fields=name,price", "text/csv").Body.String(); body != "name,price\nProduct 1,12\n" {
+			t.Errorf("Expected the selected CSV columns, got %q", body)
+		}
+		if rec := serveAccept(router, "/v2/products?expand=revisions", "text/csv"); rec.Code != http.StatusNotAcceptable {
+			t.Errorf("Expected 406 for CSV with embedded resources, got %d", rec.Code)
+		}
+		body := serveAccept(router, "/products?fields=Price&expand=revisions", "application/xml").Body.String()
+		if !strings.Contains(body, "<Products><Product><Price>12</Price><Revisions><Revision>") {
+			t.Errorf("Expected the v1 XML elements, got %s", body)
+		}
+	})
+
+	t.Run("Empty Lists", func(t *testing.T) {
+		if body := serve(router, http.MethodGet, "/v2/products?name=none&fields=id", "", "").Body.String(); strings.TrimSpace(body) != "[]" {
+			t.Errorf("Expected an empty list, got %s", body)
+		}
+	})
+}
@@ -0,0 +1,409 @@
+package http
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+const (
+	// DefaultMaxBodyBytes é o tamanho máximo padrão dos corpos de requisição.
+	DefaultMaxBodyBytes int64 = 1 << 20
+	// DefaultMaxImportBodyBytes é o tamanho máximo padrão dos arquivos CSV de importação.
+	DefaultMaxImportBodyBytes int64 = 64 << 20
+)
+
+// MaxBodyBytes limita o tamanho dos corpos de requisição ao valor retornado por limitFor, o que permite
+// limites diferentes por rota (arquivos CSV de importação costumam ser bem maiores que corpos JSON).
+// Um limite menor ou igual a zero desativa a verificação. Corpos maiores resultam em 413, tanto nos
+// handlers quanto nos middlewares que leem o corpo.
+func MaxBodyBytes(limitFor func(r *http.Request) int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if limit := limitFor(r); limit > 0 && r.Body != nil {
+				if r.ContentLength > limit {
+					writeErrorResponse(w, r, &http.MaxBytesError{Limit: limit})
+					return
+				}
+				r.Body = http.MaxBytesReader(w, r.Body, limit)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// readBodyError converte uma falha na leitura do corpo da requisição em ErrInvalidRequestBody, mantendo
+// o erro original na cadeia para que um corpo acima do limite resulte em ErrRequestBodyTooLarge.
+func readBodyError(err error) error {
+	return fmt.Errorf("%w: %w", models.ErrInvalidRequestBody, err)
+}
+
+// requestBodyFormats converte cada formato aceito nos corpos de requisição em um único valor JSON, que segue
+// então pela mesma decodificação estrita. fields são os campos da struct de destino, usados para converter
+// os valores textuais de XML e CSV; é nil quando o destino não é uma struct.
+var requestBodyFormats = map[string]func(body []byte, fields []requestField) (json.RawMessage, *ProblemError){
+	jsonMediaType:    jsonRequestBody,
+	xmlMediaType:     xmlRequestBody,
+	csvMediaType:     csvRequestBody,
+	msgpackMediaType: msgpackRequestBody,
+}
+
+// decodeRequestBody decodifica estritamente o corpo da requisição em v, que deve ser um ponteiro.
+// O Content-Type deve ser JSON, XML, CSV ou MessagePack (senão, ErrUnsupportedContentType), o corpo
+// deve conter um único valor, campos desconhecidos são rejeitados e, quando v aponta para uma struct,
+// os campos marcados com `required:"true"` devem estar presentes. Os problemas encontrados resultam em
+// ErrInvalidRequestBody, com a localização de cada um (como "body.Price"), a mesma em todos os formatos.
+func decodeRequestBody(r *http.Request, v any) error {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	toJSON, ok := requestBodyFormats[canonicalMediaType(mediaType)]
+	if !ok {
+		return fmt.Errorf("%w: got %q", models.ErrUnsupportedContentType, r.Header.Get("Content-Type"))
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return readBodyError(err)
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return invalidBody(ProblemError{Location: "body", Message: "is required"})
+	}
+
+	fields := structFields(v)
+	raw, problem := toJSON(body, fields)
+	if problem != nil {
+		return invalidBody(*problem)
+	}
+
+	var errs []ProblemError
+	if fields != nil {
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &object); err != nil || object == nil {
+			return invalidBody(ProblemError{Location: "body", Message: "must be an object"})
+		}
+		errs = checkFields(object, fields)
+	}
+
+	// A decodificação estrita ainda rejeita os campos desconhecidos de estruturas aninhadas e aponta o
+	// primeiro valor de tipo errado, se esse local ainda não tiver sido apontado acima.
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		problem := decodeProblem(err)
+		if !slices.ContainsFunc(errs, func(e ProblemError) bool { return strings.EqualFold(e.Location, problem.Location) }) {
+			errs = append(errs, problem)
+		}
+	}
+	if len(errs) > 0 {
+		return invalidBody(sortProblemErrors(errs)...)
+	}
+	return nil
+}
+
+func jsonRequestBody(body []byte, _ []requestField) (json.RawMessage, *ProblemError) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	var raw json.RawMessage
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, &ProblemError{Location: "body", Message: "is not valid JSON: " + jsonErrorMessage(err)}
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, &ProblemError{Location: "body", Message: "must contain a single JSON value"}
+	}
+	return raw, nil
+}
+
+// xmlRequestBody converte um documento XML em um objeto: os elementos filhos do elemento raiz, qualquer que
+// seja seu nome, viram campos, e elementos repetidos viram listas.
+func xmlRequestBody(body []byte, fields []requestField) (json.RawMessage, *ProblemError) {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	var document any
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, &ProblemError{Location: "body", Message: "is not valid XML: " + err.Error()}
+		}
+		switch token.(type) {
+		case xml.StartElement:
+			if document != nil {
+				return nil, &ProblemError{Location: "body", Message: "must contain a single XML root element"}
+			}
+			if document, err = readXMLElement(decoder); err != nil {
+				return nil, &ProblemError{Location: "body", Message: "is not valid XML: " + err.Error()}
+			}
+		case xml.CharData:
+			if len(bytes.TrimSpace(token.(xml.CharData))) > 0 {
+				return nil, &ProblemError{Location: "body", Message: "must contain a single XML root element"}
+			}
+		}
+	}
+
+	object, ok := document.(map[string]any)
+	if !ok {
+		return nil, &ProblemError{Location: "body", Message: "must be an XML element with child elements"}
+	}
+	coerceTextValues(object, fields)
+	return marshalRequestDocument(object)
+}
+
+// readXMLElement lê o conteúdo de um elemento até seu fim: o texto, se não tiver elementos filhos, ou um
+// mapa com os filhos.
+func readXMLElement(decoder *xml.Decoder) (any, error) {
+	var (
+		text     strings.Builder
+		children map[string]any
+		repeated = make(map[string]bool)
+	)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			value, err := readXMLElement(decoder)
+			if err != nil {
+				return nil, err
+			}
+			if children == nil {
+				children = make(map[string]any)
+			}
+			name := t.Name.Local
+			existing, ok := children[name]
+			switch {
+			case !ok:
+				children[name] = value
+			case repeated[name]:
+				children[name] = append(existing.([]any), value)
+			default:
+				children[name] = []any{existing, value}
+				repeated[name] = true
+			}
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			if children != nil {
+				return children, nil
+			}
+			return text.String(), nil
+		}
+	}
+}
+
+// csvRequestBody converte um CSV com um cabeçalho e uma única linha em um objeto, com as colunas como
+// campos. Células vazias são tratadas como campos ausentes.
+func csvRequestBody(body []byte, fields []requestField) (json.RawMessage, *ProblemError) {
+	records, err := csv.NewReader(bytes.NewReader(body)).ReadAll()
+	if err != nil {
+		return nil, &ProblemError{Location: "body", Message: "is not valid CSV: " + err.Error()}
+	}
+	if len(records) != 2 {
+		return nil, &ProblemError{Location: "body", Message: "must have a header row and exactly one data row"}
+	}
+
+	object := make(map[string]any, len(records[0]))
+	for i, column := range records[0] {
+		if records[1][i] != "" {
+			object[strings.TrimSpace(column)] = records[1][i]
+		}
+	}
+	coerceTextValues(object, fields)
+	return marshalRequestDocument(object)
+}
+
+func msgpackRequestBody(body []byte, _ []requestField) (json.RawMessage, *ProblemError) {
+	reader := bytes.NewReader(body)
+	value, err := msgpack.NewDecoder(reader).DecodeInterface()
+	if err != nil {
+		return nil, &ProblemError{Location: "body", Message: "is not valid MessagePack: " + err.Error()}
+	}
+	if reader.Len() > 0 {
+		return nil, &ProblemError{Location: "body", Message: "must contain a single MessagePack value"}
+	}
+	return marshalRequestDocument(value)
+}
+
+func marshalRequestDocument(document any) (json.RawMessage, *ProblemError) {
+	raw, err := json.Marshal(document)
+	if err != nil {
+		return nil, &ProblemError{Location: "body", Message: "cannot be represented as JSON: " + err.Error()}
+	}
+	return raw, nil
+}
+
+// coerceTextValues converte os valores textuais de XML e CSV para os tipos dos campos da struct de destino:
+// números e booleanos são interpretados, e um valor único vira lista quando o campo é uma lista. Valores que
+// não puderem ser convertidos seguem como texto e são apontados na decodificação.
+func coerceTextValues(object map[string]any, fields []requestField) {
+	for name, value := range object {
+		field, ok := findRequestField(fields, name)
+		if !ok {
+			continue
+		}
+		if field.kind == reflect.Slice {
+			if _, isList := value.([]any); !isList {
+				object[name] = []any{value}
+			}
+			continue
+		}
+		text, ok := value.(string)
+		if !ok {
+			continue
+		}
+		switch field.kind {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			if number, err := strconv.ParseFloat(strings.TrimSpace(text), 64); err == nil {
+				object[name] = number
+			}
+		case reflect.Bool:
+			if boolean, err := strconv.ParseBool(strings.TrimSpace(text)); err == nil {
+				object[name] = boolean
+			}
+		}
+	}
+}
+
+func invalidBody(errs ...ProblemError) error {
+	return &validationError{kind: models.ErrInvalidRequestBody, errors: errs}
+}
+
+// requestField é um campo JSON de uma struct de requisição. kind é o tipo do campo, sem ponteiros.
+type requestField struct {
+	name     string
+	required bool
+	kind     reflect.Kind
+}
+
+// structFields retorna os campos JSON da struct apontada por v, ou nil se v não apontar para uma struct.
+func structFields(v any) []requestField {
+	t := reflect.TypeOf(v)
+	if t == nil || t.Kind() != reflect.Pointer || t.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	t = t.Elem()
+
+	fields := make([]requestField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		fields = append(fields, requestField{name: name, required: field.Tag.Get("required") == "true", kind: fieldType.Kind()})
+	}
+	return fields
+}
+
+// findRequestField encontra o campo com o nome informado, sem diferenciar maiúsculas de minúsculas.
+func findRequestField(fields []requestField, name string) (requestField, bool) {
+	for _, field := range fields {
+		if strings.EqualFold(name, field.name) {
+			return field, true
+		}
+	}
+	return requestField{}, false
+}
+
+// checkFields aponta os campos do objeto que a struct não conhece e os obrigatórios ausentes ou nulos.
+// Como em encoding/json, os nomes são comparados sem diferenciar maiúsculas de minúsculas.
+func checkFields(object map[string]json.RawMessage, fields []requestField) []ProblemError {
+	var errs []ProblemError
+	for name := range object {
+		if _, known := findRequestField(fields, name); !known {
+			errs = append(errs, ProblemError{Location: "body." + name, Message: "is not a known field"})
+		}
+	}
+	for _, field := range fields {
+		if !field.required {
+			continue
+		}
+		present := false
+		for name, value := range object {
+			if strings.EqualFold(name, field.name) && string(value) != "null" {
+				present = true
+				break
+			}
+		}
+		if !present {
+			errs = append(errs, ProblemError{Location: "body." + field.name, Message: "is required"})
+		}
+	}
+	return errs
+}
+
+// decodeProblem localiza um erro de decodificação estrita do corpo.
+func decodeProblem(err error) ProblemError {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		location := "body"
+		if typeErr.Field != "" {
+			location += "." + typeErr.Field
+		}
+		return ProblemError{Location: location, Message: fmt.Sprintf("must be of type %s, got %s", jsonTypeName(typeErr.Type), typeErr.Value)}
+	}
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		return ProblemError{Location: "body." + strings.Trim(field, `"`), Message: "is not a known field"}
+	}
+	return ProblemError{Location: "body", Message: jsonErrorMessage(err)}
+}
+
+// jsonErrorMessage descreve um erro de sintaxe JSON, com a posição em que ocorreu.
+func jsonErrorMessage(err error) string {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return fmt.Sprintf("%s (at byte %d)", strings.TrimPrefix(syntaxErr.Error(), "json: "), syntaxErr.Offset)
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return "unexpected end of input"
+	}
+	return strings.TrimPrefix(err.Error(), "json: ")
+}
+
+// jsonTypeName traduz um tipo Go para o nome do tipo JSON correspondente.
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Pointer:
+		return jsonTypeName(t.Elem())
+	}
+	if t.String() == "time.Time" {
+		return "string"
+	}
+	return "object"
+}
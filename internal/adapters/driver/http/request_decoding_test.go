@@ -0,0 +1,89 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	httpDriver "github.com/danielrios/product-service-go/internal/adapters/driver/http"
+)
+
+func TestStrictRequestDecoding(t *testing.T) {
+	router := newTestRouter(t, httpDriver.WithMaxBodyBytes(256, 1024))
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		wantStatus int
+		wantCode   string
+		wantErrors []string
+	}{
+		{"Unknown Fields Are Rejected", http.MethodPost, "/products", `{"ID": "1", "Name": "A", "prce": 10}`,
+			http.StatusBadRequest, "invalid-request-body", []string{"body.Price", "body.prce"}},
+		{"Trailing Values Are Rejected", http.MethodPost, "/products", `{"ID": "1", "Name": "A", "Price": 1} {}`,
+			http.StatusBadRequest, "invalid-request-body", []string{"body"}},
+		{"Wrong Types Are Located", http.MethodPost, "/v2/products", `{"id": "1", "name": "A", "price": "10"}`,
+			http.StatusBadRequest, "invalid-request-body", []string{"body.price"}},
+		{"Null Required Fields Are Missing", http.MethodPost, "/v2/products", `{"id": "1", "name": null, "price": 1}`,
+			http.StatusBadRequest, "invalid-request-body", []string{"body.name"}},
+		{"Arrays Are Not Objects", http.MethodPost, "/products", `[]`,
+			http.StatusBadRequest, "invalid-request-body", []string{"body"}},
+		{"Large Bodies Are Rejected", http.MethodPost, "/products", `{"ID": "1", "Name": "` + strings.Repeat("a", 300) + `", "Price": 1}`,
+			http.StatusRequestEntityTooLarge, "request-body-too-large", nil},
+		{"Path And Body IDs Must Match", http.MethodPut, "/products/1", `{"ID": "2", "Name": "A", "Price": 1}`,
+			http.StatusBadRequest, "product-id-mismatch", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(router, tt.method, tt.target, "application/json", tt.body)
+
+			var problem httpDriver.Problem
+			_ = json.Unmarshal(rec.Body.Bytes(), &problem)
+			if rec.Code != tt.wantStatus || problem.Code != tt.wantCode {
+				t.Fatalf("Expected %d %s, got %d %s", tt.wantStatus, tt.wantCode, rec.Code, rec.Body)
+			}
+			locations := make([]string, 0, len(problem.Errors))
+			for _, problemErr := range problem.Errors {
+				locations = append(locations, problemErr.Location)
+			}
+			if tt.wantErrors != nil && !slices.Equal(locations, tt.wantErrors) {
+				t.Errorf("Expected errors at %v, got %+v", tt.wantErrors, problem.Errors)
+			}
+		})
+	}
+
+	t.Run("Content Type Must Be JSON", func(t *testing.T) {
+		rec := serve(router, http.MethodPost, "/products", "text/plain", `{"ID": "1", "Name": "A", "Price": 1}`)
+
+		if rec.Code != http.StatusUnsupportedMediaType || !strings.Contains(rec.Body.String(), `"code":"unsupported-content-type"`) {
+			t.Errorf("Expected 415 unsupported-content-type, got %d %s", rec.Code, rec.Body)
+		}
+	})
+
+	t.Run("Read Only Fields Are Accepted In V1", func(t *testing.T) {
+		body := `{"ID": "1", "Name": "A", "Price": 1, "CreatedAt": "2024-01-01T00:00:00Z", "UpdatedAt": "2024-01-01T00:00:00Z"}`
+		rec := serve(router, http.MethodPost, "/products", "application/json", body)
+
+		if rec.Code != http.StatusCreated {
+			t.Errorf("Expected 201, got %d %s", rec.Code, rec.Body)
+		}
+	})
+
+	t.Run("Imports Use Their Own Limit", func(t *testing.T) {
+		csv := "id,name,price\n" + strings.Repeat("1,Product,10\n", 40)
+		rec := serve(router, http.MethodPost, "/products/import?dry_run=true&mode=upsert", "text/csv", csv)
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected 200 for an import under its limit, got %d %s", rec.Code, rec.Body)
+		}
+
+		csv = "id,name,price\n" + strings.Repeat("1,Product,10\n", 100)
+		rec = serve(router, http.MethodPost, "/products/import?dry_run=true&mode=upsert", "text/csv", csv)
+		if rec.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("Expected 413 for an import over its limit, got %d %s", rec.Code, rec.Body)
+		}
+	})
+}
@@ -0,0 +1,255 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/application"
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// apiV1DeprecatedAt é a data em que a API v1 foi depreciada, com o lançamento da v2.
+var apiV1DeprecatedAt = time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)
+
+// Handlers reúne os handlers e serviços usados pelas rotas da API. Products atende as rotas de produtos
+// da v1 e das rotas sem versão; ProductsV2, as da v2.
+type Handlers struct {
+	Products    *ProductHandler
+	ProductsV2  *ProductHandler
+	Stream      *ProductStreamHandler
+	ChangeFeed  *ChangeFeedHandler
+	Audit       *AuditHandler
+	Jobs        *JobHandler
+	Webhooks    *WebhookHandler
+	APIKeys     *APIKeyHandler
+	Idempotency *application.IdempotencyService
+}
+
+// RouterOption configura parâmetros opcionais do roteador.
+type RouterOption func(*routerConfig)
+
+type routerConfig struct {
+	validator             *OpenAPIValidator
+	v1Sunset              time.Time
+	maxBodyBytes          int64
+	maxImportBodyBytes    int64
+	compression           CompressionConfig
+	maxDecompressionRatio int64
+	verifiers             map[string]ports.CredentialVerifier
+	authorizer            *application.Authorizer
+}
+
+// WithOpenAPIValidation valida as requisições e respostas contra a especificação OpenAPI do serviço.
+func WithOpenAPIValidation(validator *OpenAPIValidator) RouterOption {
+	return func(c *routerConfig) {
+		c.validator = validator
+	}
+}
+
+// WithV1Sunset define a data de desativação das rotas de produtos da v1, informada no cabeçalho Sunset.
+func WithV1Sunset(sunset time.Time) RouterOption {
+	return func(c *routerConfig) {
+		c.v1Sunset = sunset
+	}
+}
+
+// WithMaxBodyBytes define o tamanho máximo dos corpos de requisição (padrão DefaultMaxBodyBytes) e o dos
+// arquivos CSV de importação (padrão DefaultMaxImportBodyBytes). Um valor menor ou igual a zero remove o limite.
+func WithMaxBodyBytes(limit, importLimit int64) RouterOption {
+	return func(c *routerConfig) {
+		c.maxBodyBytes = limit
+		c.maxImportBodyBytes = importLimit
+	}
+}
+
+// WithCompression configura a compressão das respostas (padrão DefaultCompressionConfig). Uma lista de
+// tipos de conteúdo vazia desativa a compressão.
+func WithCompression(config CompressionConfig) RouterOption {
+	return func(c *routerConfig) {
+		c.compression = config
+	}
+}
+
+// WithMaxDecompressionRatio define a razão máxima entre o tamanho descomprimido e o comprimido dos corpos
+// de requisição enviados com compressão (padrão DefaultMaxDecompressionRatio). Um valor menor ou igual a
+// zero remove esse limite, mas o corpo descomprimido continua sujeito ao tamanho máximo da rota.
+func WithMaxDecompressionRatio(ratio int64) RouterOption {
+	return func(c *routerConfig) {
+		c.maxDecompressionRatio = ratio
+	}
+}
+
+// WithAuthentication exige autenticação em todas as rotas, exceto /openapi.json, com as credenciais
+// verificadas pelo CredentialVerifier do esquema do cabeçalho Authorization (por exemplo, AuthSchemeBearer).
+// Sem essa opção, a API não exige autenticação.
+func WithAuthentication(verifiers map[string]ports.CredentialVerifier) RouterOption {
+	return func(c *routerConfig) {
+		c.verifiers = verifiers
+	}
+}
+
+// WithAuthorization exige, em cada rota de produtos, que os papéis do principal concedam a permissão da
+// operação (por exemplo, products:delete em DELETE /products/{id}). Deve acompanhar WithAuthentication.
+func WithAuthorization(authorizer *application.Authorizer) RouterOption {
+	return func(c *routerConfig) {
+		c.authorizer = authorizer
+	}
+}
+
+// permit retorna o middleware que exige a permissão, ou um que não faz nada sem WithAuthorization.
+func (c *routerConfig) permit(permission models.Permission) func(http.Handler) http.Handler {
+	return RequirePermission(c.authorizer, permission)
+}
+
+// isPublic informa se a requisição dispensa autenticação: apenas o documento OpenAPI é público.
+func (c *routerConfig) isPublic(r *http.Request) bool {
+	return r.URL.Path == "/openapi.json"
+}
+
+// bodyLimit retorna o tamanho máximo do corpo da requisição: o de importação nas rotas de importação
+// de produtos e o geral nas demais.
+func (c *routerConfig) bodyLimit(r *http.Request) int64 {
+	if strings.HasSuffix(strings.TrimSuffix(r.URL.Path, "/"), "/products/import") {
+		return c.maxImportBodyBytes
+	}
+	return c.maxBodyBytes
+}
+
+// NewRouter cria o roteador com os middlewares e todas as rotas da API. As rotas devem ser mantidas
+// em sincronia com openapi.json. As rotas atuais ficam em /v1 e, por compatibilidade, também sem prefixo;
+// as rotas de produtos da v1 são depreciadas em favor das de /v2/products.
+func NewRouter(h Handlers, opts ...RouterOption) *chi.Mux {
+	config := routerConfig{
+		maxBodyBytes:          DefaultMaxBodyBytes,
+		maxImportBodyBytes:    DefaultMaxImportBodyBytes,
+		compression:           DefaultCompressionConfig(),
+		maxDecompressionRatio: DefaultMaxDecompressionRatio,
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	r := chi.NewRouter()
+	r.Use(middleware.Logger)
+	r.Use(middleware.Recoverer)
+	r.Use(middleware.RequestID)
+	r.Use(middleware.RealIP)
+	if len(config.compression.ContentTypes) > 0 {
+		r.Use(Compress(config.compression))
+	}
+	r.Use(RequestContext)
+	if len(config.verifiers) > 0 {
+		r.Use(Authenticate(config.verifiers, config.isPublic))
+	}
+	r.Use(MaxBodyBytes(config.bodyLimit))
+	if config.validator != nil {
+		r.Use(config.validator.Middleware)
+	}
+	r.NotFound(NotFound)
+	r.MethodNotAllowed(MethodNotAllowed(r))
+
+	r.Get("/openapi.json", OpenAPIHandler)
+
+	deprecated := Deprecated(apiV1DeprecatedAt, config.v1Sunset)
+	decompress := DecompressRequestBody(config.bodyLimit, config.maxDecompressionRatio)
+	r.Group(func(r chi.Router) { v1Routes(r, h, &config, deprecated, decompress) })
+	r.Route("/v1", func(r chi.Router) { v1Routes(r, h, &config, deprecated, decompress) })
+
+	r.Route("/v2/products", func(r chi.Router) {
+		r.Use(productScopes)
+		r.Use(Idempotency(h.Idempotency))
+		productRoutes(r, h.ProductsV2, nil, decompress, config.permit, nil)
+	})
+
+	return r
+}
+
+// productScopes exige products:read nas leituras e products:write nas alterações dos produtos e dos
+// recursos derivados deles; webhooks e chaves de API exigem adminScope e PermissionAPIKeysManage.
+var (
+	productScopes = RequireScopes(models.ScopeProductsRead, models.ScopeProductsWrite)
+	adminScope    = RequireScopes(models.ScopeAdmin, models.ScopeAdmin)
+)
+
+// v1Routes registra as rotas da API v1. As rotas de produtos atendidas pelo ProductHandler recebem
+// o middleware de depreciação; as demais ainda não têm equivalente na v2. As rotas que trazem produtos
+// completos, fora do ProductHandler, também exigem a leitura de todos os campos.
+func v1Routes(r chi.Router, h Handlers, config *routerConfig, deprecated, decompress func(http.Handler) http.Handler) {
+	read := config.permit(models.PermissionProductsRead)
+	readAll := RequireReadableProducts(config.authorizer)
+	r.Route("/products", func(r chi.Router) {
+		r.Use(productScopes)
+		r.Use(Idempotency(h.Idempotency))
+
+		r.With(read, readAll).Get("/stream", h.Stream.StreamHandler)
+		productRoutes(r, h.Products, []func(http.Handler) http.Handler{deprecated}, decompress, config.permit, func(r chi.Router) {
+			r.With(read, readAll).Get("/audit", h.Audit.GetProductAuditHandler)
+		})
+	})
+
+	r.With(productScopes, read, readAll).Get("/changes", h.ChangeFeed.GetChangesHandler)
+	r.With(productScopes, read, readAll).Get("/audit", h.Audit.ListAuditHandler)
+
+	r.Route("/jobs/{id}", func(r chi.Router) {
+		r.Use(productScopes)
+		r.Use(config.permit(models.PermissionProductsImport))
+		r.Get("/", h.Jobs.GetJobHandler)
+		r.Post("/cancel", h.Jobs.CancelJobHandler)
+	})
+
+	r.Route("/webhooks", func(r chi.Router) {
+		r.Use(adminScope)
+		r.Use(config.permit(models.PermissionAPIKeysManage))
+		r.Get("/", h.Webhooks.ListSubscriptionsHandler)
+		r.Post("/", h.Webhooks.CreateSubscriptionHandler)
+
+		r.Route("/{id}", func(r chi.Router) {
+			r.Get("/", h.Webhooks.GetSubscriptionHandler)
+			r.Put("/", h.Webhooks.UpdateSubscriptionHandler)
+			r.Delete("/", h.Webhooks.DeleteSubscriptionHandler)
+			r.Get("/deliveries", h.Webhooks.ListDeliveriesHandler)
+			r.Post("/deliveries/{deliveryID}/retry", h.Webhooks.RetryDeliveryHandler)
+		})
+	})
+
+	r.Route("/api-keys", func(r chi.Router) {
+		r.Use(adminScope)
+		r.Use(config.permit(models.PermissionAPIKeysManage))
+		r.Get("/", h.APIKeys.ListAPIKeysHandler)
+		r.Post("/", h.APIKeys.CreateAPIKeyHandler)
+		r.Get("/{id}", h.APIKeys.GetAPIKeyHandler)
+		r.Delete("/{id}", h.APIKeys.RevokeAPIKeyHandler)
+	})
+}
+
+// productRoutes registra as rotas atendidas pelo ProductHandler, com os middlewares informados e a
+// permissão de cada operação, exigida por permit. As rotas em lote também recebem decompress, que aceita
+// corpos comprimidos. extraItemRoutes, se não for nil, acrescenta em /{id} rotas de outros handlers, sem
+// esses middlewares.
+func productRoutes(r chi.Router, products *ProductHandler, middlewares []func(http.Handler) http.Handler, decompress func(http.Handler) http.Handler, permit func(models.Permission) func(http.Handler) http.Handler, extraItemRoutes func(chi.Router)) {
+	read := permit(models.PermissionProductsRead)
+	update := permit(models.PermissionProductsUpdate)
+
+	pr := r.With(middlewares...)
+	pr.With(read).Get("/", products.GetAllProductsHandler)
+	pr.With(permit(models.PermissionProductsCreate)).Post("/", products.CreateProductHandler)
+	pr.With(read).Get("/stats", products.GetProductStatsHandler)
+	pr.With(read).Get("/export", products.ExportProductsHandler)
+	pr.With(permit(models.PermissionProductsImport), decompress).Post("/import", products.ImportProductsHandler)
+
+	r.Route("/{id}", func(r chi.Router) {
+		pr := r.With(middlewares...)
+		pr.With(read).Get("/", products.GetProductByIDHandler)
+		pr.With(update).Put("/", products.UpdateProductHandler)
+		pr.With(update).Patch("/", products.PatchProductHandler)
+		pr.With(permit(models.PermissionProductsDelete)).Delete("/", products.DeleteProductHandler)
+		pr.With(read).Get("/revisions", products.GetProductRevisionsHandler)
+		if extraItemRoutes != nil {
+			extraItemRoutes(r)
+		}
+	})
+}
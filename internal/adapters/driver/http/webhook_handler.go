@@ -0,0 +1,136 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/danielrios/product-service-go/internal/application"
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	defaultDeliveriesLimit = 50
+	maxDeliveriesLimit     = 500
+)
+
+// WebhookHandler é o Adaptador de Entrada HTTP para o gerenciamento de webhooks.
+type WebhookHandler struct {
+	service *application.WebhookService
+}
+
+// NewWebhookHandler cria e retorna uma nova instância de WebhookHandler.
+func NewWebhookHandler(service *application.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		service: service,
+	}
+}
+
+// withoutSecret retorna uma cópia da inscrição sem o segredo, que só é exibido na criação.
+func withoutSecret(sub *models.WebhookSubscription) *models.WebhookSubscription {
+	masked := *sub
+	masked.Secret = ""
+	return &masked
+}
+
+// CreateSubscriptionHandler lida com a requisição POST /webhooks.
+func (h *WebhookHandler) CreateSubscriptionHandler(w http.ResponseWriter, r *http.Request) {
+	var sub models.WebhookSubscription
+	if err := decodeRequestBody(r, &sub); err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+
+	created, err := h.service.CreateSubscription(&sub)
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+
+	writeResponse(w, r, http.StatusCreated, created)
+}
+
+// ListSubscriptionsHandler lida com a requisição GET /webhooks.
+func (h *WebhookHandler) ListSubscriptionsHandler(w http.ResponseWriter, r *http.Request) {
+	subs, err := h.service.ListSubscriptions()
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+
+	masked := make([]*models.WebhookSubscription, 0, len(subs))
+	for _, sub := range subs {
+		masked = append(masked, withoutSecret(sub))
+	}
+	writeResponse(w, r, http.StatusOK, masked)
+}
+
+// GetSubscriptionHandler lida com a requisição GET /webhooks/{id}.
+func (h *WebhookHandler) GetSubscriptionHandler(w http.ResponseWriter, r *http.Request) {
+	sub, err := h.service.GetSubscription(chi.URLParam(r, "id"))
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, withoutSecret(sub))
+}
+
+// UpdateSubscriptionHandler lida com a requisição PUT /webhooks/{id}.
+func (h *WebhookHandler) UpdateSubscriptionHandler(w http.ResponseWriter, r *http.Request) {
+	var changes models.WebhookSubscription
+	if err := decodeRequestBody(r, &changes); err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+
+	updated, err := h.service.UpdateSubscription(chi.URLParam(r, "id"), &changes)
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, withoutSecret(updated))
+}
+
+// DeleteSubscriptionHandler lida com a requisição DELETE /webhooks/{id}.
+func (h *WebhookHandler) DeleteSubscriptionHandler(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.DeleteSubscription(chi.URLParam(r, "id")); err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListDeliveriesHandler lida com a requisição GET /webhooks/{id}/deliveries?limit=N.
+func (h *WebhookHandler) ListDeliveriesHandler(w http.ResponseWriter, r *http.Request) {
+	limit := defaultDeliveriesLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxDeliveriesLimit {
+			writeErrorResponse(w, r, models.ErrInvalidDeliveryQuery)
+			return
+		}
+		limit = parsed
+	}
+
+	deliveries, err := h.service.ListDeliveries(chi.URLParam(r, "id"), limit)
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, deliveries)
+}
+
+// RetryDeliveryHandler lida com a requisição POST /webhooks/{id}/deliveries/{deliveryID}/retry.
+func (h *WebhookHandler) RetryDeliveryHandler(w http.ResponseWriter, r *http.Request) {
+	delivery, err := h.service.RetryDelivery(chi.URLParam(r, "id"), chi.URLParam(r, "deliveryID"))
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+
+	writeResponse(w, r, http.StatusAccepted, delivery)
+}
@@ -0,0 +1,137 @@
+package application
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+// APIKeyConfig agrupa os parâmetros das chaves de API.
+type APIKeyConfig struct {
+	// LastUsedInterval é a precisão do registro de último uso: uma chave usada novamente antes desse
+	// intervalo não é regravada, o que evita uma escrita no banco a cada requisição.
+	LastUsedInterval time.Duration
+	// Roles é a matriz de autorização em uso: apenas os seus papéis podem ser atribuídos às chaves.
+	Roles models.RolePermissions
+}
+
+// DefaultAPIKeyConfig retorna a configuração padrão das chaves de API, com a matriz de papéis padrão.
+func DefaultAPIKeyConfig() APIKeyConfig {
+	return APIKeyConfig{
+		LastUsedInterval: time.Minute,
+		Roles:            models.DefaultRolePermissions(),
+	}
+}
+
+// APIKeyService emite, lista e revoga as chaves de API e autentica as requisições que as usam.
+// Ele implementa ports.CredentialVerifier para o esquema "Authorization: ApiKey <chave>".
+type APIKeyService struct {
+	repo   ports.APIKeyRepository
+	config APIKeyConfig
+}
+
+// NewAPIKeyService cria e retorna uma nova instância de APIKeyService.
+func NewAPIKeyService(repo ports.APIKeyRepository, config APIKeyConfig) *APIKeyService {
+	return &APIKeyService{
+		repo:   repo,
+		config: config,
+	}
+}
+
+var _ ports.CredentialVerifier = (*APIKeyService)(nil)
+
+// CreateAPIKey valida e registra uma nova chave em nome do autor da requisição. A chave completa é
+// retornada apenas aqui; depois, só o seu hash fica guardado. Um principal só emite chaves com escopos
+// que ele mesmo tem, ou o resultado é models.ErrInsufficientScope, e com papéis que não concedem
+// permissões além das dos seus próprios papéis, ou o resultado é models.ErrForbidden.
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, name string, scopes, roles []string, expiresAt *time.Time) (*models.APIKey, string, error) {
+	key, secret, err := models.NewAPIKey(name, scopes, roles, expiresAt, ActorFrom(ctx))
+	if err != nil {
+		return nil, "", err
+	}
+	for _, role := range roles {
+		if _, ok := s.config.Roles[role]; !ok {
+			return nil, "", fmt.Errorf("%w: unknown role %q", models.ErrInvalidAPIKey, role)
+		}
+	}
+	if principal := PrincipalFrom(ctx); principal != nil {
+		for _, scope := range scopes {
+			if !principal.HasScope(scope) {
+				return nil, "", fmt.Errorf("%w: cannot grant %s", models.ErrInsufficientScope, scope)
+			}
+		}
+		for _, role := range roles {
+			for _, permission := range s.config.Roles[role] {
+				if !s.config.Roles.Allows(principal.Roles, permission) {
+					return nil, "", fmt.Errorf("%w: %s cannot grant role %q, which requires %s", models.ErrForbidden, principal.Subject, role, permission)
+				}
+			}
+		}
+	}
+	if err := s.repo.AddAPIKey(key); err != nil {
+		return nil, "", err
+	}
+	return key, secret, nil
+}
+
+// GetAPIKey busca uma chave pelo ID.
+func (s *APIKeyService) GetAPIKey(id string) (*models.APIKey, error) {
+	return s.repo.GetAPIKey(id)
+}
+
+// ListAPIKeys retorna todas as chaves, inclusive as revogadas e as expiradas.
+func (s *APIKeyService) ListAPIKeys() ([]*models.APIKey, error) {
+	return s.repo.ListAPIKeys()
+}
+
+// RevokeAPIKey revoga uma chave, que deixa de autenticar requisições imediatamente.
+func (s *APIKeyService) RevokeAPIKey(id string) error {
+	return s.repo.RevokeAPIKey(id, time.Now())
+}
+
+// Verify autentica uma chave completa e retorna o principal "api-key:<ID>", com os escopos e papéis da chave.
+// Chaves desconhecidas, revogadas ou expiradas resultam em models.ErrInvalidCredentials.
+func (s *APIKeyService) Verify(ctx context.Context, credentials string) (*models.Principal, error) {
+	id, secret, ok := models.ParseAPIKey(credentials)
+	if !ok {
+		return nil, fmt.Errorf("%w: malformed API key", models.ErrInvalidCredentials)
+	}
+	key, err := s.repo.GetAPIKey(id)
+	if errors.Is(err, models.ErrAPIKeyNotFound) {
+		return nil, fmt.Errorf("%w: unknown API key", models.ErrInvalidCredentials)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(models.HashAPIKeySecret(secret)), []byte(key.SecretHash)) != 1 {
+		return nil, fmt.Errorf("%w: unknown API key", models.ErrInvalidCredentials)
+	}
+
+	now := time.Now()
+	if !key.IsActive(now) {
+		return nil, fmt.Errorf("%w: API key is revoked or expired", models.ErrInvalidCredentials)
+	}
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= s.config.LastUsedInterval {
+		// Uma falha no registro do último uso não impede a requisição.
+		if err := s.repo.TouchAPIKey(key.ID, now); err != nil {
+			log.Printf("Erro ao registrar o uso da chave de API %s: %v", key.ID, err)
+		}
+	}
+
+	principal := &models.Principal{
+		Subject: "api-key:" + key.ID,
+		Method:  models.AuthMethodAPIKey,
+		Scopes:  key.Scopes,
+		Roles:   key.Roles,
+	}
+	if key.ExpiresAt != nil {
+		principal.ExpiresAt = *key.ExpiresAt
+	}
+	return principal, nil
+}
@@ -0,0 +1,103 @@
+package application_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/adapters/driven/memdb"
+	"github.com/danielrios/product-service-go/internal/application"
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+func TestAPIKeyService(t *testing.T) {
+	repo := memdb.NewInMemoryAPIKeyRepository()
+	service := application.NewAPIKeyService(repo, application.DefaultAPIKeyConfig())
+	ctx := application.WithActor(context.Background(), "admin")
+
+	key, secret, err := service.CreateAPIKey(ctx, "batch", []string{models.ScopeProductsWrite}, []string{models.RoleEditor}, nil)
+	if err != nil {
+		t.Fatalf("Expected the key to be created, got %v", err)
+	}
+	if key.CreatedBy != "admin" || key.SecretHash == "" || key.SecretHash == secret {
+		t.Errorf("Expected a hashed key created by the actor, got %+v", key)
+	}
+
+	t.Run("Verify", func(t *testing.T) {
+		principal, err := service.Verify(context.Background(), secret)
+		if err != nil {
+			t.Fatalf("Expected the key to authenticate, got %v", err)
+		}
+		if principal.Subject != "api-key:"+key.ID || principal.Method != models.AuthMethodAPIKey || !principal.HasScope(models.ScopeProductsWrite) ||
+			!slices.Equal(principal.Roles, []string{models.RoleEditor}) {
+			t.Errorf("Unexpected principal %+v", principal)
+		}
+
+		stored, _ := repo.GetAPIKey(key.ID)
+		if stored.LastUsedAt == nil {
+			t.Fatal("Expected the last use to be recorded")
+		}
+		lastUsed := *stored.LastUsedAt
+		_, _ = service.Verify(context.Background(), secret)
+		if stored, _ := repo.GetAPIKey(key.ID); !stored.LastUsedAt.Equal(lastUsed) {
+			t.Errorf("Expected the last use to be recorded at most once a minute, got %v and %v", lastUsed, stored.LastUsedAt)
+		}
+	})
+
+	t.Run("Unknown Role", func(t *testing.T) {
+		if _, _, err := service.CreateAPIKey(ctx, "auditor", []string{models.ScopeProductsRead}, []string{"auditor"}, nil); !errors.Is(err, models.ErrInvalidAPIKey) {
+			t.Errorf("Expected ErrInvalidAPIKey, got %v", err)
+		}
+	})
+
+	t.Run("Scope Escalation", func(t *testing.T) {
+		writer := application.WithPrincipal(ctx, &models.Principal{Subject: "api-key:writer", Scopes: []string{models.ScopeProductsWrite}, Roles: []string{models.RoleEditor}})
+		if _, _, err := service.CreateAPIKey(writer, "escalated", []string{models.ScopeAdmin}, []string{models.RoleEditor}, nil); !errors.Is(err, models.ErrInsufficientScope) {
+			t.Errorf("Expected ErrInsufficientScope, got %v", err)
+		}
+		if _, _, err := service.CreateAPIKey(writer, "same", []string{models.ScopeProductsWrite}, []string{models.RoleEditor}, nil); err != nil {
+			t.Errorf("Expected a key with the caller's own scope to be created, got %v", err)
+		}
+	})
+
+	t.Run("Role Escalation", func(t *testing.T) {
+		editor := application.WithPrincipal(ctx, &models.Principal{Subject: "api-key:editor", Scopes: []string{models.ScopeAdmin}, Roles: []string{models.RoleEditor}})
+		if _, _, err := service.CreateAPIKey(editor, "escalated", []string{models.ScopeAdmin}, []string{models.RoleAdmin}, nil); !errors.Is(err, models.ErrForbidden) {
+			t.Errorf("Expected ErrForbidden, got %v", err)
+		}
+		if _, _, err := service.CreateAPIKey(editor, "viewer", []string{models.ScopeProductsRead}, []string{models.RoleViewer}, nil); err != nil {
+			t.Errorf("Expected a key with a subset of the caller's permissions to be created, got %v", err)
+		}
+	})
+
+	expiresAt := time.Now().Add(50 * time.Millisecond)
+	_, expiring, err := service.CreateAPIKey(ctx, "expiring", []string{models.ScopeProductsRead}, []string{models.RoleViewer}, &expiresAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	revoked, revokedSecret, _ := service.CreateAPIKey(ctx, "revoked", []string{models.ScopeProductsRead}, []string{models.RoleViewer}, nil)
+	if err := service.RevokeAPIKey(revoked.ID); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(60 * time.Millisecond)
+
+	tests := []struct {
+		name string
+		key  string
+	}{
+		{"Malformed", "not-a-key"},
+		{"Unknown", models.APIKeyPrefix + "missing_secret"},
+		{"Wrong Secret", models.APIKeyPrefix + key.ID + "_wrong"},
+		{"Expired", expiring},
+		{"Revoked", revokedSecret},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.Verify(context.Background(), tt.key); !errors.Is(err, models.ErrInvalidCredentials) {
+				t.Errorf("Expected ErrInvalidCredentials, got %v", err)
+			}
+		})
+	}
+}
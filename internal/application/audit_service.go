@@ -0,0 +1,41 @@
+package application
+
+import (
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+// Limites de paginação das consultas ao log de auditoria.
+const (
+	DefaultAuditLimit = 100
+	MaxAuditLimit     = 1000
+)
+
+// AuditService expõe as consultas ao log de auditoria de produtos.
+type AuditService struct {
+	repo ports.AuditRepository
+}
+
+// NewAuditService cria e retorna uma nova instância de AuditService.
+func NewAuditService(repo ports.AuditRepository) *AuditService {
+	return &AuditService{
+		repo: repo,
+	}
+}
+
+// ListEntries retorna os registros que atendem ao filtro, dos mais recentes para os mais antigos.
+func (s *AuditService) ListEntries(filter models.AuditFilter) ([]*models.AuditEntry, error) {
+	if filter.Limit == 0 {
+		filter.Limit = DefaultAuditLimit
+	}
+	if filter.Limit < 1 || filter.Limit > MaxAuditLimit {
+		return nil, models.ErrInvalidAuditQuery
+	}
+	if filter.Operation != "" && !filter.Operation.IsValid() {
+		return nil, models.ErrInvalidAuditQuery
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.To.Before(filter.From) {
+		return nil, models.ErrInvalidAuditQuery
+	}
+	return s.repo.ListAuditEntries(filter)
+}
@@ -0,0 +1,56 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+// Authorizer decide se o principal do contexto pode executar uma operação, pelos papéis do principal e
+// pela matriz de papéis e permissões configurada, e quais campos de produtos ele pode alterar e ler.
+type Authorizer struct {
+	roles  models.RolePermissions
+	fields models.ProductFieldPolicy
+}
+
+// NewAuthorizer valida a matriz e a política de campos e cria um novo Authorizer.
+func NewAuthorizer(roles models.RolePermissions, fields models.ProductFieldPolicy) (*Authorizer, error) {
+	if err := roles.Validate(); err != nil {
+		return nil, err
+	}
+	if err := fields.Validate(); err != nil {
+		return nil, err
+	}
+	return &Authorizer{roles: roles, fields: fields}, nil
+}
+
+// Authorize retorna nil se algum papel do principal do contexto concede a permissão. Sem principal, a
+// operação é recusada com models.ErrUnauthenticated; com papéis insuficientes, com models.ErrForbidden.
+func (a *Authorizer) Authorize(ctx context.Context, permission models.Permission) error {
+	principal := PrincipalFrom(ctx)
+	if principal == nil {
+		return models.ErrUnauthenticated
+	}
+	if !a.roles.Allows(principal.Roles, permission) {
+		return fmt.Errorf("%w: %s requires %s", models.ErrForbidden, principal.Subject, permission)
+	}
+	return nil
+}
+
+// WritableFields retorna os campos de produtos que o principal do contexto pode alterar na operação.
+func (a *Authorizer) WritableFields(ctx context.Context, permission models.Permission) []models.ProductField {
+	return a.fields.WritableFields(a.roles, principalRoles(ctx), permission)
+}
+
+// ReadableFields retorna os campos de produtos que o principal do contexto pode ler.
+func (a *Authorizer) ReadableFields(ctx context.Context) []models.ProductField {
+	return a.fields.ReadableFields(a.roles, principalRoles(ctx))
+}
+
+func principalRoles(ctx context.Context) []string {
+	if principal := PrincipalFrom(ctx); principal != nil {
+		return principal.Roles
+	}
+	return nil
+}
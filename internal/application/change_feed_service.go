@@ -0,0 +1,55 @@
+package application
+
+import (
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+// Limites de paginação do feed de alterações.
+const (
+	DefaultChangesLimit = 100
+	MaxChangesLimit     = 1000
+)
+
+// ChangeFeedPage é uma página do feed de alterações. NextSince é a sequência a ser usada como
+// checkpoint na próxima consulta; HasMore indica se já existem alterações além desta página.
+type ChangeFeedPage struct {
+	Changes   []*models.ProductChange
+	NextSince int64
+	HasMore   bool
+}
+
+// ChangeFeedService expõe o feed de alterações para consumidores que precisam se manter sincronizados.
+type ChangeFeedService struct {
+	repo ports.ChangeFeedRepository
+}
+
+// NewChangeFeedService cria e retorna uma nova instância de ChangeFeedService.
+func NewChangeFeedService(repo ports.ChangeFeedRepository) *ChangeFeedService {
+	return &ChangeFeedService{
+		repo: repo,
+	}
+}
+
+// GetChanges retorna até limit alterações posteriores à sequência since.
+func (s *ChangeFeedService) GetChanges(since int64, limit int) (*ChangeFeedPage, error) {
+	if since < 0 || limit < 1 || limit > MaxChangesLimit {
+		return nil, models.ErrInvalidChangeQuery
+	}
+
+	// Busca um registro a mais para saber se há outra página sem uma consulta adicional.
+	changes, err := s.repo.ChangesSince(since, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &ChangeFeedPage{Changes: changes, NextSince: since}
+	if len(changes) > limit {
+		page.Changes = changes[:limit]
+		page.HasMore = true
+	}
+	if len(page.Changes) > 0 {
+		page.NextSince = page.Changes[len(page.Changes)-1].Sequence
+	}
+	return page, nil
+}
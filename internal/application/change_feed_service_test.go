@@ -0,0 +1,58 @@
+package application_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/danielrios/product-service-go/internal/adapters/driven/memdb"
+	"github.com/danielrios/product-service-go/internal/application"
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+func TestChangeFeedService_GetChanges(t *testing.T) {
+	newFeed := func(productCount int) *application.ChangeFeedService {
+		repo := memdb.NewInMemoryProductRepository()
+		for i := 1; i <= productCount; i++ {
+			product, _ := models.NewProduct(string(rune('0'+i)), "Product", 10)
+			_ = repo.Add(product, models.Mutation{})
+		}
+		return application.NewChangeFeedService(repo)
+	}
+
+	t.Run("Pages Through The Feed", func(t *testing.T) {
+		feed := newFeed(3)
+
+		first, err := feed.GetChanges(0, 2)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(first.Changes) != 2 || !first.HasMore || first.NextSince != 2 {
+			t.Errorf("Unexpected first page: %+v", first)
+		}
+
+		second, _ := feed.GetChanges(first.NextSince, 2)
+		if len(second.Changes) != 1 || second.HasMore || second.NextSince != 3 {
+			t.Errorf("Unexpected second page: %+v", second)
+		}
+	})
+
+	t.Run("Empty Page Keeps Checkpoint", func(t *testing.T) {
+		feed := newFeed(1)
+
+		page, _ := feed.GetChanges(1, 10)
+
+		if len(page.Changes) != 0 || page.NextSince != 1 || page.HasMore {
+			t.Errorf("Unexpected empty page: %+v", page)
+		}
+	})
+
+	t.Run("Rejects Invalid Parameters", func(t *testing.T) {
+		feed := newFeed(0)
+
+		for _, params := range [][2]int{{-1, 10}, {0, 0}, {0, application.MaxChangesLimit + 1}} {
+			if _, err := feed.GetChanges(int64(params[0]), params[1]); !errors.Is(err, models.ErrInvalidChangeQuery) {
+				t.Errorf("Expected ErrInvalidChangeQuery for since=%d limit=%d, got %v", params[0], params[1], err)
+			}
+		}
+	})
+}
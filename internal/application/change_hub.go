@@ -0,0 +1,219 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+// ChangeHubConfig agrupa os parâmetros do ChangeHub.
+type ChangeHubConfig struct {
+	BacklogSize      int           // Quantidade de notificações mantidas para retomada.
+	SubscriberBuffer int           // Notificações pendentes por assinante antes de ele ser desconectado.
+	RetryInterval    time.Duration // Espera antes de voltar a escutar a fonte após um erro.
+}
+
+// DefaultChangeHubConfig retorna a configuração padrão do hub.
+func DefaultChangeHubConfig() ChangeHubConfig {
+	return ChangeHubConfig{
+		BacklogSize:      1000,
+		SubscriberBuffer: 64,
+		RetryInterval:    time.Second,
+	}
+}
+
+// ChangeNotification é um evento de produto numerado pelo hub.
+// O ID tem o formato "<época>-<sequência>": a época muda a cada inicialização do hub,
+// o que impede que um ID anterior a um reinício seja confundido com um ID atual.
+type ChangeNotification struct {
+	ID    string
+	Event models.ProductEvent
+	seq   uint64
+}
+
+// ChangeFilter restringe as notificações entregues a um assinante. Listas vazias não filtram.
+type ChangeFilter struct {
+	ProductIDs []string
+	Types      []models.EventType
+}
+
+// Matches informa se o evento atende ao filtro.
+func (f ChangeFilter) Matches(event models.ProductEvent) bool {
+	if len(f.ProductIDs) > 0 && !slices.Contains(f.ProductIDs, event.ProductID) {
+		return false
+	}
+	return len(f.Types) == 0 || slices.Contains(f.Types, event.Type)
+}
+
+// ChangeSubscription recebe as notificações do hub. O canal C é fechado quando o assinante
+// é desconectado por lentidão ou quando o hub é encerrado.
+type ChangeSubscription struct {
+	C      <-chan ChangeNotification
+	ch     chan ChangeNotification
+	filter ChangeFilter
+}
+
+// ChangeHub distribui, em memória, os eventos de produtos recebidos de uma ports.ProductEventSource
+// a vários assinantes, mantendo um histórico limitado que permite retomar uma conexão interrompida.
+type ChangeHub struct {
+	source ports.ProductEventSource
+	config ChangeHubConfig
+	epoch  int64
+
+	mu          sync.Mutex
+	seq         uint64
+	backlog     []ChangeNotification
+	subscribers map[*ChangeSubscription]struct{}
+	closed      bool
+}
+
+// NewChangeHub cria e retorna uma nova instância de ChangeHub.
+func NewChangeHub(source ports.ProductEventSource, config ChangeHubConfig) *ChangeHub {
+	return &ChangeHub{
+		source:      source,
+		config:      config,
+		epoch:       time.Now().UnixNano(),
+		subscribers: make(map[*ChangeSubscription]struct{}),
+	}
+}
+
+// Run escuta a fonte de eventos até que o contexto seja cancelado, reconectando após falhas.
+func (h *ChangeHub) Run(ctx context.Context) {
+	for {
+		if err := h.source.Listen(ctx, h.Publish); err != nil {
+			log.Printf("Erro ao escutar eventos de produtos: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(h.config.RetryInterval):
+		}
+	}
+}
+
+// Publish numera o evento, guarda-o no histórico e o entrega aos assinantes interessados.
+// Assinantes cujo buffer está cheio são desconectados, em vez de atrasar os demais.
+func (h *ChangeHub) Publish(event models.ProductEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return
+	}
+
+	h.seq++
+	notification := ChangeNotification{ID: fmt.Sprintf("%d-%d", h.epoch, h.seq), Event: event, seq: h.seq}
+	h.backlog = append(h.backlog, notification)
+	if len(h.backlog) > h.config.BacklogSize {
+		h.backlog = h.backlog[len(h.backlog)-h.config.BacklogSize:]
+	}
+
+	for sub := range h.subscribers {
+		if !sub.filter.Matches(event) {
+			continue
+		}
+		select {
+		case sub.ch <- notification:
+		default:
+			h.remove(sub)
+		}
+	}
+}
+
+// Subscribe registra um novo assinante. Quando lastID é informado, retorna também as notificações
+// posteriores a ele que ainda estão no histórico; resumed é falso se não foi possível retomar a
+// partir de lastID (época diferente ou histórico já descartado), caso em que o cliente deve recarregar seu estado.
+func (h *ChangeHub) Subscribe(lastID string, filter ChangeFilter) (sub *ChangeSubscription, missed []ChangeNotification, resumed bool, err error) {
+	var lastSeq uint64
+	resumed = true
+	if lastID != "" {
+		epoch, seq, err := parseChangeID(lastID)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		lastSeq = seq
+		resumed = epoch == h.epoch
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ch := make(chan ChangeNotification, h.config.SubscriberBuffer)
+	sub = &ChangeSubscription{C: ch, ch: ch, filter: filter}
+	if h.closed {
+		close(ch)
+		return sub, nil, resumed, nil
+	}
+	h.subscribers[sub] = struct{}{}
+
+	if lastID == "" {
+		return sub, nil, true, nil
+	}
+	if !resumed || lastSeq > h.seq {
+		return sub, nil, false, nil
+	}
+
+	// O histórico cobre lastSeq se a notificação seguinte a ele ainda não foi descartada.
+	oldest := h.seq - uint64(len(h.backlog)) + 1
+	if lastSeq+1 < oldest {
+		resumed = false
+	}
+	for _, notification := range h.backlog {
+		if notification.seq > lastSeq && filter.Matches(notification.Event) {
+			missed = append(missed, notification)
+		}
+	}
+	return sub, missed, resumed, nil
+}
+
+// Unsubscribe remove o assinante e fecha seu canal.
+func (h *ChangeHub) Unsubscribe(sub *ChangeSubscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.remove(sub)
+}
+
+// Close desconecta todos os assinantes e recusa novos eventos. Deve ser chamado no encerramento do
+// servidor, para que as conexões de streaming não impeçam o graceful shutdown.
+func (h *ChangeHub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for sub := range h.subscribers {
+		h.remove(sub)
+	}
+}
+
+// remove desconecta o assinante. Deve ser chamado com o mutex adquirido.
+func (h *ChangeHub) remove(sub *ChangeSubscription) {
+	if _, ok := h.subscribers[sub]; ok {
+		delete(h.subscribers, sub)
+		close(sub.ch)
+	}
+}
+
+func parseChangeID(id string) (epoch int64, seq uint64, err error) {
+	rawEpoch, rawSeq, ok := strings.Cut(id, "-")
+	if !ok {
+		return 0, 0, models.ErrInvalidChangeID
+	}
+	epoch, err = strconv.ParseInt(rawEpoch, 10, 64)
+	if err != nil {
+		return 0, 0, models.ErrInvalidChangeID
+	}
+	seq, err = strconv.ParseUint(rawSeq, 10, 64)
+	if err != nil {
+		return 0, 0, models.ErrInvalidChangeID
+	}
+	return epoch, seq, nil
+}
@@ -0,0 +1,159 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/adapters/driven/memdb"
+	"github.com/danielrios/product-service-go/internal/application"
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+func testChangeHubConfig() application.ChangeHubConfig {
+	return application.ChangeHubConfig{
+		BacklogSize:      3,
+		SubscriberBuffer: 2,
+		RetryInterval:    time.Millisecond,
+	}
+}
+
+func eventFor(eventType models.EventType, id string) models.ProductEvent {
+	product, _ := models.NewProduct(id, "Product "+id, 10)
+	return models.NewProductEvent(eventType, product)
+}
+
+func receive(t *testing.T, sub *application.ChangeSubscription) application.ChangeNotification {
+	t.Helper()
+	select {
+	case notification, ok := <-sub.C:
+		if !ok {
+			t.Fatal("Subscription channel was closed")
+		}
+		return notification
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for notification")
+	}
+	return application.ChangeNotification{}
+}
+
+func TestChangeHub_Run(t *testing.T) {
+	t.Run("Forwards Repository Events", func(t *testing.T) {
+		repo := memdb.NewInMemoryProductRepository()
+		hub := application.NewChangeHub(repo, testChangeHubConfig())
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go hub.Run(ctx)
+		sub, _, _, _ := hub.Subscribe("", application.ChangeFilter{})
+
+		// Aguarda o hub começar a escutar o repositório antes de produzir alterações.
+		deadline := time.Now().Add(time.Second)
+		var notification application.ChangeNotification
+		for time.Now().Before(deadline) {
+			product, _ := models.NewProduct(time.Now().String(), "Product", 10)
+			_ = repo.Add(product, models.Mutation{})
+			select {
+			case notification = <-sub.C:
+			case <-time.After(10 * time.Millisecond):
+				continue
+			}
+			break
+		}
+
+		if notification.Event.Type != models.EventProductCreated {
+			t.Errorf("Expected a product.created notification, got %+v", notification)
+		}
+	})
+}
+
+func TestChangeHub_Subscribe(t *testing.T) {
+	t.Run("Applies Filters", func(t *testing.T) {
+		hub := application.NewChangeHub(memdb.NewInMemoryProductRepository(), testChangeHubConfig())
+		sub, _, _, _ := hub.Subscribe("", application.ChangeFilter{ProductIDs: []string{"2"}, Types: []models.EventType{models.EventProductUpdated}})
+
+		hub.Publish(eventFor(models.EventProductUpdated, "1"))
+		hub.Publish(eventFor(models.EventProductCreated, "2"))
+		hub.Publish(eventFor(models.EventProductUpdated, "2"))
+
+		notification := receive(t, sub)
+		if notification.Event.ProductID != "2" || notification.Event.Type != models.EventProductUpdated {
+			t.Errorf("Expected only the update of product 2, got %+v", notification.Event)
+		}
+	})
+
+	t.Run("Resumes From Last Event ID", func(t *testing.T) {
+		hub := application.NewChangeHub(memdb.NewInMemoryProductRepository(), testChangeHubConfig())
+		first, _, _, _ := hub.Subscribe("", application.ChangeFilter{})
+		hub.Publish(eventFor(models.EventProductCreated, "1"))
+		hub.Publish(eventFor(models.EventProductCreated, "2"))
+		lastSeen := receive(t, first)
+
+		_, missed, resumed, err := hub.Subscribe(lastSeen.ID, application.ChangeFilter{})
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !resumed {
+			t.Error("Expected subscription to resume")
+		}
+		if len(missed) != 1 || missed[0].Event.ProductID != "2" {
+			t.Errorf("Expected to replay only product 2, got %+v", missed)
+		}
+	})
+
+	t.Run("Reports Reset When Backlog Was Trimmed", func(t *testing.T) {
+		hub := application.NewChangeHub(memdb.NewInMemoryProductRepository(), testChangeHubConfig())
+		first, _, _, _ := hub.Subscribe("", application.ChangeFilter{})
+		hub.Publish(eventFor(models.EventProductCreated, "1"))
+		lastSeen := receive(t, first)
+		for _, id := range []string{"2", "3", "4", "5"} {
+			hub.Publish(eventFor(models.EventProductCreated, id))
+		}
+
+		_, missed, resumed, _ := hub.Subscribe(lastSeen.ID, application.ChangeFilter{})
+
+		if resumed {
+			t.Error("Expected resume to fail after backlog was trimmed")
+		}
+		if len(missed) != 3 {
+			t.Errorf("Expected the 3 notifications still in backlog, got %d", len(missed))
+		}
+	})
+
+	t.Run("Reports Reset For ID From Another Epoch", func(t *testing.T) {
+		hub := application.NewChangeHub(memdb.NewInMemoryProductRepository(), testChangeHubConfig())
+
+		_, _, resumed, err := hub.Subscribe("1-1", application.ChangeFilter{})
+
+		if err != nil || resumed {
+			t.Errorf("Expected reset without error, got resumed=%v err=%v", resumed, err)
+		}
+	})
+
+	t.Run("Rejects Malformed ID", func(t *testing.T) {
+		hub := application.NewChangeHub(memdb.NewInMemoryProductRepository(), testChangeHubConfig())
+
+		_, _, _, err := hub.Subscribe("abc", application.ChangeFilter{})
+
+		if err != models.ErrInvalidChangeID {
+			t.Errorf("Expected ErrInvalidChangeID, got %v", err)
+		}
+	})
+
+	t.Run("Disconnects Slow Subscribers", func(t *testing.T) {
+		hub := application.NewChangeHub(memdb.NewInMemoryProductRepository(), testChangeHubConfig())
+		sub, _, _, _ := hub.Subscribe("", application.ChangeFilter{})
+
+		for _, id := range []string{"1", "2", "3"} {
+			hub.Publish(eventFor(models.EventProductCreated, id))
+		}
+
+		received := 0
+		for range sub.C {
+			received++
+		}
+		if received != 2 {
+			t.Errorf("Expected the 2 buffered notifications before disconnect, got %d", received)
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package application
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+// maxIdempotencyKeyLength é o tamanho máximo aceito para uma chave de idempotência.
+const maxIdempotencyKeyLength = 255
+
+// IdempotencyConfig agrupa os parâmetros do controle de idempotência.
+type IdempotencyConfig struct {
+	TTL           time.Duration // Tempo durante o qual uma resposta concluída é reaproveitada.
+	LockTimeout   time.Duration // Tempo após o qual uma requisição em andamento é considerada abandonada.
+	PurgeInterval time.Duration // Intervalo entre remoções das chaves expiradas.
+}
+
+// DefaultIdempotencyConfig retorna a configuração padrão do controle de idempotência.
+func DefaultIdempotencyConfig() IdempotencyConfig {
+	return IdempotencyConfig{
+		TTL:           24 * time.Hour,
+		LockTimeout:   time.Minute,
+		PurgeInterval: 10 * time.Minute,
+	}
+}
+
+// IdempotencyService garante que requisições repetidas com a mesma chave de idempotência sejam
+// executadas uma única vez, devolvendo a resposta original nas repetições.
+// As chaves são isoladas por autor, para que clientes diferentes não colidam ao escolher a mesma chave.
+type IdempotencyService struct {
+	repo   ports.IdempotencyRepository
+	config IdempotencyConfig
+}
+
+// NewIdempotencyService cria e retorna uma nova instância de IdempotencyService.
+func NewIdempotencyService(repo ports.IdempotencyRepository, config IdempotencyConfig) *IdempotencyService {
+	return &IdempotencyService{
+		repo:   repo,
+		config: config,
+	}
+}
+
+// Begin reserva a chave para uma nova requisição com a impressão digital informada.
+// Se a chave já tiver uma resposta concluída para a mesma requisição, retorna o registro a ser repetido;
+// se retornar nil sem erro, o chamador deve executar a requisição e chamar Complete ou Release.
+func (s *IdempotencyService) Begin(ctx context.Context, key, fingerprint string) (*models.IdempotencyRecord, error) {
+	if key == "" || len(key) > maxIdempotencyKeyLength {
+		return nil, models.ErrInvalidIdempotencyKey
+	}
+
+	now := time.Now()
+	existing, err := s.repo.Reserve(&models.IdempotencyRecord{
+		Key:         scopedIdempotencyKey(ctx, key),
+		Fingerprint: fingerprint,
+		Status:      models.IdempotencyInProgress,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.config.LockTimeout),
+	})
+	if err != nil || existing == nil {
+		return nil, err
+	}
+
+	if existing.Fingerprint != fingerprint {
+		return nil, models.ErrIdempotencyKeyReused
+	}
+	if existing.Status != models.IdempotencyCompleted {
+		return nil, models.ErrIdempotencyRequestInFlight
+	}
+	return existing, nil
+}
+
+// Complete grava a resposta da requisição reservada por Begin, que passa a ser repetida até o fim do TTL.
+func (s *IdempotencyService) Complete(ctx context.Context, key, fingerprint string, status int, headers http.Header, body []byte) error {
+	now := time.Now()
+	return s.repo.Complete(&models.IdempotencyRecord{
+		Key:             scopedIdempotencyKey(ctx, key),
+		Fingerprint:     fingerprint,
+		Status:          models.IdempotencyCompleted,
+		ResponseStatus:  status,
+		ResponseHeaders: headers,
+		ResponseBody:    body,
+		ExpiresAt:       now.Add(s.config.TTL),
+	})
+}
+
+// Release libera a chave reservada por Begin sem gravar uma resposta, permitindo que o cliente repita a requisição.
+func (s *IdempotencyService) Release(ctx context.Context, key string) error {
+	return s.repo.Release(scopedIdempotencyKey(ctx, key))
+}
+
+// Run remove periodicamente as chaves expiradas até o contexto ser cancelado.
+func (s *IdempotencyService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.repo.DeleteExpired(time.Now()); err != nil {
+			log.Printf("Erro ao remover chaves de idempotência expiradas: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// scopedIdempotencyKey qualifica a chave com o autor da requisição.
+func scopedIdempotencyKey(ctx context.Context, key string) string {
+	return ActorFrom(ctx) + ":" + key
+}
@@ -0,0 +1,90 @@
+package application_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/adapters/driven/memdb"
+	"github.com/danielrios/product-service-go/internal/application"
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+func TestIdempotencyService(t *testing.T) {
+	ctx := application.WithActor(context.Background(), "alice")
+
+	t.Run("Replays Completed Response", func(t *testing.T) {
+		service := application.NewIdempotencyService(memdb.NewInMemoryIdempotencyRepository(), application.DefaultIdempotencyConfig())
+
+		stored, err := service.Begin(ctx, "key-1", "fp-1")
+		if err != nil || stored != nil {
+			t.Fatalf("Expected the key to be reserved, got %+v (%v)", stored, err)
+		}
+		headers := http.Header{"Content-Type": []string{"application/json"}}
+		if err := service.Complete(ctx, "key-1", "fp-1", http.StatusCreated, headers, []byte(`{"ID":"1"}`)); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		stored, err = service.Begin(ctx, "key-1", "fp-1")
+		if err != nil || stored == nil {
+			t.Fatalf("Expected the stored response, got %+v (%v)", stored, err)
+		}
+		if stored.ResponseStatus != http.StatusCreated || string(stored.ResponseBody) != `{"ID":"1"}` ||
+			stored.ResponseHeaders.Get("Content-Type") != "application/json" {
+			t.Errorf("Unexpected stored response: %+v", stored)
+		}
+	})
+
+	t.Run("Rejects Reuse And In-Flight Duplicates", func(t *testing.T) {
+		service := application.NewIdempotencyService(memdb.NewInMemoryIdempotencyRepository(), application.DefaultIdempotencyConfig())
+		_, _ = service.Begin(ctx, "key-1", "fp-1")
+
+		if _, err := service.Begin(ctx, "key-1", "fp-1"); !errors.Is(err, models.ErrIdempotencyRequestInFlight) {
+			t.Errorf("Expected ErrIdempotencyRequestInFlight, got %v", err)
+		}
+		if _, err := service.Begin(ctx, "key-1", "fp-2"); !errors.Is(err, models.ErrIdempotencyKeyReused) {
+			t.Errorf("Expected ErrIdempotencyKeyReused, got %v", err)
+		}
+	})
+
+	t.Run("Keys Are Scoped By Actor", func(t *testing.T) {
+		service := application.NewIdempotencyService(memdb.NewInMemoryIdempotencyRepository(), application.DefaultIdempotencyConfig())
+		_, _ = service.Begin(ctx, "key-1", "fp-1")
+
+		other := application.WithActor(context.Background(), "bob")
+		if stored, err := service.Begin(other, "key-1", "fp-2"); err != nil || stored != nil {
+			t.Errorf("Expected another actor to reserve the same key, got %+v (%v)", stored, err)
+		}
+	})
+
+	t.Run("Released And Abandoned Keys Can Be Reserved Again", func(t *testing.T) {
+		config := application.DefaultIdempotencyConfig()
+		config.LockTimeout = 10 * time.Millisecond
+		service := application.NewIdempotencyService(memdb.NewInMemoryIdempotencyRepository(), config)
+
+		_, _ = service.Begin(ctx, "released", "fp-1")
+		_ = service.Release(ctx, "released")
+		if stored, err := service.Begin(ctx, "released", "fp-2"); err != nil || stored != nil {
+			t.Errorf("Expected released key to be reserved again, got %+v (%v)", stored, err)
+		}
+
+		_, _ = service.Begin(ctx, "abandoned", "fp-1")
+		time.Sleep(20 * time.Millisecond)
+		if stored, err := service.Begin(ctx, "abandoned", "fp-1"); err != nil || stored != nil {
+			t.Errorf("Expected abandoned key to be taken over, got %+v (%v)", stored, err)
+		}
+	})
+
+	t.Run("Invalid Key", func(t *testing.T) {
+		service := application.NewIdempotencyService(memdb.NewInMemoryIdempotencyRepository(), application.DefaultIdempotencyConfig())
+		longKey := string(make([]byte, 256))
+
+		for _, key := range []string{"", longKey} {
+			if _, err := service.Begin(ctx, key, "fp-1"); !errors.Is(err, models.ErrInvalidIdempotencyKey) {
+				t.Errorf("Expected ErrInvalidIdempotencyKey, got %v", err)
+			}
+		}
+	})
+}
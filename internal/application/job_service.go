@@ -0,0 +1,377 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+// JobConfig agrupa os parâmetros da execução dos jobs assíncronos.
+type JobConfig struct {
+	Workers           int           // Quantidade máxima de jobs executados simultaneamente por esta instância.
+	PollInterval      time.Duration // Intervalo entre consultas à fila quando ela está vazia.
+	Lease             time.Duration // Tempo após o qual um job sem heartbeat volta a ser reservado.
+	HeartbeatInterval time.Duration // Intervalo entre renovações do lease e gravações do progresso.
+	MaxAttempts       int           // Reservas após as quais um job que nunca termina é marcado como falho.
+	Retention         time.Duration // Tempo durante o qual os jobs finalizados podem ser consultados.
+	PurgeInterval     time.Duration // Intervalo entre remoções dos jobs finalizados antigos.
+}
+
+// DefaultJobConfig retorna a configuração padrão dos jobs assíncronos.
+func DefaultJobConfig() JobConfig {
+	return JobConfig{
+		Workers:           4,
+		PollInterval:      time.Second,
+		Lease:             time.Minute,
+		HeartbeatInterval: 10 * time.Second,
+		MaxAttempts:       3,
+		Retention:         7 * 24 * time.Hour,
+		PurgeInterval:     time.Hour,
+	}
+}
+
+// JobFunc executa um job de um determinado tipo e retorna o resultado, que é gravado em JSON.
+// O contexto é cancelado quando o cancelamento é solicitado ou quando o serviço é encerrado, e traz
+// o autor e a requisição que submeteram o job. progress atualiza o progresso exibido aos clientes.
+type JobFunc func(ctx context.Context, job *models.Job, progress func(models.JobProgress)) (result any, err error)
+
+// JobService mantém a fila de jobs assíncronos e os executa em um pool limitado de workers.
+// Um job interrompido pelo encerramento volta para a fila e é executado novamente após o reinício;
+// por isso as funções dos jobs não devem gravar nada antes do ponto em que deixam de observar o contexto.
+type JobService struct {
+	repo        ports.JobRepository
+	config      JobConfig
+	handlers    map[models.JobType]JobFunc
+	permissions map[models.JobType]models.Permission
+	authz       *Authorizer
+
+	wake     chan struct{}
+	stopping chan struct{}
+	stopOnce sync.Once
+	workers  sync.WaitGroup
+
+	mu      sync.Mutex
+	running map[string]context.CancelCauseFunc
+}
+
+// JobServiceOption configura um JobService.
+type JobServiceOption func(*JobService)
+
+// WithJobAuthorizer restringe a consulta e o cancelamento de jobs: o principal do contexto precisa da
+// permissão registrada para o tipo do job e só enxerga os jobs que ele mesmo submeteu, exceto com o
+// escopo admin. Os jobs de outros autores são tratados como inexistentes.
+func WithJobAuthorizer(authz *Authorizer) JobServiceOption {
+	return func(s *JobService) {
+		s.authz = authz
+	}
+}
+
+// NewJobService cria e retorna uma nova instância de JobService.
+func NewJobService(repo ports.JobRepository, config JobConfig, opts ...JobServiceOption) *JobService {
+	s := &JobService{
+		repo:        repo,
+		config:      config,
+		handlers:    make(map[models.JobType]JobFunc),
+		permissions: make(map[models.JobType]models.Permission),
+		wake:        make(chan struct{}, 1),
+		stopping:    make(chan struct{}),
+		running:     make(map[string]context.CancelCauseFunc),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Register associa a função que executa os jobs do tipo informado e a permissão exigida para consultá-los
+// e cancelá-los. Deve ser chamado antes de Run.
+func (s *JobService) Register(jobType models.JobType, permission models.Permission, fn JobFunc) {
+	s.handlers[jobType] = fn
+	s.permissions[jobType] = permission
+}
+
+// Submit coloca um job na fila, identificando o autor e a requisição pelo contexto.
+func (s *JobService) Submit(ctx context.Context, jobType models.JobType, parameters any, input []byte) (*models.Job, error) {
+	if _, ok := s.handlers[jobType]; !ok {
+		return nil, models.ErrUnknownJobType
+	}
+	rawParameters, err := json.Marshal(parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	job := models.NewJob(jobType, rawParameters, input, ActorFrom(ctx), RequestIDFrom(ctx))
+	if err := s.repo.AddJob(job); err != nil {
+		return nil, err
+	}
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return job, nil
+}
+
+// GetJob busca um job pelo ID.
+func (s *JobService) GetJob(ctx context.Context, id string) (*models.Job, error) {
+	return s.visibleJob(ctx, id)
+}
+
+// CancelJob cancela um job na fila ou solicita o cancelamento de um job em execução. Se o job estiver em
+// execução nesta instância, seu contexto é cancelado imediatamente; nas demais, no próximo heartbeat.
+func (s *JobService) CancelJob(ctx context.Context, id string) (*models.Job, error) {
+	if _, err := s.visibleJob(ctx, id); err != nil {
+		return nil, err
+	}
+	job, err := s.repo.RequestJobCancellation(id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	cancel, ok := s.running[id]
+	s.mu.Unlock()
+	if ok {
+		cancel(models.ErrJobCancelled)
+	}
+	return job, nil
+}
+
+// visibleJob busca o job, verificando, quando o serviço tem um Authorizer, se o principal do contexto
+// pode acessá-lo.
+func (s *JobService) visibleJob(ctx context.Context, id string) (*models.Job, error) {
+	job, err := s.repo.GetJob(id)
+	if err != nil {
+		return nil, err
+	}
+	if s.authz == nil {
+		return job, nil
+	}
+	if err := s.authz.Authorize(ctx, s.permissions[job.Type]); err != nil {
+		return nil, err
+	}
+	if job.Actor != ActorFrom(ctx) && !PrincipalFrom(ctx).HasScope(models.ScopeAdmin) {
+		return nil, models.ErrJobNotFound
+	}
+	return job, nil
+}
+
+// Run executa os workers e a limpeza dos jobs antigos até que o contexto seja cancelado ou Shutdown
+// seja chamado. O cancelamento do contexto interrompe os jobs em execução, que voltam para a fila.
+func (s *JobService) Run(ctx context.Context) {
+	s.workers.Add(s.config.Workers)
+	for range s.config.Workers {
+		go func() {
+			defer s.workers.Done()
+			s.work(ctx)
+		}()
+	}
+
+	ticker := time.NewTicker(s.config.PurgeInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.repo.DeleteFinishedJobs(time.Now().Add(-s.config.Retention)); err != nil {
+			log.Printf("Erro ao remover jobs finalizados: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-s.stopping:
+		case <-ticker.C:
+			continue
+		}
+		s.workers.Wait()
+		return
+	}
+}
+
+// Shutdown para de reservar novos jobs e espera os jobs em execução terminarem. Se o contexto expirar
+// antes, os jobs restantes são interrompidos e devolvidos à fila, e o erro do contexto é retornado.
+func (s *JobService) Shutdown(ctx context.Context) error {
+	s.stopOnce.Do(func() { close(s.stopping) })
+
+	done := make(chan struct{})
+	go func() {
+		s.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	s.mu.Lock()
+	for _, cancel := range s.running {
+		cancel(models.ErrJobInterrupted)
+	}
+	s.mu.Unlock()
+	<-done
+	return ctx.Err()
+}
+
+// work reserva e executa jobs, um por vez, até o encerramento.
+func (s *JobService) work(ctx context.Context) {
+	ticker := time.NewTicker(s.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopping:
+			return
+		default:
+		}
+
+		job, err := s.repo.ClaimNextJob(s.config.Lease)
+		if err != nil {
+			log.Printf("Erro ao reservar job: %v", err)
+		}
+		if job != nil {
+			s.execute(ctx, job)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopping:
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// execute roda o job reservado e grava o resultado, mantendo o lease renovado enquanto ele executa.
+func (s *JobService) execute(parent context.Context, job *models.Job) {
+	fn, ok := s.handlers[job.Type]
+	if !ok {
+		s.finish(job, models.JobFailed, nil, models.ErrUnknownJobType)
+		return
+	}
+	if job.Attempts > s.config.MaxAttempts {
+		s.finish(job, models.JobFailed, nil, fmt.Errorf("job abandoned after %d attempts", s.config.MaxAttempts))
+		return
+	}
+
+	ctx, cancel := context.WithCancelCause(WithRequestID(WithActor(parent, job.Actor), job.RequestID))
+	defer cancel(nil)
+	s.mu.Lock()
+	s.running[job.ID] = cancel
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.running, job.ID)
+		s.mu.Unlock()
+	}()
+	if job.CancelRequested {
+		cancel(models.ErrJobCancelled)
+	}
+
+	var (
+		progressMu sync.Mutex
+		progress   = job.Progress
+	)
+	reportProgress := func(p models.JobProgress) {
+		progressMu.Lock()
+		progress = p
+		progressMu.Unlock()
+	}
+	heartbeatDone := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(s.config.HeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-heartbeatDone:
+				return
+			case <-ticker.C:
+			}
+			progressMu.Lock()
+			current := progress
+			progressMu.Unlock()
+			cancelRequested, err := s.repo.HeartbeatJob(job.ID, current, s.config.Lease)
+			if err != nil {
+				log.Printf("Erro ao renovar o job %s: %v", job.ID, err)
+			} else if cancelRequested {
+				cancel(models.ErrJobCancelled)
+			}
+		}
+	}()
+
+	result, err := s.run(ctx, fn, job, reportProgress)
+	close(heartbeatDone)
+	progressMu.Lock()
+	job.Progress = progress
+	progressMu.Unlock()
+
+	cause := context.Cause(ctx)
+	switch {
+	case err == nil:
+		s.finish(job, models.JobSucceeded, result, nil)
+	case errors.Is(cause, models.ErrJobCancelled):
+		s.finish(job, models.JobCancelled, result, nil)
+	case ctx.Err() != nil:
+		s.requeue(job)
+	default:
+		s.finish(job, models.JobFailed, result, err)
+	}
+}
+
+// run chama a função do job, convertendo um panic em falha para não derrubar o worker.
+func (s *JobService) run(ctx context.Context, fn JobFunc, job *models.Job, progress func(models.JobProgress)) (result any, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			log.Printf("Panic no job %s: %v", job.ID, recovered)
+			err = fmt.Errorf("job panicked: %v", recovered)
+		}
+	}()
+	return fn(ctx, job, progress)
+}
+
+// finish grava o estado final do job.
+func (s *JobService) finish(job *models.Job, status models.JobStatus, result any, jobErr error) {
+	now := time.Now()
+	job.Status = status
+	job.FinishedAt = &now
+	job.LeaseExpiresAt = nil
+	job.Result = nil
+	job.Error = ""
+	if jobErr != nil {
+		job.Error = jobErr.Error()
+	}
+	if result != nil {
+		data, err := json.Marshal(result)
+		if err != nil {
+			log.Printf("Erro ao codificar o resultado do job %s: %v", job.ID, err)
+		} else {
+			job.Result = data
+		}
+	}
+
+	if err := s.repo.UpdateJob(job); err != nil {
+		log.Printf("ERRO: resultado do job %s não gravado: %v", job.ID, err)
+	}
+}
+
+// requeue devolve à fila um job interrompido pelo encerramento, sem contar a reserva como tentativa.
+func (s *JobService) requeue(job *models.Job) {
+	job.Status = models.JobQueued
+	job.Attempts--
+	job.LeaseExpiresAt = nil
+	job.Progress = models.JobProgress{}
+	if err := s.repo.UpdateJob(job); err != nil {
+		log.Printf("ERRO: job %s interrompido não devolvido à fila: %v", job.ID, err)
+		return
+	}
+	log.Printf("Job %s interrompido e devolvido à fila.", job.ID)
+}
@@ -0,0 +1,208 @@
+package application_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/adapters/driven/memdb"
+	"github.com/danielrios/product-service-go/internal/application"
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+const testJobType models.JobType = "test"
+
+func newTestJobService(repo *memdb.InMemoryJobRepository) *application.JobService {
+	config := application.DefaultJobConfig()
+	config.Workers = 2
+	config.PollInterval = 10 * time.Millisecond
+	config.HeartbeatInterval = 10 * time.Millisecond
+	return application.NewJobService(repo, config)
+}
+
+// waitForJob espera o job atingir a situação informada.
+func waitForJob(t *testing.T, service *application.JobService, id string, status models.JobStatus) *models.Job {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		job, err := service.GetJob(context.Background(), id)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if job.Status == status {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected job to be %s, got %+v", status, job)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestJobService(t *testing.T) {
+	t.Run("Runs Jobs And Records Results", func(t *testing.T) {
+		service := newTestJobService(memdb.NewInMemoryJobRepository())
+		service.Register(testJobType, models.PermissionProductsImport, func(ctx context.Context, job *models.Job, progress func(models.JobProgress)) (any, error) {
+			progress(models.JobProgress{Processed: 1, Total: 1})
+			if application.ActorFrom(ctx) != "alice" {
+				return nil, errors.New("actor not propagated")
+			}
+			return map[string]string{"input": string(job.Input)}, nil
+		})
+		ctx, stop := context.WithCancel(context.Background())
+		defer stop()
+		go service.Run(ctx)
+
+		job, err := service.Submit(application.WithActor(context.Background(), "alice"), testJobType, nil, []byte("data"))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		finished := waitForJob(t, service, job.ID, models.JobSucceeded)
+		if string(finished.Result) != `{"input":"data"}` || finished.Progress.Processed != 1 || finished.FinishedAt == nil {
+			t.Errorf("Unexpected finished job: %+v", finished)
+		}
+	})
+
+	t.Run("Failures Keep The Error", func(t *testing.T) {
+		service := newTestJobService(memdb.NewInMemoryJobRepository())
+		service.Register(testJobType, models.PermissionProductsImport, func(context.Context, *models.Job, func(models.JobProgress)) (any, error) {
+			return nil, errors.New("boom")
+		})
+		ctx, stop := context.WithCancel(context.Background())
+		defer stop()
+		go service.Run(ctx)
+
+		job, _ := service.Submit(context.Background(), testJobType, nil, nil)
+
+		if failed := waitForJob(t, service, job.ID, models.JobFailed); failed.Error != "boom" {
+			t.Errorf("Expected error boom, got %q", failed.Error)
+		}
+	})
+
+	t.Run("Cancels Running Jobs", func(t *testing.T) {
+		service := newTestJobService(memdb.NewInMemoryJobRepository())
+		started := make(chan struct{})
+		service.Register(testJobType, models.PermissionProductsImport, func(ctx context.Context, _ *models.Job, _ func(models.JobProgress)) (any, error) {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+		ctx, stop := context.WithCancel(context.Background())
+		defer stop()
+		go service.Run(ctx)
+
+		job, _ := service.Submit(context.Background(), testJobType, nil, nil)
+		<-started
+		if _, err := service.CancelJob(context.Background(), job.ID); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		waitForJob(t, service, job.ID, models.JobCancelled)
+		if _, err := service.CancelJob(context.Background(), job.ID); !errors.Is(err, models.ErrJobAlreadyFinished) {
+			t.Errorf("Expected ErrJobAlreadyFinished, got %v", err)
+		}
+	})
+
+	t.Run("Shutdown Drains Or Requeues Running Jobs", func(t *testing.T) {
+		repo := memdb.NewInMemoryJobRepository()
+		service := newTestJobService(repo)
+		started := make(chan struct{})
+		service.Register(testJobType, models.PermissionProductsImport, func(ctx context.Context, _ *models.Job, _ func(models.JobProgress)) (any, error) {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+		done := make(chan struct{})
+		go func() {
+			service.Run(context.Background())
+			close(done)
+		}()
+
+		job, _ := service.Submit(context.Background(), testJobType, nil, nil)
+		<-started
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if err := service.Shutdown(shutdownCtx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected the drain deadline to be exceeded, got %v", err)
+		}
+		<-done
+
+		requeued, _ := repo.GetJob(job.ID)
+		if requeued.Status != models.JobQueued || requeued.Attempts != 0 {
+			t.Errorf("Expected the job back in the queue without spending an attempt, got %+v", requeued)
+		}
+	})
+
+	t.Run("Rejects Unknown Types", func(t *testing.T) {
+		service := newTestJobService(memdb.NewInMemoryJobRepository())
+
+		if _, err := service.Submit(context.Background(), "unknown", nil, nil); !errors.Is(err, models.ErrUnknownJobType) {
+			t.Errorf("Expected ErrUnknownJobType, got %v", err)
+		}
+	})
+}
+
+func TestProductService_SubmitImportProducts(t *testing.T) {
+	repo := memdb.NewInMemoryProductRepository()
+	jobs := newTestJobService(memdb.NewInMemoryJobRepository())
+	service := application.NewProductService(repo, application.WithImports(repo), application.WithJobs(jobs))
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+	go jobs.Run(ctx)
+
+	job, err := service.SubmitImportProducts(context.Background(), strings.NewReader("id,name,price\n1,Product 1,10\n"),
+		application.DefaultProductImportOptions())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	finished := waitForJob(t, jobs, job.ID, models.JobSucceeded)
+	var report models.ImportReport
+	if err := json.Unmarshal(finished.Result, &report); err != nil || !report.Applied || report.Created != 1 {
+		t.Errorf("Unexpected report: %+v (%v)", report, err)
+	}
+	if _, err := repo.GetByID("1"); err != nil {
+		t.Errorf("Expected product 1 to be imported, got %v", err)
+	}
+}
+
+func TestJobService_Authorization(t *testing.T) {
+	authorizer, err := application.NewAuthorizer(models.DefaultRolePermissions(), models.DefaultProductFieldPolicy())
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := application.DefaultJobConfig()
+	service := application.NewJobService(memdb.NewInMemoryJobRepository(), config, application.WithJobAuthorizer(authorizer))
+	service.Register(testJobType, models.PermissionProductsImport, func(context.Context, *models.Job, func(models.JobProgress)) (any, error) {
+		return nil, nil
+	})
+	as := func(subject string, scopes []string, roles ...string) context.Context {
+		ctx := application.WithPrincipal(context.Background(), &models.Principal{Subject: subject, Scopes: scopes, Roles: roles})
+		return application.WithActor(ctx, subject)
+	}
+	owner := as("alice", nil, models.RoleEditor)
+	job, err := service.Submit(owner, testJobType, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := service.GetJob(owner, job.ID); err != nil {
+		t.Errorf("Expected the submitter to see the job, got %v", err)
+	}
+	if _, err := service.GetJob(as("bob", nil, models.RoleViewer), job.ID); !errors.Is(err, models.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden without products:import, got %v", err)
+	}
+	if _, err := service.CancelJob(as("bob", nil, models.RoleEditor), job.ID); !errors.Is(err, models.ErrJobNotFound) {
+		t.Errorf("Expected another editor not to find the job, got %v", err)
+	}
+	if _, err := service.GetJob(as("carol", []string{models.ScopeAdmin}, models.RoleAdmin), job.ID); err != nil {
+		t.Errorf("Expected an admin to see every job, got %v", err)
+	}
+	if cancelled, err := service.CancelJob(owner, job.ID); err != nil || cancelled.Status != models.JobCancelled {
+		t.Errorf("Expected the submitter to cancel the queued job, got %+v %v", cancelled, err)
+	}
+}
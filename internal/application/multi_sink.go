@@ -0,0 +1,25 @@
+package application
+
+import (
+	"context"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+// MultiSink entrega cada evento a vários destinos, em ordem.
+// Se algum destino falhar, o evento inteiro é considerado não entregue e será reenviado a todos,
+// por isso os destinos devem tolerar eventos repetidos.
+type MultiSink []ports.EventSink
+
+var _ ports.EventSink = MultiSink(nil)
+
+// Publish entrega o evento a cada destino, interrompendo no primeiro erro.
+func (m MultiSink) Publish(ctx context.Context, event models.ProductEvent) error {
+	for _, sink := range m {
+		if err := sink.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package application
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+// OutboxRelayConfig agrupa os parâmetros de funcionamento do OutboxRelay.
+type OutboxRelayConfig struct {
+	PollInterval time.Duration // Intervalo entre consultas quando não há mensagens pendentes.
+	BatchSize    int           // Quantidade máxima de mensagens processadas por consulta.
+	BaseBackoff  time.Duration // Espera após a primeira falha de entrega; dobra a cada nova falha.
+	MaxBackoff   time.Duration // Limite superior da espera entre tentativas.
+}
+
+// DefaultOutboxRelayConfig retorna a configuração padrão do relay.
+func DefaultOutboxRelayConfig() OutboxRelayConfig {
+	return OutboxRelayConfig{
+		PollInterval: time.Second,
+		BatchSize:    100,
+		BaseBackoff:  time.Second,
+		MaxBackoff:   5 * time.Minute,
+	}
+}
+
+// OutboxRelay lê periodicamente as mensagens pendentes do outbox e as entrega ao EventSink.
+type OutboxRelay struct {
+	outbox ports.OutboxRepository
+	sink   ports.EventSink
+	config OutboxRelayConfig
+}
+
+// NewOutboxRelay cria e retorna uma nova instância de OutboxRelay.
+func NewOutboxRelay(outbox ports.OutboxRepository, sink ports.EventSink, config OutboxRelayConfig) *OutboxRelay {
+	return &OutboxRelay{
+		outbox: outbox,
+		sink:   sink,
+		config: config,
+	}
+}
+
+// Run executa o relay até que o contexto seja cancelado.
+func (r *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		processed, err := r.RelayOnce(ctx)
+		if err != nil {
+			log.Printf("Erro ao processar o outbox: %v", err)
+		}
+
+		// Um lote cheio indica que pode haver mais mensagens: processa imediatamente.
+		if err == nil && processed == r.config.BatchSize {
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayOnce processa um único lote de mensagens pendentes.
+func (r *OutboxRelay) RelayOnce(ctx context.Context) (int, error) {
+	return r.outbox.ProcessPending(r.config.BatchSize, func(msg *models.OutboxMessage) error {
+		return r.sink.Publish(ctx, msg.Event)
+	}, r.backoff)
+}
+
+// backoff calcula a espera até a próxima tentativa de entrega.
+func (r *OutboxRelay) backoff(attempts int) time.Duration {
+	return exponentialBackoff(r.config.BaseBackoff, r.config.MaxBackoff, attempts)
+}
+
+// exponentialBackoff retorna base dobrada a cada tentativa após a primeira, limitada a maximum.
+func exponentialBackoff(base, maximum time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < maximum; i++ {
+		delay *= 2
+	}
+	return min(delay, maximum)
+}
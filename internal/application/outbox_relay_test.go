@@ -0,0 +1,97 @@
+package application_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/adapters/driven/memdb"
+	"github.com/danielrios/product-service-go/internal/application"
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+// recordingSink armazena os eventos recebidos e pode simular falhas de entrega.
+type recordingSink struct {
+	mu       sync.Mutex
+	events   []models.ProductEvent
+	failures int
+}
+
+func (s *recordingSink) Publish(_ context.Context, event models.ProductEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("sink unavailable")
+	}
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *recordingSink) received() []models.ProductEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]models.ProductEvent(nil), s.events...)
+}
+
+func testRelayConfig() application.OutboxRelayConfig {
+	return application.OutboxRelayConfig{
+		PollInterval: 5 * time.Millisecond,
+		BatchSize:    10,
+		BaseBackoff:  time.Millisecond,
+		MaxBackoff:   10 * time.Millisecond,
+	}
+}
+
+func TestOutboxRelay_RelayOnce(t *testing.T) {
+	t.Run("Delivers Pending Events", func(t *testing.T) {
+		repo := memdb.NewInMemoryProductRepository()
+		service := application.NewProductService(repo)
+		_, _ = service.CreateProduct(context.Background(), &models.Product{ID: "1", Name: "Product 1", Price: 10})
+		sink := &recordingSink{}
+		relay := application.NewOutboxRelay(repo, sink, testRelayConfig())
+
+		processed, err := relay.RelayOnce(context.Background())
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if processed != 1 {
+			t.Errorf("Expected 1 processed message, got %d", processed)
+		}
+		events := sink.received()
+		if len(events) != 1 || events[0].Type != models.EventProductCreated || events[0].ProductID != "1" {
+			t.Errorf("Unexpected delivered events: %+v", events)
+		}
+	})
+}
+
+func TestOutboxRelay_Run(t *testing.T) {
+	t.Run("Retries Failed Deliveries With Backoff", func(t *testing.T) {
+		repo := memdb.NewInMemoryProductRepository()
+		service := application.NewProductService(repo)
+		_, _ = service.CreateProduct(context.Background(), &models.Product{ID: "1", Name: "Product 1", Price: 10})
+		sink := &recordingSink{failures: 3}
+		relay := application.NewOutboxRelay(repo, sink, testRelayConfig())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			relay.Run(ctx)
+			close(done)
+		}()
+
+		deadline := time.Now().Add(2 * time.Second)
+		for len(sink.received()) == 0 && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+		cancel()
+		<-done
+
+		if events := sink.received(); len(events) != 1 {
+			t.Errorf("Expected the event to be delivered exactly once after retries, got %d deliveries", len(events))
+		}
+	})
+}
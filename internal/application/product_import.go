@@ -0,0 +1,351 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+// ImportProductsJobType identifica os jobs de importação de produtos em CSV.
+const ImportProductsJobType models.JobType = "products.import"
+
+// importProgressInterval é a quantidade de linhas lidas entre duas atualizações do progresso da importação.
+const importProgressInterval = 1000
+
+// maxImportPrice é o maior preço aceito pela coluna NUMERIC(10, 2) de products.
+const maxImportPrice = 99999999.99
+
+// ImportColumns indica o nome, no cabeçalho do CSV, da coluna de cada campo do produto.
+// A comparação ignora maiúsculas, minúsculas e espaços nas bordas.
+type ImportColumns struct {
+	ID    string
+	Name  string
+	Price string
+}
+
+// DefaultImportColumns são as colunas esperadas quando nenhum mapeamento é informado,
+// as mesmas da exportação em CSV.
+var DefaultImportColumns = ImportColumns{ID: "id", Name: "name", Price: "price"}
+
+// ProductImportOptions configura a leitura e a gravação de uma importação de produtos em CSV.
+type ProductImportOptions struct {
+	Mode         models.ImportMode
+	DryRun       bool // Apenas valida e relata o que seria gravado.
+	Delimiter    rune // Separador de colunas; vírgula quando zero.
+	DecimalComma bool // Preços no formato brasileiro, como 1.234,56.
+	Columns      ImportColumns
+}
+
+// DefaultProductImportOptions retorna as opções padrão: criação, separador vírgula, ponto decimal
+// e as colunas de DefaultImportColumns.
+func DefaultProductImportOptions() ProductImportOptions {
+	return ProductImportOptions{
+		Mode:      models.ImportModeCreate,
+		Delimiter: ',',
+		Columns:   DefaultImportColumns,
+	}
+}
+
+// validate verifica se as opções são coerentes, aplicando os padrões aos campos vazios.
+func (o *ProductImportOptions) validate() error {
+	if o.Delimiter == 0 {
+		o.Delimiter = ','
+	}
+	if o.Columns.ID == "" {
+		o.Columns.ID = DefaultImportColumns.ID
+	}
+	if o.Columns.Name == "" {
+		o.Columns.Name = DefaultImportColumns.Name
+	}
+	if o.Columns.Price == "" {
+		o.Columns.Price = DefaultImportColumns.Price
+	}
+
+	invalidDelimiter := o.Delimiter == '"' || o.Delimiter == '\r' || o.Delimiter == '\n' ||
+		o.Delimiter == utf8.RuneError || (o.DecimalComma && o.Delimiter == ',')
+	id, name, price := normalizeColumn(o.Columns.ID), normalizeColumn(o.Columns.Name), normalizeColumn(o.Columns.Price)
+	if !o.Mode.IsValid() || invalidDelimiter || id == name || id == price || name == price {
+		return models.ErrInvalidImportOptions
+	}
+	return nil
+}
+
+func normalizeColumn(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// importRow é um produto lido do arquivo, com a linha de origem para o relatório.
+type importRow struct {
+	line    int
+	product *models.Product
+}
+
+// ImportProducts lida com a lógica de negócio da importação de produtos em CSV. Cada linha é validada
+// com as mesmas regras de CreateProduct; IDs repetidos no arquivo e, no modo create, IDs já cadastrados
+// também são erros. A importação é tudo ou nada: havendo qualquer erro, nada é gravado e o relatório
+// lista os problemas por linha. Erros no formato do arquivo ou nas opções são devolvidos como erro.
+func (s *ProductService) ImportProducts(ctx context.Context, data io.Reader, opts ProductImportOptions) (*models.ImportReport, error) {
+	if err := s.authorize(ctx, models.PermissionProductsImport); err != nil {
+		return nil, err
+	}
+	return s.importProducts(ctx, data, opts, s.writableFields(ctx, models.PermissionProductsImport), func(models.JobProgress) {})
+}
+
+// SubmitImportProducts valida as opções e coloca a importação na fila de jobs, gravando o arquivo com o job.
+// O job termina com o relatório da importação como resultado e falha se alguma linha for inválida. A
+// permissão é verificada aqui, pois o job roda depois, sem o principal que o submeteu; os campos que ele
+// pode alterar são gravados com o job.
+func (s *ProductService) SubmitImportProducts(ctx context.Context, data io.Reader, opts ProductImportOptions) (*models.Job, error) {
+	if err := s.authorize(ctx, models.PermissionProductsImport); err != nil {
+		return nil, err
+	}
+	if s.jobs == nil {
+		return nil, models.ErrJobsNotConfigured
+	}
+	if s.imports == nil {
+		return nil, models.ErrImportNotConfigured
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	input, err := io.ReadAll(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", models.ErrInvalidImportFile, err)
+	}
+	parameters := importJobParameters{
+		ProductImportOptions: opts,
+		WritableFields:       s.writableFields(ctx, models.PermissionProductsImport),
+	}
+	return s.jobs.Submit(ctx, ImportProductsJobType, parameters, input)
+}
+
+// importJobParameters são os parâmetros gravados com um job de importação: as opções e os campos que o
+// principal que submeteu o job pode alterar.
+type importJobParameters struct {
+	ProductImportOptions
+	WritableFields writableFields
+}
+
+// runImportJob executa um job de importação submetido por SubmitImportProducts.
+func (s *ProductService) runImportJob(ctx context.Context, job *models.Job, progress func(models.JobProgress)) (any, error) {
+	var parameters importJobParameters
+	if err := json.Unmarshal(job.Parameters, &parameters); err != nil {
+		return nil, err
+	}
+
+	report, err := s.importProducts(ctx, bytes.NewReader(job.Input), parameters.ProductImportOptions, parameters.WritableFields, progress)
+	if err != nil {
+		return nil, err
+	}
+	if report.ErrorCount > 0 && !report.DryRun {
+		return report, fmt.Errorf("import rejected: %d invalid rows", report.ErrorCount)
+	}
+	return report, nil
+}
+
+// importProducts implementa a importação, informando o progresso a cada importProgressInterval linhas.
+// O contexto é verificado durante a leitura e antes da gravação, que, uma vez iniciada, vai até o fim.
+// Se alguma linha mudar um campo fora de writable (nos produtos novos, preencher um campo conta como
+// alterá-lo), a importação inteira é recusada com um ForbiddenFieldsError, mesmo na simulação. As alterações são verificadas sobre os produtos lidos antes
+// da gravação; se algum deles mudar até lá, nada é gravado e o resultado é ErrProductModified.
+func (s *ProductService) importProducts(ctx context.Context, data io.Reader, opts ProductImportOptions, writable writableFields, progress func(models.JobProgress)) (*models.ImportReport, error) {
+	if s.imports == nil {
+		return nil, models.ErrImportNotConfigured
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	report := models.NewImportReport(opts.Mode, opts.DryRun)
+	rows, err := parseImportCSV(ctx, data, opts, report, progress)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(rows))
+	for i, row := range rows {
+		ids[i] = row.product.ID
+	}
+	existing, err := s.imports.ExistingProducts(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	products := make([]*models.Product, 0, len(rows))
+	var changed []models.ProductField
+	for _, row := range rows {
+		if current, ok := existing[row.product.ID]; ok {
+			if opts.Mode == models.ImportModeCreate {
+				report.AddError(row.line, "ID", models.ErrProductAlreadyExists.Error())
+				continue
+			}
+			changed = append(changed, models.ChangedProductFields(current, row.product)...)
+			report.Updated++
+		} else {
+			changed = append(changed, models.ChangedProductFields(&models.Product{}, row.product)...)
+			report.Created++
+		}
+		products = append(products, row.product)
+	}
+	report.ValidRows = len(products)
+	if writable.Restricted {
+		if err := models.NewForbiddenFieldsError(models.FieldAccessWrite, changed, writable.Fields); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.DryRun || report.ErrorCount > 0 || len(products) == 0 {
+		return report, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	results, err := s.imports.ImportProducts(products, existing, opts.Mode, MutationFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+	report.Applied = true
+	report.Created, report.Updated = 0, 0
+	for _, result := range results {
+		if result.Before == nil {
+			report.Created++
+		} else {
+			report.Updated++
+		}
+	}
+	return report, nil
+}
+
+// parseImportCSV lê o arquivo e valida cada linha, registrando no relatório os erros encontrados.
+// Apenas as linhas válidas são devolvidas.
+func parseImportCSV(ctx context.Context, data io.Reader, opts ProductImportOptions, report *models.ImportReport, progress func(models.JobProgress)) ([]importRow, error) {
+	reader := csv.NewReader(data)
+	reader.Comma = opts.Delimiter
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: missing header row", models.ErrInvalidImportFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", models.ErrInvalidImportFile, err)
+	}
+	idColumn, nameColumn, priceColumn, err := importColumnIndexes(header, opts.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		rows    []importRow
+		seenIDs = make(map[string]int)
+	)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			progress(models.JobProgress{Processed: report.TotalRows, Total: report.TotalRows})
+			break
+		}
+		if err != nil {
+			// Aspas malformadas impedem saber onde termina a linha; o restante do arquivo não é confiável.
+			return nil, fmt.Errorf("%w: %w", models.ErrInvalidImportFile, err)
+		}
+		line, _ := reader.FieldPos(0)
+		report.TotalRows++
+		if report.TotalRows%importProgressInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			progress(models.JobProgress{Processed: report.TotalRows})
+		}
+
+		field := func(index int) string {
+			if index < len(record) {
+				return strings.TrimSpace(record[index])
+			}
+			return ""
+		}
+		id, name, rawPrice := field(idColumn), field(nameColumn), field(priceColumn)
+
+		price, priceErr := parseImportPrice(rawPrice, opts.DecimalComma)
+		if priceErr != nil {
+			report.AddError(line, "Price", priceErr.Error())
+		}
+		product, err := models.NewProduct(id, name, price)
+		if err != nil {
+			report.AddError(line, "ID", err.Error())
+			continue
+		}
+		if firstLine, seen := seenIDs[id]; seen {
+			report.AddError(line, "ID", fmt.Sprintf("duplicate product ID, first seen on line %d", firstLine))
+			continue
+		}
+		seenIDs[id] = line
+		if priceErr != nil {
+			continue
+		}
+		rows = append(rows, importRow{line: line, product: product})
+	}
+	return rows, nil
+}
+
+// importColumnIndexes localiza as colunas mapeadas no cabeçalho.
+func importColumnIndexes(header []string, columns ImportColumns) (id, name, price int, err error) {
+	positions := make(map[string]int, len(header))
+	for i, column := range header {
+		if i == 0 {
+			column = strings.TrimPrefix(column, "\ufeff") // BOM gravado por planilhas.
+		}
+		if _, repeated := positions[normalizeColumn(column)]; !repeated {
+			positions[normalizeColumn(column)] = i
+		}
+	}
+
+	var missing []string
+	lookup := func(column string) int {
+		index, ok := positions[normalizeColumn(column)]
+		if !ok {
+			missing = append(missing, column)
+		}
+		return index
+	}
+	id, name, price = lookup(columns.ID), lookup(columns.Name), lookup(columns.Price)
+	if len(missing) > 0 {
+		return 0, 0, 0, fmt.Errorf("%w: missing columns %s", models.ErrInvalidImportFile, strings.Join(missing, ", "))
+	}
+	return id, name, price, nil
+}
+
+// parseImportPrice converte o preço da planilha. Com vírgula decimal, os pontos são separadores
+// de milhar e são descartados.
+func parseImportPrice(raw string, decimalComma bool) (float64, error) {
+	if raw == "" {
+		return 0, errors.New("price is required")
+	}
+	normalized := raw
+	if decimalComma {
+		normalized = strings.ReplaceAll(normalized, ".", "")
+		normalized = strings.Replace(normalized, ",", ".", 1)
+	}
+
+	price, err := strconv.ParseFloat(normalized, 64)
+	if err != nil || math.IsNaN(price) || math.IsInf(price, 0) {
+		return 0, fmt.Errorf("price %q is not a valid number", raw)
+	}
+	if price < 0 || price > maxImportPrice {
+		return 0, fmt.Errorf("price must be between 0 and %.2f", maxImportPrice)
+	}
+	return price, nil
+}
@@ -0,0 +1,138 @@
+package application_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/danielrios/product-service-go/internal/adapters/driven/memdb"
+	"github.com/danielrios/product-service-go/internal/application"
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+func TestProductService_ImportProducts(t *testing.T) {
+	newService := func() (*application.ProductService, *memdb.InMemoryProductRepository) {
+		repo := memdb.NewInMemoryProductRepository()
+		existing, _ := models.NewProduct("1", "Existing", 10)
+		_ = repo.Add(existing, models.Mutation{})
+		service := application.NewProductService(repo, application.WithImports(repo))
+		return service, repo
+	}
+	options := func(mode models.ImportMode, dryRun bool) application.ProductImportOptions {
+		opts := application.DefaultProductImportOptions()
+		opts.Mode, opts.DryRun = mode, dryRun
+		return opts
+	}
+
+	t.Run("Upsert Creates And Updates", func(t *testing.T) {
+		service, repo := newService()
+		data := "id,name,price\n1,Renamed,12.5\n2,New Product,30\n"
+
+		ctx := application.WithRequestID(application.WithActor(context.Background(), "importer"), "req-1")
+
+		report, err := service.ImportProducts(ctx, strings.NewReader(data), options(models.ImportModeUpsert, false))
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !report.Applied || report.TotalRows != 2 || report.Created != 1 || report.Updated != 1 || report.ErrorCount != 0 {
+			t.Errorf("Unexpected report: %+v", report)
+		}
+		updated, _ := repo.GetByID("1")
+		if updated.Name != "Renamed" || updated.Price != 12.5 {
+			t.Errorf("Expected product 1 to be updated, got %+v", updated)
+		}
+		entries, _ := repo.ListAuditEntries(models.AuditFilter{Actor: "importer", Limit: 10})
+		if len(entries) != 2 || entries[0].RequestID != "req-1" {
+			t.Errorf("Expected one audit entry per imported product, got %+v", entries)
+		}
+		if update := entries[1]; update.Before == nil || update.Before.Name != "Existing" || update.After.Name != "Renamed" {
+			t.Errorf("Expected the update to be audited with the previous state, got %+v", update)
+		}
+	})
+
+	t.Run("Dry Run Reports Without Writing", func(t *testing.T) {
+		service, repo := newService()
+		data := "id,name,price\n2,New Product,30\n"
+
+		report, err := service.ImportProducts(context.Background(), strings.NewReader(data), options(models.ImportModeCreate, true))
+
+		if err != nil || report.Applied || report.Created != 1 || report.ValidRows != 1 {
+			t.Errorf("Unexpected report: %+v (%v)", report, err)
+		}
+		if _, err := repo.GetByID("2"); !errors.Is(err, models.ErrProductNotFound) {
+			t.Errorf("Expected dry run not to write, got %v", err)
+		}
+	})
+
+	t.Run("Any Invalid Row Rejects The Whole File", func(t *testing.T) {
+		service, repo := newService()
+		data := "id,name,price\n2,Valid,30\n,No ID,5\n3,Bad Price,abc\n4,Negative,-1\n2,Duplicate,1\n1,Existing,10\n"
+
+		report, err := service.ImportProducts(context.Background(), strings.NewReader(data), options(models.ImportModeCreate, false))
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		expected := []models.ImportRowError{
+			{Row: 3, Field: "ID"},
+			{Row: 4, Field: "Price"},
+			{Row: 5, Field: "Price"},
+			{Row: 6, Field: "ID"},
+			{Row: 7, Field: "ID"},
+		}
+		if report.Applied || report.TotalRows != 6 || report.ErrorCount != len(expected) {
+			t.Fatalf("Unexpected report: %+v", report)
+		}
+		for i, e := range expected {
+			if report.Errors[i].Row != e.Row || report.Errors[i].Field != e.Field {
+				t.Errorf("Expected error %d on row %d (%s), got %+v", i, e.Row, e.Field, report.Errors[i])
+			}
+		}
+		if _, err := repo.GetByID("2"); !errors.Is(err, models.ErrProductNotFound) {
+			t.Errorf("Expected nothing to be written, got %v", err)
+		}
+	})
+
+	t.Run("Column Mapping, Delimiter And Decimal Comma", func(t *testing.T) {
+		service, repo := newService()
+		opts := options(models.ImportModeCreate, false)
+		opts.Delimiter, opts.DecimalComma = ';', true
+		opts.Columns = application.ImportColumns{ID: "Código", Name: "Descrição", Price: "Preço"}
+		data := "\ufeffCódigo;Descrição;Preço\n2;Camisa;1.234,56\n"
+
+		report, err := service.ImportProducts(context.Background(), strings.NewReader(data), opts)
+
+		if err != nil || !report.Applied {
+			t.Fatalf("Unexpected report: %+v (%v)", report, err)
+		}
+		product, _ := repo.GetByID("2")
+		if product.Name != "Camisa" || product.Price != 1234.56 {
+			t.Errorf("Expected Camisa for 1234.56, got %+v", product)
+		}
+	})
+
+	t.Run("Rejects Malformed Files And Options", func(t *testing.T) {
+		service, _ := newService()
+
+		_, err := service.ImportProducts(context.Background(), strings.NewReader("id,name\n1,Only Name\n"), options(models.ImportModeCreate, false))
+		if !errors.Is(err, models.ErrInvalidImportFile) {
+			t.Errorf("Expected ErrInvalidImportFile for a missing column, got %v", err)
+		}
+		_, err = service.ImportProducts(context.Background(), strings.NewReader("id,name,price\n"), options("replace", false))
+		if !errors.Is(err, models.ErrInvalidImportOptions) {
+			t.Errorf("Expected ErrInvalidImportOptions for an unknown mode, got %v", err)
+		}
+	})
+
+	t.Run("Import Not Configured", func(t *testing.T) {
+		service := application.NewProductService(memdb.NewInMemoryProductRepository())
+
+		_, err := service.ImportProducts(context.Background(), strings.NewReader(""), application.DefaultProductImportOptions())
+
+		if !errors.Is(err, models.ErrImportNotConfigured) {
+			t.Errorf("Expected ErrImportNotConfigured, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,379 @@
+package application
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+// Tipos de mídia aceitos para patches parciais de produtos.
+const (
+	MergePatchMediaType = "application/merge-patch+json"
+	JSONPatchMediaType  = "application/json-patch+json"
+)
+
+// ProductPatch é uma alteração parcial aplicada sobre a representação JSON de um produto.
+type ProductPatch interface {
+	Apply(document []byte) ([]byte, error)
+}
+
+// MergePatch é um documento JSON Merge Patch (RFC 7396).
+type MergePatch struct {
+	patch any
+}
+
+// NewMergePatch interpreta um documento JSON Merge Patch.
+func NewMergePatch(data []byte) (*MergePatch, error) {
+	var patch any
+	if err := json.Unmarshal(data, &patch); err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrInvalidPatch, err)
+	}
+	return &MergePatch{patch: patch}, nil
+}
+
+// Apply mescla o patch ao documento: membros nulos são removidos e os demais são substituídos recursivamente.
+func (p *MergePatch) Apply(document []byte) ([]byte, error) {
+	var target any
+	if err := json.Unmarshal(document, &target); err != nil {
+		return nil, err
+	}
+	return json.Marshal(mergePatch(target, p.patch))
+}
+
+func mergePatch(target, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]any)
+	if !ok {
+		targetObject = make(map[string]any)
+	}
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+			continue
+		}
+		targetObject[name] = mergePatch(targetObject[name], value)
+	}
+	return targetObject
+}
+
+// JSONPatch é um documento JSON Patch (RFC 6902): uma sequência de operações aplicadas em ordem.
+// Se qualquer operação falhar, nenhuma alteração é aplicada.
+type JSONPatch []jsonPatchOperation
+
+type jsonPatchOperation struct {
+	op    string
+	path  []string
+	from  []string
+	value any
+}
+
+// ParseJSONPatch interpreta e valida um documento JSON Patch.
+func ParseJSONPatch(data []byte) (JSONPatch, error) {
+	var raw []struct {
+		Op    string          `json:"op"`
+		Path  *string         `json:"path"`
+		From  *string         `json:"from"`
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrInvalidPatch, err)
+	}
+
+	patch := make(JSONPatch, len(raw))
+	for i, r := range raw {
+		invalid := func(format string, args ...any) error {
+			return fmt.Errorf("%w: operation %d: %s", models.ErrInvalidPatch, i, fmt.Sprintf(format, args...))
+		}
+
+		switch r.Op {
+		case "add", "remove", "replace", "move", "copy", "test":
+		default:
+			return nil, invalid("unknown op %q", r.Op)
+		}
+		if r.Path == nil {
+			return nil, invalid("missing path")
+		}
+		path, err := parseJSONPointer(*r.Path)
+		if err != nil {
+			return nil, invalid("%v", err)
+		}
+		operation := jsonPatchOperation{op: r.Op, path: path}
+
+		switch r.Op {
+		case "move", "copy":
+			if r.From == nil {
+				return nil, invalid("missing from")
+			}
+			if operation.from, err = parseJSONPointer(*r.From); err != nil {
+				return nil, invalid("%v", err)
+			}
+		case "add", "replace", "test":
+			// Um valor ausente é diferente de um valor null, que é válido.
+			if len(r.Value) == 0 {
+				return nil, invalid("missing value")
+			}
+			if err := json.Unmarshal(r.Value, &operation.value); err != nil {
+				return nil, invalid("%v", err)
+			}
+		}
+		patch[i] = operation
+	}
+	return patch, nil
+}
+
+// Apply aplica as operações em ordem sobre o documento.
+func (p JSONPatch) Apply(document []byte) ([]byte, error) {
+	var target any
+	if err := json.Unmarshal(document, &target); err != nil {
+		return nil, err
+	}
+
+	for i, operation := range p {
+		var err error
+		if target, err = operation.apply(target); err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, operation.op, formatJSONPointer(operation.path), err)
+		}
+	}
+	return json.Marshal(target)
+}
+
+func (o jsonPatchOperation) apply(document any) (any, error) {
+	switch o.op {
+	case "add":
+		return addValue(document, o.path, o.value)
+	case "remove":
+		return removeValue(document, o.path)
+	case "replace":
+		if _, err := getValue(document, o.path); err != nil {
+			return nil, err
+		}
+		// O caminho vazio referencia o documento inteiro, que é substituído pelo valor.
+		if len(o.path) == 0 {
+			return o.value, nil
+		}
+		removed, err := removeValue(document, o.path)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(removed, o.path, o.value)
+	case "move":
+		if isProperPrefix(o.from, o.path) {
+			return nil, fmt.Errorf("%w: cannot move a value into one of its children", models.ErrInvalidPatch)
+		}
+		value, err := getValue(document, o.from)
+		if err != nil {
+			return nil, err
+		}
+		removed, err := removeValue(document, o.from)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(removed, o.path, value)
+	case "copy":
+		value, err := getValue(document, o.from)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(document, o.path, deepCopy(value))
+	case "test":
+		value, err := getValue(document, o.path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(value, o.value) {
+			return nil, fmt.Errorf("%w: test failed", models.ErrPatchConflict)
+		}
+		return document, nil
+	}
+	return nil, fmt.Errorf("%w: unknown op %q", models.ErrInvalidPatch, o.op)
+}
+
+// parseJSONPointer decompõe um JSON Pointer (RFC 6901) em seus tokens de referência.
+func parseJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("JSON pointer %q must start with /", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens, nil
+}
+
+func formatJSONPointer(tokens []string) string {
+	var b strings.Builder
+	for _, token := range tokens {
+		b.WriteByte('/')
+		b.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(token))
+	}
+	return b.String()
+}
+
+func isProperPrefix(prefix, path []string) bool {
+	return len(prefix) < len(path) && reflect.DeepEqual(prefix, path[:len(prefix)])
+}
+
+// arrayIndex converte um token em índice de array; allowEnd aceita o índice logo após o último elemento.
+func arrayIndex(token string, length int, allowEnd bool) (int, error) {
+	if token == "-" && allowEnd {
+		return length, nil
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("%w: invalid array index %q", models.ErrPatchConflict, token)
+	}
+	if index > length || (index == length && !allowEnd) {
+		return 0, fmt.Errorf("%w: array index %d out of range", models.ErrPatchConflict, index)
+	}
+	return index, nil
+}
+
+func getValue(document any, path []string) (any, error) {
+	current := document
+	for _, token := range path {
+		switch node := current.(type) {
+		case map[string]any:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("%w: member %q not found", models.ErrPatchConflict, token)
+			}
+			current = value
+		case []any:
+			index, err := arrayIndex(token, len(node), false)
+			if err != nil {
+				return nil, err
+			}
+			current = node[index]
+		default:
+			return nil, fmt.Errorf("%w: cannot traverse a scalar value", models.ErrPatchConflict)
+		}
+	}
+	return current, nil
+}
+
+// updateParent percorre o documento até o contêiner pai do caminho, substitui-o pelo resultado de fn
+// e reconstrói os contêineres acima dele, já que inserções e remoções em arrays criam novos slices.
+func updateParent(document any, path []string, fn func(parent any, key string) (any, error)) (any, error) {
+	if len(path) == 1 {
+		return fn(document, path[0])
+	}
+
+	child, err := getValue(document, path[:1])
+	if err != nil {
+		return nil, err
+	}
+	updated, err := updateParent(child, path[1:], fn)
+	if err != nil {
+		return nil, err
+	}
+
+	switch node := document.(type) {
+	case map[string]any:
+		node[path[0]] = updated
+	case []any:
+		index, _ := arrayIndex(path[0], len(node), false)
+		node[index] = updated
+	}
+	return document, nil
+}
+
+func addValue(document any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return updateParent(document, path, func(parent any, key string) (any, error) {
+		switch node := parent.(type) {
+		case map[string]any:
+			node[key] = value
+			return node, nil
+		case []any:
+			index, err := arrayIndex(key, len(node), true)
+			if err != nil {
+				return nil, err
+			}
+			node = append(node, nil)
+			copy(node[index+1:], node[index:])
+			node[index] = value
+			return node, nil
+		}
+		return nil, fmt.Errorf("%w: cannot add a member to a scalar value", models.ErrPatchConflict)
+	})
+}
+
+func removeValue(document any, path []string) (any, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("%w: cannot remove the whole document", models.ErrInvalidPatch)
+	}
+	return updateParent(document, path, func(parent any, key string) (any, error) {
+		switch node := parent.(type) {
+		case map[string]any:
+			if _, ok := node[key]; !ok {
+				return nil, fmt.Errorf("%w: member %q not found", models.ErrPatchConflict, key)
+			}
+			delete(node, key)
+			return node, nil
+		case []any:
+			index, err := arrayIndex(key, len(node), false)
+			if err != nil {
+				return nil, err
+			}
+			return append(node[:index], node[index+1:]...), nil
+		}
+		return nil, fmt.Errorf("%w: cannot remove a member from a scalar value", models.ErrPatchConflict)
+	})
+}
+
+func deepCopy(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		copied := make(map[string]any, len(v))
+		for name, member := range v {
+			copied[name] = deepCopy(member)
+		}
+		return copied
+	case []any:
+		copied := make([]any, len(v))
+		for i, element := range v {
+			copied[i] = deepCopy(element)
+		}
+		return copied
+	}
+	return value
+}
+
+// applyProductPatch aplica o patch à representação JSON do produto e decodifica o resultado,
+// rejeitando campos desconhecidos e alterações nos campos imutáveis.
+func applyProductPatch(product *models.Product, patch ProductPatch) (*models.Product, error) {
+	document, err := json.Marshal(product)
+	if err != nil {
+		return nil, err
+	}
+	patched, err := patch.Apply(document)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	var result models.Product
+	if err := decoder.Decode(&result); err != nil {
+		return nil, fmt.Errorf("%w: patched product is invalid: %v", models.ErrInvalidPatch, err)
+	}
+	if result.ID != product.ID || !result.CreatedAt.Equal(product.CreatedAt) {
+		return nil, models.ErrImmutableProductField
+	}
+	return &result, nil
+}
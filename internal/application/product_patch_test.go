@@ -0,0 +1,115 @@
+package application_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/danielrios/product-service-go/internal/application"
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+func assertJSONEqual(t *testing.T, got []byte, want string) {
+	t.Helper()
+	var gotValue, wantValue any
+	if err := json.Unmarshal(got, &gotValue); err != nil {
+		t.Fatalf("Invalid JSON result %s: %v", got, err)
+	}
+	_ = json.Unmarshal([]byte(want), &wantValue)
+	if !reflect.DeepEqual(gotValue, wantValue) {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
+
+func TestMergePatch_Apply(t *testing.T) {
+	// Exemplos do apêndice A da RFC 7396.
+	cases := []struct{ target, patch, result string }{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+	for _, c := range cases {
+		patch, err := application.NewMergePatch([]byte(c.patch))
+		if err != nil {
+			t.Fatalf("Failed to parse patch %s: %v", c.patch, err)
+		}
+		result, err := patch.Apply([]byte(c.target))
+		if err != nil {
+			t.Fatalf("Failed to apply %s to %s: %v", c.patch, c.target, err)
+		}
+		assertJSONEqual(t, result, c.result)
+	}
+}
+
+func TestJSONPatch_Apply(t *testing.T) {
+	t.Run("Operations", func(t *testing.T) {
+		// Exemplos do apêndice A da RFC 6902.
+		cases := []struct{ target, patch, result string }{
+			{`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"baz":"qux","foo":"bar"}`},
+			{`{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`},
+			{`{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`},
+			{`{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`},
+			{`{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`},
+			{`{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`, `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+			{`{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`},
+			{`{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`, `{"foo":["bar",["abc","def"]]}`},
+			{`{"foo":"bar"}`, `[{"op":"add","path":"/child","value":{"grandchild":{}}}]`, `{"foo":"bar","child":{"grandchild":{}}}`},
+			{`{"foo":null}`, `[{"op":"test","path":"/foo","value":null}]`, `{"foo":null}`},
+			{`{"/":9,"~1":10}`, `[{"op":"test","path":"/~01","value":10}]`, `{"/":9,"~1":10}`},
+			{`{"foo":{"bar":1}}`, `[{"op":"copy","from":"/foo","path":"/baz"},{"op":"replace","path":"/baz/bar","value":2}]`, `{"foo":{"bar":1},"baz":{"bar":2}}`},
+			{`{"foo":"bar"}`, `[{"op":"replace","path":"","value":{"baz":"qux"}}]`, `{"baz":"qux"}`},
+		}
+		for _, c := range cases {
+			patch, err := application.ParseJSONPatch([]byte(c.patch))
+			if err != nil {
+				t.Fatalf("Failed to parse patch %s: %v", c.patch, err)
+			}
+			result, err := patch.Apply([]byte(c.target))
+			if err != nil {
+				t.Fatalf("Failed to apply %s to %s: %v", c.patch, c.target, err)
+			}
+			assertJSONEqual(t, result, c.result)
+		}
+	})
+
+	t.Run("Conflicts", func(t *testing.T) {
+		cases := []struct{ target, patch string }{
+			{`{"baz":"qux"}`, `[{"op":"test","path":"/baz","value":"bar"}]`},
+			{`{"foo":"bar"}`, `[{"op":"add","path":"/baz/bat","value":"qux"}]`},
+			{`{"foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"qux"}]`},
+			{`{"foo":[1]}`, `[{"op":"remove","path":"/foo/01"}]`},
+			{`{"foo":[1]}`, `[{"op":"add","path":"/foo/2","value":2}]`},
+		}
+		for _, c := range cases {
+			patch, err := application.ParseJSONPatch([]byte(c.patch))
+			if err != nil {
+				t.Fatalf("Failed to parse patch %s: %v", c.patch, err)
+			}
+			if _, err := patch.Apply([]byte(c.target)); !errors.Is(err, models.ErrPatchConflict) {
+				t.Errorf("Expected ErrPatchConflict applying %s to %s, got %v", c.patch, c.target, err)
+			}
+		}
+	})
+
+	t.Run("Invalid Documents", func(t *testing.T) {
+		for _, patch := range []string{
+			`{"op":"add"}`,
+			`[{"op":"frobnicate","path":"/a"}]`,
+			`[{"op":"add","path":"/a"}]`,
+			`[{"op":"copy","path":"/a"}]`,
+			`[{"op":"remove","path":"a"}]`,
+		} {
+			if _, err := application.ParseJSONPatch([]byte(patch)); !errors.Is(err, models.ErrInvalidPatch) {
+				t.Errorf("Expected ErrInvalidPatch for %s, got %v", patch, err)
+			}
+		}
+	})
+}
@@ -1,7 +1,9 @@
 package application
 
 import (
+	"context"
 	"errors"
+	"time"
 
 	"github.com/danielrios/product-service-go/internal/core/models"
 	"github.com/danielrios/product-service-go/internal/core/ports"
@@ -9,73 +11,416 @@ import (
 
 // ProductService define a estrutura do nosso serviço de aplicação para produtos.
 type ProductService struct {
-	repo ports.ProductRepository
+	repo    ports.ProductRepository
+	history ports.ProductHistoryRepository
+	queries ports.ProductQueryRepository
+	imports ports.ProductImportRepository
+	jobs    *JobService
+	authz   *Authorizer
+}
+
+// ProductServiceOption configura dependências opcionais do ProductService.
+type ProductServiceOption func(*ProductService)
+
+// WithHistory habilita as consultas ao histórico de revisões dos produtos.
+func WithHistory(history ports.ProductHistoryRepository) ProductServiceOption {
+	return func(s *ProductService) {
+		s.history = history
+	}
+}
+
+// WithQueries delega ao repositório informado as listagens filtradas e as estatísticas.
+// Sem ele, o serviço filtra e agrega em memória o resultado de GetAll.
+func WithQueries(queries ports.ProductQueryRepository) ProductServiceOption {
+	return func(s *ProductService) {
+		s.queries = queries
+	}
+}
+
+// WithImports habilita a importação de produtos em lote pelo repositório informado.
+func WithImports(imports ports.ProductImportRepository) ProductServiceOption {
+	return func(s *ProductService) {
+		s.imports = imports
+	}
+}
+
+// WithJobs permite executar as operações longas, como a importação em lote, como jobs assíncronos,
+// registrando no serviço de jobs as funções que as executam.
+func WithJobs(jobs *JobService) ProductServiceOption {
+	return func(s *ProductService) {
+		s.jobs = jobs
+		jobs.Register(ImportProductsJobType, models.PermissionProductsImport, s.runImportJob)
+	}
+}
+
+// WithAuthorizer exige que o principal do contexto tenha a permissão de cada operação, qualquer que seja
+// o driver que chama o serviço, e aplica a política de campos: as alterações só podem mudar os campos
+// liberados pelos papéis do principal, e os campos que ele não pode ler são omitidos dos produtos
+// retornados. Os jobs assíncronos são autorizados quando submetidos.
+func WithAuthorizer(authz *Authorizer) ProductServiceOption {
+	return func(s *ProductService) {
+		s.authz = authz
+	}
 }
 
 // NewProductService cria e retorna uma nova instância de ProductService.
-func NewProductService(repo ports.ProductRepository) *ProductService {
-	return &ProductService{
+func NewProductService(repo ports.ProductRepository, opts ...ProductServiceOption) *ProductService {
+	s := &ProductService{
 		repo: repo,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // CreateProduct lida com a lógica de negócio para criar um novo produto.
-func (s *ProductService) CreateProduct(product *models.Product) (*models.Product, error) {
+func (s *ProductService) CreateProduct(ctx context.Context, product *models.Product) (*models.Product, error) {
+	if err := s.authorize(ctx, models.PermissionProductsCreate); err != nil {
+		return nil, err
+	}
 	validatedProduct, err := models.NewProduct(product.ID, product.Name, product.Price)
 	if err != nil {
 		return nil, err
 	}
+	// Na criação, cada campo preenchido é uma alteração em relação a um produto vazio.
+	if err := s.writableFields(ctx, models.PermissionProductsCreate).check(&models.Product{}, validatedProduct); err != nil {
+		return nil, err
+	}
 
-	err = s.repo.Add(validatedProduct)
+	err = s.repo.Add(validatedProduct, MutationFrom(ctx))
 	if err != nil {
 		return nil, err
 	}
-	return validatedProduct, nil
+	return s.redact(ctx, validatedProduct), nil
 }
 
 // GetProductByID lida com a lógica de negócio para buscar um produto por ID.
-func (s *ProductService) GetProductByID(id string) (*models.Product, error) {
+func (s *ProductService) GetProductByID(ctx context.Context, id string) (*models.Product, error) {
+	if err := s.authorize(ctx, models.PermissionProductsRead); err != nil {
+		return nil, err
+	}
 	product, err := s.repo.GetByID(id)
 	if err != nil {
 		return nil, err
 	}
-	return product, nil
+	return s.redact(ctx, product), nil
 }
 
-// GetAllProducts lida com a lógica de negócio para obter todos os produtos.
-func (s *ProductService) GetAllProducts() ([]*models.Product, error) {
-	products, err := s.repo.GetAll()
+// GetProductAsOf lida com a lógica de negócio para buscar um produto como estava em um instante passado.
+func (s *ProductService) GetProductAsOf(ctx context.Context, id string, at time.Time) (*models.Product, error) {
+	if err := s.authorize(ctx, models.PermissionProductsRead); err != nil {
+		return nil, err
+	}
+	if s.history == nil {
+		return nil, models.ErrHistoryNotConfigured
+	}
+	product, err := s.history.GetAsOf(id, at)
 	if err != nil {
 		return nil, err
 	}
-	return products, nil
+	return s.redact(ctx, product), nil
 }
 
-// UpdateProduct lida com a lógica de negócio para atualizar um produto.
-func (s *ProductService) UpdateProduct(id string, product *models.Product) (*models.Product, error) {
-	if id != product.ID {
-		return nil, errors.New("product ID in path does not match ID in body")
+// ListProductRevisions lida com a lógica de negócio para listar as revisões de um produto,
+// preenchendo o fim da validade de cada revisão com o início da seguinte. As revisões trazem os produtos
+// completos, então exigem a leitura de todos os campos.
+func (s *ProductService) ListProductRevisions(ctx context.Context, id string) ([]*models.ProductRevision, error) {
+	if err := s.authorize(ctx, models.PermissionProductsRead); err != nil {
+		return nil, err
+	}
+	if err := s.requireReadable(ctx, models.ProductFields...); err != nil {
+		return nil, err
+	}
+	if s.history == nil {
+		return nil, models.ErrHistoryNotConfigured
 	}
 
-	// Apenas valida os dados, sem criar uma nova instância que zeraria o CreatedAt
-	_, err := models.NewProduct(product.ID, product.Name, product.Price)
+	revisions, err := s.history.ListRevisions(id)
 	if err != nil {
 		return nil, err
 	}
+	for i := 0; i < len(revisions)-1; i++ {
+		validTo := revisions[i+1].ValidFrom
+		revisions[i].ValidTo = &validTo
+	}
+	return revisions, nil
+}
+
+// GetAllProducts lida com a lógica de negócio para obter os produtos que atendem ao filtro. Com uma
+// projeção, apenas os campos pedidos precisam ser lidos do armazenamento. Quando o principal não pode ler
+// todos os campos, a projeção se limita aos que ele pode, e os filtros só podem usar esses campos.
+func (s *ProductService) GetAllProducts(ctx context.Context, filter models.ProductFilter, projection models.ProductProjection) ([]*models.Product, error) {
+	if err := s.authorize(ctx, models.PermissionProductsRead); err != nil {
+		return nil, err
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	if err := projection.Validate(); err != nil {
+		return nil, err
+	}
+	readable := s.readableFields(ctx)
+	if readable != nil {
+		if err := models.NewForbiddenFieldsError(models.FieldAccessRead, append(filter.Fields(), projection...), readable); err != nil {
+			return nil, err
+		}
+		if len(projection) == 0 {
+			projection = readable
+		}
+	}
+
+	products, err := s.findProducts(filter, projection)
+	if err != nil || readable == nil {
+		return products, err
+	}
+	for i, product := range products {
+		products[i] = models.RedactProduct(product, readable)
+	}
+	return products, nil
+}
+
+// findProducts busca os produtos que atendem ao filtro, pelo repositório de consultas quando configurado.
+func (s *ProductService) findProducts(filter models.ProductFilter, projection models.ProductProjection) ([]*models.Product, error) {
+	if filter.IsEmpty() && len(projection) == 0 {
+		return s.repo.GetAll()
+	}
+	if s.queries != nil {
+		return s.queries.Find(filter, projection)
+	}
 
-	err = s.repo.Update(product)
+	products, err := s.repo.GetAll()
 	if err != nil {
 		return nil, err
 	}
-	// Após a atualização, busca e retorna a entidade completa do banco de dados.
-	return s.repo.GetByID(id)
+	filtered := make([]*models.Product, 0, len(products))
+	for _, p := range products {
+		if filter.Matches(p) {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered, nil
 }
 
-// DeleteProduct lida com a lógica de negócio para excluir um produto.
-func (s *ProductService) DeleteProduct(id string) error {
-	err := s.repo.Delete(id)
+// ExportProducts lida com a lógica de negócio para percorrer os produtos que atendem ao filtro,
+// entregando-os um a um a fn para que a exportação não precise carregar o catálogo inteiro. A exportação
+// traz os produtos completos, então exige a leitura de todos os campos.
+func (s *ProductService) ExportProducts(ctx context.Context, filter models.ProductFilter, fn func(product *models.Product) error) error {
+	if err := s.authorize(ctx, models.PermissionProductsRead); err != nil {
+		return err
+	}
+	if err := s.requireReadable(ctx, models.ProductFields...); err != nil {
+		return err
+	}
+	if err := filter.Validate(); err != nil {
+		return err
+	}
+	if s.queries != nil {
+		return s.queries.ForEach(filter, fn)
+	}
+
+	products, err := s.GetAllProducts(ctx, filter, nil)
 	if err != nil {
 		return err
 	}
+	for _, product := range products {
+		if err := fn(product); err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+// GetProductStats lida com a lógica de negócio para calcular as estatísticas do catálogo. As estatísticas
+// agregam os preços, então exigem a leitura de todos os campos.
+func (s *ProductService) GetProductStats(ctx context.Context, filter models.ProductFilter, groupBy models.StatsGrouping) (*models.ProductStats, error) {
+	if err := s.authorize(ctx, models.PermissionProductsRead); err != nil {
+		return nil, err
+	}
+	if err := s.requireReadable(ctx, models.ProductFields...); err != nil {
+		return nil, err
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	if !groupBy.IsValid() {
+		return nil, models.ErrInvalidProductQuery
+	}
+	if s.queries != nil {
+		return s.queries.Stats(filter, groupBy)
+	}
+
+	products, err := s.repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	builder := models.NewProductStatsBuilder(groupBy)
+	for _, p := range products {
+		if filter.Matches(p) {
+			builder.Add(p)
+		}
+	}
+	return builder.Build(), nil
+}
+
+// UpdateProduct lida com a lógica de negócio para atualizar um produto.
+// Os campos que o principal não pode ler chegam a ele zerados e são mantidos com os valores atuais.
+func (s *ProductService) UpdateProduct(ctx context.Context, id string, product *models.Product) (*models.Product, error) {
+	if err := s.authorize(ctx, models.PermissionProductsUpdate); err != nil {
+		return nil, err
+	}
+	if id != product.ID {
+		return nil, models.ErrProductIDMismatch
+	}
+
+	readable := s.readableFields(ctx)
+	return s.update(ctx, id, func(current *models.Product) (*models.Product, error) {
+		updated := product
+		if readable != nil {
+			updated = models.RestoreUnreadableFields(product, current, readable)
+		}
+		// Apenas valida os dados, sem criar uma nova instância que zeraria o CreatedAt
+		if _, err := models.NewProduct(updated.ID, updated.Name, updated.Price); err != nil {
+			return nil, err
+		}
+		return updated, nil
+	})
+}
+
+// PatchProduct lida com a lógica de negócio para atualizar parcialmente um produto.
+// O patch é aplicado sobre o estado atual e o resultado passa pelas mesmas validações de UpdateProduct.
+// Operações test de um JSON Patch permitem ao cliente condicionar a alteração ao estado que ele leu.
+func (s *ProductService) PatchProduct(ctx context.Context, id string, patch ProductPatch) (*models.Product, error) {
+	if err := s.authorize(ctx, models.PermissionProductsUpdate); err != nil {
+		return nil, err
+	}
+
+	return s.update(ctx, id, func(current *models.Product) (*models.Product, error) {
+		product, err := applyProductPatch(current, patch)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := models.NewProduct(product.ID, product.Name, product.Price); err != nil {
+			return nil, err
+		}
+		return product, nil
+	})
+}
+
+// maxUpdateAttempts limita as tentativas de uma atualização cujo produto é alterado por outra requisição
+// entre a leitura e a gravação.
+const maxUpdateAttempts = 3
+
+// update lê o estado atual do produto, monta o novo estado com build e o grava desde que o produto não
+// tenha mudado desde a leitura. Apenas os campos que mudam precisam ser liberados ao principal, então
+// reenviar um valor inalterado é permitido. Se o produto mudar nesse intervalo, o novo estado é montado e
+// verificado outra vez sobre o estado atual, até maxUpdateAttempts vezes; depois disso, o resultado é
+// ErrProductModified.
+func (s *ProductService) update(ctx context.Context, id string, build func(current *models.Product) (*models.Product, error)) (*models.Product, error) {
+	writable := s.writableFields(ctx, models.PermissionProductsUpdate)
+	for attempt := 1; ; attempt++ {
+		current, err := s.repo.GetByID(id)
+		if err != nil {
+			return nil, err
+		}
+		// Copia o estado atual, pois repositórios em memória podem devolver a própria instância armazenada.
+		previous := *current
+
+		product, err := build(&previous)
+		if err != nil {
+			return nil, err
+		}
+		if err := writable.check(&previous, product); err != nil {
+			return nil, err
+		}
+
+		err = s.repo.Update(product, &previous, MutationFrom(ctx))
+		if errors.Is(err, models.ErrProductModified) && attempt < maxUpdateAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		// Após a atualização, busca e retorna a entidade completa do banco de dados.
+		updated, err := s.repo.GetByID(id)
+		if err != nil {
+			return nil, err
+		}
+		return s.redact(ctx, updated), nil
+	}
+}
+
+// DeleteProduct lida com a lógica de negócio para excluir um produto.
+func (s *ProductService) DeleteProduct(ctx context.Context, id string) error {
+	if err := s.authorize(ctx, models.PermissionProductsDelete); err != nil {
+		return err
+	}
+	return s.repo.Delete(id, MutationFrom(ctx))
+}
+
+// authorize verifica a permissão do principal do contexto, quando o serviço tem um Authorizer.
+func (s *ProductService) authorize(ctx context.Context, permission models.Permission) error {
+	if s.authz == nil {
+		return nil
+	}
+	return s.authz.Authorize(ctx, permission)
+}
+
+// writableFields são os campos de produtos que uma operação pode alterar. Sem Restricted, todos.
+type writableFields struct {
+	Restricted bool
+	Fields     []models.ProductField
+}
+
+// check verifica se os campos alterados de before para after podem ser alterados.
+func (w writableFields) check(before, after *models.Product) error {
+	if !w.Restricted {
+		return nil
+	}
+	return models.NewForbiddenFieldsError(models.FieldAccessWrite, models.ChangedProductFields(before, after), w.Fields)
+}
+
+// writableFields retorna os campos que o principal do contexto pode alterar na operação, sem restrições
+// quando o serviço não tem um Authorizer.
+func (s *ProductService) writableFields(ctx context.Context, permission models.Permission) writableFields {
+	if s.authz == nil {
+		return writableFields{}
+	}
+	return writableFields{Restricted: true, Fields: s.authz.WritableFields(ctx, permission)}
+}
+
+// ReadableProductFields retorna os campos de produtos que o principal do contexto pode ler, ou nil quando
+// ele pode ler todos. Os produtos retornados pelo serviço trazem os demais campos zerados.
+func (s *ProductService) ReadableProductFields(ctx context.Context) models.ProductProjection {
+	return s.readableFields(ctx)
+}
+
+func (s *ProductService) readableFields(ctx context.Context) models.ProductProjection {
+	if s.authz == nil {
+		return nil
+	}
+	readable := s.authz.ReadableFields(ctx)
+	if len(readable) == len(models.ProductFields) {
+		return nil
+	}
+	return readable
+}
+
+// requireReadable recusa com um ForbiddenFieldsError as operações que expõem campos que o principal do
+// contexto não pode ler.
+func (s *ProductService) requireReadable(ctx context.Context, fields ...models.ProductField) error {
+	readable := s.readableFields(ctx)
+	if readable == nil {
+		return nil
+	}
+	return models.NewForbiddenFieldsError(models.FieldAccessRead, fields, readable)
+}
+
+// redact omite do produto retornado ao principal os campos que ele não pode ler.
+func (s *ProductService) redact(ctx context.Context, product *models.Product) *models.Product {
+	readable := s.readableFields(ctx)
+	if readable == nil {
+		return product
+	}
+	return models.RedactProduct(product, readable)
+}
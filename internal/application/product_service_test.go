@@ -0,0 +1,409 @@
+package application_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/danielrios/product-service-go/internal/adapters/driven/memdb"
+	"github.com/danielrios/product-service-go/internal/application"
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+func TestProductService_AuditLog(t *testing.T) {
+	t.Run("Records Every Mutation With Actor And Request ID", func(t *testing.T) {
+		repo := memdb.NewInMemoryProductRepository()
+		service := application.NewProductService(repo)
+		ctx := application.WithRequestID(application.WithActor(context.Background(), "alice"), "req-1")
+
+		_, _ = service.CreateProduct(ctx, &models.Product{ID: "1", Name: "Product 1", Price: 10})
+		_, _ = service.UpdateProduct(ctx, "1", &models.Product{ID: "1", Name: "Product 1", Price: 12})
+		_ = service.DeleteProduct(ctx, "1")
+
+		entries, err := repo.ListAuditEntries(models.AuditFilter{ProductID: "1", Limit: 10})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		expected := []models.AuditOperation{models.AuditOperationDelete, models.AuditOperationUpdate, models.AuditOperationCreate}
+		if len(entries) != len(expected) {
+			t.Fatalf("Expected %d entries, got %d", len(expected), len(entries))
+		}
+		for i, operation := range expected {
+			if entries[i].Operation != operation || entries[i].Actor != "alice" || entries[i].RequestID != "req-1" {
+				t.Errorf("Unexpected entry %d: %+v", i, entries[i])
+			}
+		}
+
+		update := entries[1]
+		if update.Before.Price != 10 || update.After.Price != 12 {
+			t.Errorf("Expected price to go from 10 to 12, got before=%+v after=%+v", update.Before, update.After)
+		}
+		if len(update.Changes) != 1 || update.Changes[0].Field != "Price" {
+			t.Errorf("Expected only the price change, got %+v", update.Changes)
+		}
+	})
+
+	t.Run("Failed Mutations Are Not Recorded", func(t *testing.T) {
+		repo := memdb.NewInMemoryProductRepository()
+		service := application.NewProductService(repo)
+
+		_ = service.DeleteProduct(context.Background(), "nonexistent")
+		_, _ = service.CreateProduct(context.Background(), &models.Product{ID: "", Name: "Invalid"})
+
+		entries, _ := repo.ListAuditEntries(models.AuditFilter{Limit: 10})
+		if len(entries) != 0 {
+			t.Errorf("Expected no entries, got %d", len(entries))
+		}
+	})
+
+	t.Run("Anonymous Actor By Default", func(t *testing.T) {
+		repo := memdb.NewInMemoryProductRepository()
+		service := application.NewProductService(repo)
+
+		_, _ = service.CreateProduct(context.Background(), &models.Product{ID: "1", Name: "Product 1", Price: 10})
+
+		entries, _ := repo.ListAuditEntries(models.AuditFilter{Limit: 10})
+		if len(entries) != 1 || entries[0].Actor != application.AnonymousActor {
+			t.Errorf("Expected one anonymous entry, got %+v", entries)
+		}
+	})
+}
+
+func TestProductService_ListProductRevisions(t *testing.T) {
+	t.Run("Fills Validity Intervals", func(t *testing.T) {
+		repo := memdb.NewInMemoryProductRepository()
+		service := application.NewProductService(repo, application.WithHistory(repo))
+		ctx := context.Background()
+		_, _ = service.CreateProduct(ctx, &models.Product{ID: "1", Name: "Product 1", Price: 10})
+		_, _ = service.UpdateProduct(ctx, "1", &models.Product{ID: "1", Name: "Product 1", Price: 12})
+
+		revisions, err := service.ListProductRevisions(ctx, "1")
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(revisions) != 2 {
+			t.Fatalf("Expected 2 revisions, got %d", len(revisions))
+		}
+		if revisions[0].ValidTo == nil || !revisions[0].ValidTo.Equal(revisions[1].ValidFrom) {
+			t.Errorf("Expected first revision to end when the second starts, got %+v", revisions[0])
+		}
+		if revisions[1].ValidTo != nil {
+			t.Errorf("Expected current revision to have no end, got %v", revisions[1].ValidTo)
+		}
+	})
+
+	t.Run("History Not Configured", func(t *testing.T) {
+		service := application.NewProductService(memdb.NewInMemoryProductRepository())
+
+		_, err := service.ListProductRevisions(context.Background(), "1")
+
+		if !errors.Is(err, models.ErrHistoryNotConfigured) {
+			t.Errorf("Expected ErrHistoryNotConfigured, got %v", err)
+		}
+	})
+}
+
+func TestProductService_PatchProduct(t *testing.T) {
+	newService := func(t *testing.T) (*application.ProductService, *memdb.InMemoryProductRepository) {
+		t.Helper()
+		repo := memdb.NewInMemoryProductRepository()
+		service := application.NewProductService(repo)
+		if _, err := service.CreateProduct(context.Background(), &models.Product{ID: "1", Name: "Product 1", Price: 10}); err != nil {
+			t.Fatalf("Failed to create product: %v", err)
+		}
+		return service, repo
+	}
+
+	t.Run("Merge Patch Changes Only The Given Fields", func(t *testing.T) {
+		service, repo := newService(t)
+		patch, _ := application.NewMergePatch([]byte(`{"Price": 12.5}`))
+
+		updated, err := service.PatchProduct(context.Background(), "1", patch)
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if updated.Price != 12.5 || updated.Name != "Product 1" {
+			t.Errorf("Expected only the price to change, got %+v", updated)
+		}
+		entries, _ := repo.ListAuditEntries(models.AuditFilter{Limit: 10})
+		if len(entries) != 2 || entries[0].Operation != models.AuditOperationUpdate {
+			t.Errorf("Expected the patch to be audited as an update, got %+v", entries)
+		}
+	})
+
+	t.Run("JSON Patch Test Guards The Update", func(t *testing.T) {
+		service, _ := newService(t)
+		stale, _ := application.ParseJSONPatch([]byte(`[{"op":"test","path":"/Price","value":99},{"op":"replace","path":"/Price","value":20}]`))
+
+		_, err := service.PatchProduct(context.Background(), "1", stale)
+
+		if !errors.Is(err, models.ErrPatchConflict) {
+			t.Errorf("Expected ErrPatchConflict, got %v", err)
+		}
+		current, _ := service.GetProductByID(context.Background(), "1")
+		if current.Price != 10 {
+			t.Errorf("Expected the product to be unchanged, got %+v", current)
+		}
+	})
+
+	t.Run("Rejects Invalid Results", func(t *testing.T) {
+		service, _ := newService(t)
+		cases := map[string]error{
+			`{"ID": "2"}`:         models.ErrImmutableProductField,
+			`{"CreatedAt": null}`: models.ErrImmutableProductField,
+			`{"Price": "free"}`:   models.ErrInvalidPatch,
+			`{"prce": 0}`:         models.ErrInvalidPatch,
+		}
+		for document, expected := range cases {
+			patch, _ := application.NewMergePatch([]byte(document))
+			if _, err := service.PatchProduct(context.Background(), "1", patch); !errors.Is(err, expected) {
+				t.Errorf("Expected %v for %s, got %v", expected, document, err)
+			}
+		}
+	})
+
+	t.Run("Product Not Found", func(t *testing.T) {
+		service, _ := newService(t)
+		patch, _ := application.NewMergePatch([]byte(`{"Price": 1}`))
+
+		if _, err := service.PatchProduct(context.Background(), "nonexistent", patch); !errors.Is(err, models.ErrProductNotFound) {
+			t.Errorf("Expected ErrProductNotFound, got %v", err)
+		}
+	})
+}
+
+func TestProductService_Authorization(t *testing.T) {
+	authorizer, err := application.NewAuthorizer(models.DefaultRolePermissions(), models.DefaultProductFieldPolicy())
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := memdb.NewInMemoryProductRepository()
+	jobs := newTestJobService(memdb.NewInMemoryJobRepository())
+	service := application.NewProductService(repo, application.WithImports(repo), application.WithJobs(jobs),
+		application.WithAuthorizer(authorizer))
+	as := func(roles ...string) context.Context {
+		return application.WithPrincipal(context.Background(), &models.Principal{Subject: "user", Roles: roles})
+	}
+	product := &models.Product{ID: "1", Name: "Product 1"}
+
+	if _, err := service.CreateProduct(context.Background(), product); !errors.Is(err, models.ErrUnauthenticated) {
+		t.Errorf("Expected ErrUnauthenticated without a principal, got %v", err)
+	}
+	if _, err := service.CreateProduct(as(models.RoleViewer), product); !errors.Is(err, models.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden for a viewer, got %v", err)
+	}
+	if _, err := service.CreateProduct(as(models.RoleEditor), product); err != nil {
+		t.Fatalf("Expected the editor to create the product, got %v", err)
+	}
+	if _, err := service.GetProductByID(as(models.RoleViewer), "1"); err != nil {
+		t.Errorf("Expected the viewer to read the product, got %v", err)
+	}
+	if _, err := service.UpdateProduct(as(models.RolePricingManager), "1", &models.Product{ID: "1", Name: "Product 1", Price: 12}); err != nil {
+		t.Errorf("Expected the pricing manager to update the product, got %v", err)
+	}
+	if err := service.DeleteProduct(as(models.RolePricingManager), "1"); !errors.Is(err, models.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden for a pricing manager deleting, got %v", err)
+	}
+
+	t.Run("Jobs Are Authorized On Submission", func(t *testing.T) {
+		ctx, stop := context.WithCancel(context.Background())
+		defer stop()
+		go jobs.Run(ctx)
+
+		data := "id,name,price\n2,Product 2,0\n"
+		if _, err := service.SubmitImportProducts(as(models.RoleViewer), strings.NewReader(data), application.DefaultProductImportOptions()); !errors.Is(err, models.ErrForbidden) {
+			t.Errorf("Expected ErrForbidden for a viewer, got %v", err)
+		}
+		job, err := service.SubmitImportProducts(as(models.RoleEditor), strings.NewReader(data), application.DefaultProductImportOptions())
+		if err != nil {
+			t.Fatalf("Expected the editor to submit the import, got %v", err)
+		}
+		waitForJob(t, jobs, job.ID, models.JobSucceeded)
+	})
+}
+
+func TestProductService_FieldPolicy(t *testing.T) {
+	policy := models.DefaultProductFieldPolicy()
+	policy.Read = map[string][]models.ProductField{models.RoleViewer: {models.ProductFieldName}}
+	authorizer, err := application.NewAuthorizer(models.DefaultRolePermissions(), policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := memdb.NewInMemoryProductRepository()
+	jobs := newTestJobService(memdb.NewInMemoryJobRepository())
+	service := application.NewProductService(repo, application.WithImports(repo), application.WithJobs(jobs),
+		application.WithAuthorizer(authorizer))
+	as := func(roles ...string) context.Context {
+		return application.WithPrincipal(context.Background(), &models.Principal{Subject: "user", Roles: roles})
+	}
+	if _, err := service.CreateProduct(as(models.RoleAdmin), &models.Product{ID: "1", Name: "Product 1", Price: 10}); err != nil {
+		t.Fatal(err)
+	}
+	forbidden := func(t *testing.T, err error, field models.ProductField) {
+		t.Helper()
+		var fieldsErr *models.ForbiddenFieldsError
+		if !errors.As(err, &fieldsErr) || len(fieldsErr.Fields) != 1 || fieldsErr.Fields[0] != field {
+			t.Errorf("Expected %s to be forbidden, got %v", field, err)
+		}
+	}
+
+	t.Run("Creation", func(t *testing.T) {
+		_, err := service.CreateProduct(as(models.RoleEditor), &models.Product{ID: "3", Name: "Product 3", Price: 30})
+		forbidden(t, err, models.ProductFieldPrice)
+		if _, err := service.CreateProduct(as(models.RoleEditor), &models.Product{ID: "4", Name: "Product 4"}); err != nil {
+			t.Errorf("Expected the editor to create a product without a price, got %v", err)
+		}
+	})
+
+	t.Run("Updates", func(t *testing.T) {
+		_, err := service.UpdateProduct(as(models.RoleEditor), "1", &models.Product{ID: "1", Name: "Product 1", Price: 12})
+		forbidden(t, err, models.ProductFieldPrice)
+		_, err = service.UpdateProduct(as(models.RolePricingManager), "1", &models.Product{ID: "1", Name: "Renamed", Price: 10})
+		forbidden(t, err, models.ProductFieldName)
+
+		patch, _ := application.NewMergePatch([]byte(`{"Name": "Renamed", "Price": 10}`))
+		if _, err := service.PatchProduct(as(models.RoleEditor), "1", patch); err != nil {
+			t.Errorf("Expected the editor to rename the product resending its price, got %v", err)
+		}
+		if _, err := service.UpdateProduct(as(models.RoleEditor, models.RolePricingManager), "1", &models.Product{ID: "1", Name: "Product 1", Price: 12}); err != nil {
+			t.Errorf("Expected the combined roles to change both fields, got %v", err)
+		}
+	})
+
+	t.Run("New Import Rows", func(t *testing.T) {
+		_, err := service.ImportProducts(as(models.RoleEditor), strings.NewReader("id,name,price\n5,Product 5,50\n"), application.DefaultProductImportOptions())
+		forbidden(t, err, models.ProductFieldPrice)
+		if _, err := service.ImportProducts(as(models.RoleEditor), strings.NewReader("id,name,price\n5,Product 5,0\n"), application.DefaultProductImportOptions()); err != nil {
+			t.Errorf("Expected the editor to import a product without a price, got %v", err)
+		}
+	})
+
+	t.Run("Upsert Imports", func(t *testing.T) {
+		opts := application.DefaultProductImportOptions()
+		opts.Mode = models.ImportModeUpsert
+		data := "id,name,price\n1,Product 1,15\n2,Product 2,20\n"
+		_, err := service.ImportProducts(as(models.RoleEditor), strings.NewReader(data), opts)
+		forbidden(t, err, models.ProductFieldPrice)
+		if _, err := service.GetProductByID(as(models.RoleEditor), "2"); !errors.Is(err, models.ErrProductNotFound) {
+			t.Errorf("Expected the forbidden import to write nothing, got %v", err)
+		}
+
+		ctx, stop := context.WithCancel(context.Background())
+		defer stop()
+		go jobs.Run(ctx)
+		job, err := service.SubmitImportProducts(as(models.RoleEditor), strings.NewReader(data), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if failed := waitForJob(t, jobs, job.ID, models.JobFailed); !strings.Contains(failed.Error, string(models.ProductFieldPrice)) {
+			t.Errorf("Expected the job to fail on the price change, got %q", failed.Error)
+		}
+	})
+
+	t.Run("Read Redaction", func(t *testing.T) {
+		product, err := service.GetProductByID(as(models.RoleViewer), "1")
+		if err != nil || product.Name == "" || product.Price != 0 {
+			t.Errorf("Expected the price to be redacted, got %+v %v", product, err)
+		}
+		if err := service.ExportProducts(as(models.RoleViewer), models.ProductFilter{}, func(*models.Product) error { return nil }); !errors.Is(err, models.ErrForbiddenFields) {
+			t.Errorf("Expected ErrForbiddenFields exporting full products, got %v", err)
+		}
+		if product, err := service.GetProductByID(as(models.RoleEditor), "1"); err != nil || product.Price == 0 {
+			t.Errorf("Expected the editor to read the price, got %+v %v", product, err)
+		}
+	})
+}
+
+// racingRepository grava, antes de cada uma das próximas races atualizações, uma atualização concorrente
+// do produto, simulando outra requisição entre a leitura e a gravação do serviço.
+type racingRepository struct {
+	*memdb.InMemoryProductRepository
+	concurrent *models.Product
+	races      int
+}
+
+func (r *racingRepository) Update(product, expected *models.Product, mutation models.Mutation) error {
+	if r.races > 0 {
+		r.races--
+		concurrent := *r.concurrent
+		_ = r.InMemoryProductRepository.Update(&concurrent, nil, models.Mutation{})
+		r.concurrent.Price++
+	}
+	return r.InMemoryProductRepository.Update(product, expected, mutation)
+}
+
+func TestProductService_ConcurrentUpdates(t *testing.T) {
+	policy := models.DefaultProductFieldPolicy()
+	policy.Read = map[string][]models.ProductField{models.RoleEditor: {models.ProductFieldName}}
+	authorizer, err := application.NewAuthorizer(models.DefaultRolePermissions(), policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	as := func(roles ...string) context.Context {
+		return application.WithPrincipal(context.Background(), &models.Principal{Subject: "user", Roles: roles})
+	}
+	newService := func(t *testing.T, races int) (*application.ProductService, *racingRepository) {
+		t.Helper()
+		repo := &racingRepository{InMemoryProductRepository: memdb.NewInMemoryProductRepository(), races: races}
+		product, _ := models.NewProduct("1", "Product 1", 10)
+		_ = repo.Add(product, models.Mutation{})
+		repo.concurrent = &models.Product{ID: "1", Name: "Product 1", Price: 12}
+		return application.NewProductService(repo, application.WithAuthorizer(authorizer)), repo
+	}
+
+	t.Run("Stale Values Are Checked Against The Current State", func(t *testing.T) {
+		service, repo := newService(t, 1)
+
+		_, err := service.UpdateProduct(as(models.RoleAdmin), "1", &models.Product{ID: "1", Name: "Renamed", Price: 10})
+
+		if err != nil {
+			t.Fatalf("Expected the update to be retried, got %v", err)
+		}
+		current, _ := repo.GetByID("1")
+		if current.Name != "Renamed" || current.Price != 10 {
+			t.Errorf("Expected the admin update to win after the retry, got %+v", current)
+		}
+
+		service, repo = newService(t, 1)
+		patch, _ := application.NewMergePatch([]byte(`{"Name": "Renamed", "Price": 10}`))
+		_, err = service.PatchProduct(as(models.RoleEditor), "1", patch)
+
+		if !errors.Is(err, models.ErrForbiddenFields) {
+			t.Errorf("Expected the editor's stale price to be a forbidden change, got %v", err)
+		}
+		if current, _ := repo.GetByID("1"); current.Price != 12 {
+			t.Errorf("Expected the concurrent price to be kept, got %+v", current)
+		}
+	})
+
+	t.Run("Gives Up After Repeated Conflicts", func(t *testing.T) {
+		service, _ := newService(t, 3)
+
+		_, err := service.UpdateProduct(as(models.RoleAdmin), "1", &models.Product{ID: "1", Name: "Renamed", Price: 10})
+
+		if !errors.Is(err, models.ErrProductModified) {
+			t.Errorf("Expected ErrProductModified, got %v", err)
+		}
+	})
+
+	t.Run("Unreadable Fields Are Kept", func(t *testing.T) {
+		service, repo := newService(t, 0)
+		read, _ := service.GetProductByID(as(models.RoleEditor), "1")
+		read.Name = "Renamed"
+
+		updated, err := service.UpdateProduct(as(models.RoleEditor), "1", read)
+
+		if err != nil {
+			t.Fatalf("Expected the redacted price not to count as a change, got %v", err)
+		}
+		if updated.Price != 0 {
+			t.Errorf("Expected the response to stay redacted, got %+v", updated)
+		}
+		if current, _ := repo.GetByID("1"); current.Name != "Renamed" || current.Price != 10 {
+			t.Errorf("Expected the price to be kept, got %+v", current)
+		}
+	})
+}
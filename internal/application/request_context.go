@@ -0,0 +1,58 @@
+package application
+
+import (
+	"context"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+// AnonymousActor identifica mutações feitas sem um autor conhecido.
+const AnonymousActor = "anonymous"
+
+type actorKey struct{}
+
+type requestIDKey struct{}
+
+// WithActor retorna um contexto que identifica o autor das operações.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom retorna o autor registrado no contexto, ou AnonymousActor.
+func ActorFrom(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return AnonymousActor
+}
+
+// WithRequestID retorna um contexto que carrega o ID da requisição de origem.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFrom retorna o ID da requisição registrado no contexto, ou uma string vazia.
+func RequestIDFrom(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// MutationFrom retorna o autor e a requisição do contexto, registrados pelos repositórios na auditoria
+// das mutações de produtos.
+func MutationFrom(ctx context.Context) models.Mutation {
+	return models.Mutation{Actor: ActorFrom(ctx), RequestID: RequestIDFrom(ctx)}
+}
+
+type principalKey struct{}
+
+// WithPrincipal retorna um contexto que carrega o principal autenticado da requisição. O autor das
+// operações passa a ser o Subject do principal.
+func WithPrincipal(ctx context.Context, principal *models.Principal) context.Context {
+	return WithActor(context.WithValue(ctx, principalKey{}, principal), principal.Subject)
+}
+
+// PrincipalFrom retorna o principal autenticado registrado no contexto, ou nil em requisições anônimas.
+func PrincipalFrom(ctx context.Context) *models.Principal {
+	principal, _ := ctx.Value(principalKey{}).(*models.Principal)
+	return principal
+}
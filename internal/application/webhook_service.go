@@ -0,0 +1,275 @@
+package application
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+	"github.com/danielrios/product-service-go/internal/core/ports"
+)
+
+// Cabeçalhos enviados em cada callback de webhook.
+const (
+	WebhookIDHeader        = "X-Webhook-ID"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// WebhookConfig agrupa os parâmetros de entrega dos webhooks.
+type WebhookConfig struct {
+	PollInterval time.Duration // Intervalo entre consultas quando não há entregas vencidas.
+	BatchSize    int           // Quantidade máxima de entregas reservadas por consulta.
+	Lease        time.Duration // Tempo de reserva de uma entrega em curso.
+	MaxAttempts  int           // Tentativas antes de a entrega ser marcada como morta.
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+}
+
+// DefaultWebhookConfig retorna a configuração padrão de entrega dos webhooks.
+func DefaultWebhookConfig() WebhookConfig {
+	return WebhookConfig{
+		PollInterval: time.Second,
+		BatchSize:    50,
+		Lease:        time.Minute,
+		MaxAttempts:  8,
+		BaseBackoff:  5 * time.Second,
+		MaxBackoff:   time.Hour,
+	}
+}
+
+// WebhookService gerencia as inscrições de webhooks e a entrega assinada dos eventos de produtos.
+// Ele implementa ports.EventSink, transformando cada evento recebido do outbox em entregas enfileiradas.
+type WebhookService struct {
+	repo   ports.WebhookRepository
+	sender ports.WebhookSender
+	config WebhookConfig
+}
+
+// NewWebhookService cria e retorna uma nova instância de WebhookService.
+func NewWebhookService(repo ports.WebhookRepository, sender ports.WebhookSender, config WebhookConfig) *WebhookService {
+	return &WebhookService{
+		repo:   repo,
+		sender: sender,
+		config: config,
+	}
+}
+
+var _ ports.EventSink = (*WebhookService)(nil)
+
+// CreateSubscription valida e registra uma nova inscrição.
+func (s *WebhookService) CreateSubscription(sub *models.WebhookSubscription) (*models.WebhookSubscription, error) {
+	created, err := models.NewWebhookSubscription(sub.URL, sub.EventTypes, sub.Secret)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.AddSubscription(created); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// GetSubscription busca uma inscrição pelo ID.
+func (s *WebhookService) GetSubscription(id string) (*models.WebhookSubscription, error) {
+	return s.repo.GetSubscription(id)
+}
+
+// ListSubscriptions retorna todas as inscrições.
+func (s *WebhookService) ListSubscriptions() ([]*models.WebhookSubscription, error) {
+	return s.repo.ListSubscriptions()
+}
+
+// UpdateSubscription altera a URL, os tipos de eventos e, se informado, o segredo de uma inscrição.
+func (s *WebhookService) UpdateSubscription(id string, changes *models.WebhookSubscription) (*models.WebhookSubscription, error) {
+	sub, err := s.repo.GetSubscription(id)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *sub
+	updated.URL = changes.URL
+	updated.EventTypes = changes.EventTypes
+	if changes.Secret != "" {
+		updated.Secret = changes.Secret
+	}
+	if err := updated.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateSubscription(&updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteSubscription remove uma inscrição.
+func (s *WebhookService) DeleteSubscription(id string) error {
+	return s.repo.DeleteSubscription(id)
+}
+
+// ListDeliveries retorna o histórico de entregas de uma inscrição.
+func (s *WebhookService) ListDeliveries(subscriptionID string, limit int) ([]*models.WebhookDelivery, error) {
+	if _, err := s.repo.GetSubscription(subscriptionID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListDeliveries(subscriptionID, limit)
+}
+
+// RetryDelivery reagenda imediatamente uma entrega, inclusive uma que já foi marcada como morta.
+func (s *WebhookService) RetryDelivery(subscriptionID, deliveryID string) (*models.WebhookDelivery, error) {
+	delivery, err := s.repo.GetDelivery(deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.SubscriptionID != subscriptionID {
+		return nil, models.ErrWebhookDeliveryNotFound
+	}
+
+	delivery.Status = models.DeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = time.Now()
+	if err := s.repo.UpdateDelivery(delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// Publish enfileira uma entrega do evento para cada inscrição interessada.
+func (s *WebhookService) Publish(_ context.Context, event models.ProductEvent) error {
+	subs, err := s.repo.ListSubscriptions()
+	if err != nil {
+		return err
+	}
+
+	var deliveries []*models.WebhookDelivery
+	for _, sub := range subs {
+		if sub.Matches(event.Type) {
+			deliveries = append(deliveries, models.NewWebhookDelivery(sub.ID, event))
+		}
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return s.repo.EnqueueDeliveries(deliveries)
+}
+
+// Run executa o worker de entregas até que o contexto seja cancelado.
+func (s *WebhookService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		delivered, err := s.DeliverDue(ctx)
+		if err != nil {
+			log.Printf("Erro ao entregar webhooks: %v", err)
+		}
+
+		if err == nil && delivered == s.config.BatchSize {
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverDue reserva e tenta entregar um lote de entregas vencidas, retornando quantas foram tentadas.
+func (s *WebhookService) DeliverDue(ctx context.Context) (int, error) {
+	deliveries, err := s.repo.ClaimDueDeliveries(s.config.BatchSize, s.config.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, delivery := range deliveries {
+		if err := s.attempt(ctx, delivery); err != nil {
+			return i, err
+		}
+	}
+	return len(deliveries), nil
+}
+
+// attempt envia uma entrega e registra o resultado, aplicando backoff exponencial nas falhas.
+func (s *WebhookService) attempt(ctx context.Context, delivery *models.WebhookDelivery) error {
+	sub, err := s.repo.GetSubscription(delivery.SubscriptionID)
+	if errors.Is(err, models.ErrWebhookNotFound) {
+		// A inscrição foi removida depois do enfileiramento: não há para onde entregar.
+		delivery.Status = models.DeliveryDead
+		delivery.LastError = err.Error()
+		return s.repo.UpdateDelivery(delivery)
+	}
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(delivery.Event)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	statusCode, sendErr := s.sender.Send(ctx, ports.WebhookRequest{
+		URL: sub.URL,
+		Headers: map[string]string{
+			"Content-Type":         "application/json",
+			WebhookIDHeader:        delivery.ID,
+			WebhookEventHeader:     string(delivery.Event.Type),
+			WebhookTimestampHeader: timestamp,
+			WebhookSignatureHeader: SignWebhookPayload(sub.Secret, timestamp, body),
+		},
+		Body: body,
+	})
+
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.ResponseStatus = statusCode
+	delivery.LastError = ""
+	if sendErr == nil && (statusCode < 200 || statusCode > 299) {
+		sendErr = fmt.Errorf("unexpected response status %d", statusCode)
+	}
+
+	switch {
+	case sendErr == nil:
+		delivery.Status = models.DeliverySucceeded
+	case delivery.Attempts >= s.config.MaxAttempts:
+		delivery.Status = models.DeliveryDead
+		delivery.LastError = sendErr.Error()
+		log.Printf("Entrega de webhook %s descartada após %d tentativas: %v", delivery.ID, delivery.Attempts, sendErr)
+	default:
+		delivery.Status = models.DeliveryPending
+		delivery.LastError = sendErr.Error()
+		delivery.NextAttemptAt = now.Add(exponentialBackoff(s.config.BaseBackoff, s.config.MaxBackoff, delivery.Attempts))
+	}
+
+	return s.repo.UpdateDelivery(delivery)
+}
+
+// SignWebhookPayload calcula a assinatura HMAC-SHA256 de um callback.
+// O conteúdo assinado é "<timestamp>.<corpo>", e o resultado tem o formato "sha256=<hex>".
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature verifica, em tempo constante, se a assinatura corresponde ao conteúdo recebido.
+func VerifyWebhookSignature(secret, timestamp string, body []byte, signature string) bool {
+	expected := SignWebhookPayload(secret, timestamp, body)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
@@ -0,0 +1,204 @@
+package application_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/adapters/driven/memdb"
+	"github.com/danielrios/product-service-go/internal/adapters/driven/webhookclient"
+	"github.com/danielrios/product-service-go/internal/application"
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+// webhookReceiver é um receptor local de callbacks que responde com os status configurados, em ordem.
+type webhookReceiver struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []receivedWebhook
+}
+
+type receivedWebhook struct {
+	header http.Header
+	body   []byte
+}
+
+func (rcv *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	rcv.requests = append(rcv.requests, receivedWebhook{header: r.Header.Clone(), body: body})
+	status := http.StatusOK
+	if len(rcv.statuses) > 0 {
+		status, rcv.statuses = rcv.statuses[0], rcv.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func (rcv *webhookReceiver) received() []receivedWebhook {
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	return append([]receivedWebhook(nil), rcv.requests...)
+}
+
+func testWebhookConfig() application.WebhookConfig {
+	return application.WebhookConfig{
+		PollInterval: 5 * time.Millisecond,
+		BatchSize:    10,
+		Lease:        time.Minute,
+		MaxAttempts:  3,
+		BaseBackoff:  0,
+		MaxBackoff:   0,
+	}
+}
+
+func newTestWebhookService(t *testing.T, receiver http.Handler) (*application.WebhookService, *memdb.InMemoryWebhookRepository, string) {
+	t.Helper()
+	server := httptest.NewServer(receiver)
+	t.Cleanup(server.Close)
+
+	repo := memdb.NewInMemoryWebhookRepository()
+	service := application.NewWebhookService(repo, webhookclient.NewHTTPSender(time.Second), testWebhookConfig())
+	return service, repo, server.URL
+}
+
+func productCreatedEvent() models.ProductEvent {
+	product, _ := models.NewProduct("1", "Product 1", 10)
+	return models.NewProductEvent(models.EventProductCreated, product)
+}
+
+func TestWebhookService_CreateSubscription(t *testing.T) {
+	t.Run("Generates Secret When Omitted", func(t *testing.T) {
+		service, _, url := newTestWebhookService(t, &webhookReceiver{})
+
+		sub, err := service.CreateSubscription(&models.WebhookSubscription{URL: url})
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if sub.ID == "" || sub.Secret == "" {
+			t.Errorf("Expected generated ID and secret, got %+v", sub)
+		}
+	})
+
+	t.Run("Rejects Relative URL", func(t *testing.T) {
+		service, _, _ := newTestWebhookService(t, &webhookReceiver{})
+
+		_, err := service.CreateSubscription(&models.WebhookSubscription{URL: "/callback"})
+
+		if err != models.ErrInvalidWebhookURL {
+			t.Errorf("Expected ErrInvalidWebhookURL, got %v", err)
+		}
+	})
+
+	t.Run("Rejects Unknown Event Type", func(t *testing.T) {
+		service, _, url := newTestWebhookService(t, &webhookReceiver{})
+
+		_, err := service.CreateSubscription(&models.WebhookSubscription{URL: url, EventTypes: []models.EventType{"product.sold"}})
+
+		if err != models.ErrInvalidEventType {
+			t.Errorf("Expected ErrInvalidEventType, got %v", err)
+		}
+	})
+}
+
+func TestWebhookService_DeliverDue(t *testing.T) {
+	t.Run("Sends Signed Payload", func(t *testing.T) {
+		receiver := &webhookReceiver{}
+		service, _, url := newTestWebhookService(t, receiver)
+		sub, _ := service.CreateSubscription(&models.WebhookSubscription{URL: url, Secret: "s3cr3t"})
+		_ = service.Publish(context.Background(), productCreatedEvent())
+
+		delivered, err := service.DeliverDue(context.Background())
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if delivered != 1 {
+			t.Fatalf("Expected 1 delivery, got %d", delivered)
+		}
+		requests := receiver.received()
+		if len(requests) != 1 {
+			t.Fatalf("Expected receiver to get 1 request, got %d", len(requests))
+		}
+		req := requests[0]
+		if req.header.Get(application.WebhookEventHeader) != string(models.EventProductCreated) {
+			t.Errorf("Unexpected event header %q", req.header.Get(application.WebhookEventHeader))
+		}
+		timestamp := req.header.Get(application.WebhookTimestampHeader)
+		signature := req.header.Get(application.WebhookSignatureHeader)
+		if !application.VerifyWebhookSignature("s3cr3t", timestamp, req.body, signature) {
+			t.Errorf("Signature %q does not match payload", signature)
+		}
+
+		history, _ := service.ListDeliveries(sub.ID, 10)
+		if len(history) != 1 || history[0].Status != models.DeliverySucceeded || history[0].ResponseStatus != http.StatusOK {
+			t.Errorf("Expected a succeeded delivery in history, got %+v", history)
+		}
+	})
+
+	t.Run("Skips Subscriptions Not Interested In Event", func(t *testing.T) {
+		receiver := &webhookReceiver{}
+		service, _, url := newTestWebhookService(t, receiver)
+		_, _ = service.CreateSubscription(&models.WebhookSubscription{URL: url, EventTypes: []models.EventType{models.EventProductDeleted}})
+		_ = service.Publish(context.Background(), productCreatedEvent())
+
+		delivered, _ := service.DeliverDue(context.Background())
+
+		if delivered != 0 || len(receiver.received()) != 0 {
+			t.Errorf("Expected no deliveries, got %d", delivered)
+		}
+	})
+
+	t.Run("Republished Event Is Delivered Once", func(t *testing.T) {
+		receiver := &webhookReceiver{}
+		service, _, url := newTestWebhookService(t, receiver)
+		_, _ = service.CreateSubscription(&models.WebhookSubscription{URL: url})
+		event := productCreatedEvent()
+		_ = service.Publish(context.Background(), event)
+		_ = service.Publish(context.Background(), event)
+
+		delivered, _ := service.DeliverDue(context.Background())
+
+		if delivered != 1 {
+			t.Errorf("Expected 1 delivery, got %d", delivered)
+		}
+	})
+
+	t.Run("Retries Then Dead Letters", func(t *testing.T) {
+		receiver := &webhookReceiver{statuses: []int{500, 502, 503}}
+		service, _, url := newTestWebhookService(t, receiver)
+		sub, _ := service.CreateSubscription(&models.WebhookSubscription{URL: url})
+		_ = service.Publish(context.Background(), productCreatedEvent())
+
+		for i := 0; i < 3; i++ {
+			if _, err := service.DeliverDue(context.Background()); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+		}
+
+		history, _ := service.ListDeliveries(sub.ID, 10)
+		if len(history) != 1 {
+			t.Fatalf("Expected 1 delivery in history, got %d", len(history))
+		}
+		if history[0].Status != models.DeliveryDead || history[0].Attempts != 3 || history[0].ResponseStatus != 503 {
+			t.Errorf("Expected dead delivery after 3 attempts, got %+v", history[0])
+		}
+		if delivered, _ := service.DeliverDue(context.Background()); delivered != 0 {
+			t.Errorf("Expected dead delivery not to be retried, got %d deliveries", delivered)
+		}
+
+		retried, err := service.RetryDelivery(sub.ID, history[0].ID)
+		if err != nil || retried.Status != models.DeliveryPending {
+			t.Fatalf("Expected delivery to be rescheduled, got %+v (%v)", retried, err)
+		}
+		if delivered, _ := service.DeliverDue(context.Background()); delivered != 1 {
+			t.Errorf("Expected rescheduled delivery to be sent, got %d deliveries", delivered)
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Escopos concedidos a chaves de API e tokens. ScopeAdmin concede todos os demais, além do gerenciamento
+// de chaves de API e de webhooks.
+const (
+	ScopeProductsRead  = "products:read"
+	ScopeProductsWrite = "products:write"
+	ScopeAdmin         = "admin"
+)
+
+// Scopes são os escopos conhecidos, na ordem em que são documentados.
+var Scopes = []string{ScopeProductsRead, ScopeProductsWrite, ScopeAdmin}
+
+// APIKeyPrefix inicia todas as chaves de API, o que facilita identificá-las em varreduras de segredos.
+const APIKeyPrefix = "psk_"
+
+// APIKey é uma chave de API emitida pelo serviço para integrações que não usam OAuth. Apenas o hash do
+// segredo é guardado: a chave completa é exibida uma única vez, na criação.
+type APIKey struct {
+	ID         string
+	Name       string
+	SecretHash string
+	Scopes     []string
+	Roles      []string
+	CreatedBy  string
+	CreatedAt  time.Time
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+// NewAPIKey valida os dados e cria uma nova chave, retornando também a chave completa, no formato
+// psk_<ID>_<segredo>.
+func NewAPIKey(name string, scopes, roles []string, expiresAt *time.Time, createdBy string) (*APIKey, string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", err
+	}
+	encodedSecret := base64.RawURLEncoding.EncodeToString(secret)
+
+	key := &APIKey{
+		ID:         NewID(),
+		Name:       strings.TrimSpace(name),
+		SecretHash: HashAPIKeySecret(encodedSecret),
+		Scopes:     scopes,
+		Roles:      roles,
+		CreatedBy:  createdBy,
+		CreatedAt:  time.Now(),
+		ExpiresAt:  expiresAt,
+	}
+	if err := key.Validate(); err != nil {
+		return nil, "", err
+	}
+	return key, APIKeyPrefix + key.ID + "_" + encodedSecret, nil
+}
+
+// Validate verifica se a chave tem um nome, ao menos um escopo conhecido, ao menos um papel e, se expira,
+// uma data de expiração posterior à criação. Os papéis são conferidos com a matriz configurada pelo serviço.
+func (k *APIKey) Validate() error {
+	if k.Name == "" || len(k.Scopes) == 0 || len(k.Roles) == 0 {
+		return ErrInvalidAPIKey
+	}
+	for _, scope := range k.Scopes {
+		if !slices.Contains(Scopes, scope) {
+			return ErrInvalidAPIKey
+		}
+	}
+	if k.ExpiresAt != nil && !k.ExpiresAt.After(k.CreatedAt) {
+		return ErrInvalidAPIKey
+	}
+	return nil
+}
+
+// IsActive informa se a chave pode autenticar requisições no instante informado: não foi revogada nem expirou.
+func (k *APIKey) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// ParseAPIKey separa o ID e o segredo de uma chave completa.
+func ParseAPIKey(key string) (id, secret string, ok bool) {
+	rest, ok := strings.CutPrefix(key, APIKeyPrefix)
+	if !ok {
+		return "", "", false
+	}
+	id, secret, ok = strings.Cut(rest, "_")
+	return id, secret, ok && id != "" && secret != ""
+}
+
+// HashAPIKeySecret retorna o hash SHA-256, em hexadecimal, do segredo de uma chave. Como os segredos são
+// aleatórios, com 256 bits, um hash rápido basta.
+func HashAPIKeySecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
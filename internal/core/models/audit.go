@@ -0,0 +1,161 @@
+package models
+
+import (
+	"time"
+)
+
+// AuditOperation identifica o tipo de mutação registrada no log de auditoria.
+type AuditOperation string
+
+// Operações registradas no log de auditoria.
+const (
+	AuditOperationCreate AuditOperation = "create"
+	AuditOperationUpdate AuditOperation = "update"
+	AuditOperationDelete AuditOperation = "delete"
+)
+
+// IsValid informa se a operação é conhecida.
+func (o AuditOperation) IsValid() bool {
+	switch o {
+	case AuditOperationCreate, AuditOperationUpdate, AuditOperationDelete:
+		return true
+	}
+	return false
+}
+
+// FieldChange descreve a alteração de um campo do produto.
+type FieldChange struct {
+	Field string
+	From  any
+	To    any
+}
+
+// AuditEntry é um registro imutável de uma mutação de produto: quem a fez, em qual requisição,
+// quando, e o estado do produto antes e depois (nulo na criação e na exclusão, respectivamente).
+type AuditEntry struct {
+	ID        string
+	ProductID string
+	Operation AuditOperation
+	Actor     string
+	RequestID string
+	Timestamp time.Time
+	Before    *Product
+	After     *Product
+	Changes   []FieldChange
+}
+
+// NewAuditEntry cria um registro de auditoria, calculando a diferença entre os estados informados.
+func NewAuditEntry(operation AuditOperation, actor, requestID string, before, after *Product) *AuditEntry {
+	entry := &AuditEntry{
+		ID:        NewID(),
+		Operation: operation,
+		Actor:     actor,
+		RequestID: requestID,
+		Timestamp: time.Now(),
+		Before:    copyProduct(before),
+		After:     copyProduct(after),
+		Changes:   DiffProducts(before, after),
+	}
+	if before != nil {
+		entry.ProductID = before.ID
+	} else if after != nil {
+		entry.ProductID = after.ID
+	}
+	return entry
+}
+
+// Mutation identifica quem fez uma mutação de produto e em qual requisição. Os repositórios gravam o
+// registro de auditoria da mutação na mesma transação que a altera.
+type Mutation struct {
+	Actor     string
+	RequestID string
+}
+
+// NewEventAuditEntry cria o registro de auditoria da mutação que emitiu o evento, a partir do estado do
+// produto antes dela (nulo na criação). O registro compartilha o instante do evento.
+func NewEventAuditEntry(event ProductEvent, before *Product, mutation Mutation) *AuditEntry {
+	operation, after := AuditOperationUpdate, event.Product
+	switch event.Type {
+	case EventProductCreated:
+		operation = AuditOperationCreate
+	case EventProductDeleted:
+		operation, after = AuditOperationDelete, nil
+	}
+	entry := NewAuditEntry(operation, mutation.Actor, mutation.RequestID, before, after)
+	entry.Timestamp = event.OccurredAt
+	return entry
+}
+
+// auditedFields lista, em ordem, os campos do produto comparados pelo log de auditoria.
+var auditedFields = []struct {
+	name  string
+	value func(p *Product) any
+}{
+	{"ID", func(p *Product) any { return p.ID }},
+	{"Name", func(p *Product) any { return p.Name }},
+	{"Price", func(p *Product) any { return p.Price }},
+	{"CreatedAt", func(p *Product) any { return p.CreatedAt }},
+}
+
+// DiffProducts lista os campos que diferem entre dois estados de um produto.
+// Um estado nulo é tratado como ausência do produto: todos os campos do outro estado são listados.
+func DiffProducts(before, after *Product) []FieldChange {
+	changes := []FieldChange{}
+	for _, field := range auditedFields {
+		var from, to any
+		if before != nil {
+			from = field.value(before)
+		}
+		if after != nil {
+			to = field.value(after)
+		}
+		if before != nil && after != nil && equalFieldValues(from, to) {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: field.name, From: from, To: to})
+	}
+	return changes
+}
+
+func equalFieldValues(a, b any) bool {
+	if ta, ok := a.(time.Time); ok {
+		tb, ok := b.(time.Time)
+		return ok && ta.Equal(tb)
+	}
+	return a == b
+}
+
+func copyProduct(p *Product) *Product {
+	if p == nil {
+		return nil
+	}
+	c := *p
+	return &c
+}
+
+// AuditFilter restringe a consulta ao log de auditoria. Campos vazios (ou zero) não filtram.
+type AuditFilter struct {
+	ProductID string
+	Actor     string
+	Operation AuditOperation
+	From      time.Time
+	To        time.Time
+	Limit     int
+}
+
+// Matches informa se o registro atende ao filtro, desconsiderando o limite.
+func (f AuditFilter) Matches(entry *AuditEntry) bool {
+	switch {
+	case f.ProductID != "" && entry.ProductID != f.ProductID:
+		return false
+	case f.Actor != "" && entry.Actor != f.Actor:
+		return false
+	case f.Operation != "" && entry.Operation != f.Operation:
+		return false
+	case !f.From.IsZero() && entry.Timestamp.Before(f.From):
+		return false
+	case !f.To.IsZero() && !entry.Timestamp.Before(f.To):
+		return false
+	}
+	return true
+}
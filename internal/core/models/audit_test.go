@@ -0,0 +1,55 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+func TestDiffProducts(t *testing.T) {
+	t.Run("Lists Only Changed Fields", func(t *testing.T) {
+		before, _ := models.NewProduct("1", "Product 1", 10)
+		after := *before
+		after.Price = 12.5
+
+		changes := models.DiffProducts(before, &after)
+
+		if len(changes) != 1 {
+			t.Fatalf("Expected 1 change, got %+v", changes)
+		}
+		if changes[0].Field != "Price" || changes[0].From != 10.0 || changes[0].To != 12.5 {
+			t.Errorf("Unexpected change: %+v", changes[0])
+		}
+	})
+
+	t.Run("Creation Lists Every Field", func(t *testing.T) {
+		after, _ := models.NewProduct("1", "Product 1", 10)
+
+		changes := models.DiffProducts(nil, after)
+
+		if len(changes) != 4 {
+			t.Fatalf("Expected 4 changes, got %+v", changes)
+		}
+		for _, change := range changes {
+			if change.From != nil {
+				t.Errorf("Expected no previous value for %s, got %v", change.Field, change.From)
+			}
+		}
+	})
+}
+
+func TestNewAuditEntry(t *testing.T) {
+	t.Run("Snapshots Product States", func(t *testing.T) {
+		product, _ := models.NewProduct("1", "Product 1", 10)
+
+		entry := models.NewAuditEntry(models.AuditOperationDelete, "alice", "req-1", product, nil)
+		product.Name = "Changed Afterwards"
+
+		if entry.ProductID != "1" || entry.Actor != "alice" || entry.RequestID != "req-1" {
+			t.Errorf("Unexpected entry metadata: %+v", entry)
+		}
+		if entry.Before.Name != "Product 1" || entry.After != nil {
+			t.Errorf("Expected entry to keep a copy of the previous state, got before=%+v after=%+v", entry.Before, entry.After)
+		}
+	})
+}
@@ -0,0 +1,255 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Permission é uma operação sobre o catálogo de produtos que pode ser concedida a um papel.
+type Permission string
+
+// Permissões das operações de produtos e, em PermissionAPIKeysManage, do gerenciamento de chaves de API e
+// webhooks.
+const (
+	PermissionProductsRead   Permission = "products:read"
+	PermissionProductsCreate Permission = "products:create"
+	PermissionProductsUpdate Permission = "products:update"
+	PermissionProductsDelete Permission = "products:delete"
+	PermissionProductsImport Permission = "products:import"
+	PermissionAPIKeysManage  Permission = "api-keys:manage"
+)
+
+// Permissions são as permissões conhecidas.
+var Permissions = []Permission{
+	PermissionProductsRead, PermissionProductsCreate, PermissionProductsUpdate, PermissionProductsDelete, PermissionProductsImport,
+	PermissionAPIKeysManage,
+}
+
+// Papéis predefinidos. A matriz de papéis pode ser substituída por configuração, inclusive com outros papéis.
+const (
+	RoleViewer         = "viewer"
+	RoleEditor         = "editor"
+	RolePricingManager = "pricing-manager"
+	RoleAdmin          = "admin"
+)
+
+// RolePermissions associa cada papel às permissões que ele concede.
+type RolePermissions map[string][]Permission
+
+// DefaultRolePermissions retorna a matriz padrão: viewer lê o catálogo, pricing-manager também atualiza
+// produtos existentes, editor também cria, remove e importa, e admin tem todas as permissões, inclusive a
+// de gerenciar chaves de API e webhooks.
+func DefaultRolePermissions() RolePermissions {
+	return RolePermissions{
+		RoleViewer:         {PermissionProductsRead},
+		RolePricingManager: {PermissionProductsRead, PermissionProductsUpdate},
+		RoleEditor:         {PermissionProductsRead, PermissionProductsCreate, PermissionProductsUpdate, PermissionProductsDelete, PermissionProductsImport},
+		RoleAdmin:          slices.Clone(Permissions),
+	}
+}
+
+// Validate verifica se a matriz tem ao menos um papel e apenas permissões conhecidas.
+func (m RolePermissions) Validate() error {
+	if len(m) == 0 {
+		return fmt.Errorf("%w: no roles defined", ErrInvalidRolePermissions)
+	}
+	for role, permissions := range m {
+		if role == "" {
+			return fmt.Errorf("%w: empty role name", ErrInvalidRolePermissions)
+		}
+		for _, permission := range permissions {
+			if !slices.Contains(Permissions, permission) {
+				return fmt.Errorf("%w: role %q has unknown permission %q", ErrInvalidRolePermissions, role, permission)
+			}
+		}
+	}
+	return nil
+}
+
+// Allows informa se algum dos papéis concede a permissão. Papéis ausentes da matriz não concedem nada.
+func (m RolePermissions) Allows(roles []string, permission Permission) bool {
+	for _, role := range roles {
+		if slices.Contains(m[role], permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// Roles retorna os nomes dos papéis da matriz, em ordem alfabética.
+func (m RolePermissions) Roles() []string {
+	roles := make([]string, 0, len(m))
+	for role := range m {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// ProductFieldPolicy restringe, por papel, os campos de produtos que o papel pode alterar (Write) e ler
+// (Read). Um papel ausente de um dos mapas não tem restrições nesse acesso. As restrições de um papel só
+// contam nas operações cuja permissão ele concede: com vários papéis, valem os campos liberados por algum
+// dos papéis que concedem a operação.
+type ProductFieldPolicy struct {
+	Write map[string][]ProductField `json:"write"`
+	Read  map[string][]ProductField `json:"read"`
+}
+
+// DefaultProductFieldPolicy retorna a política padrão: editor altera apenas o nome e pricing-manager, apenas
+// o preço. Nenhum papel tem restrições de leitura.
+func DefaultProductFieldPolicy() ProductFieldPolicy {
+	return ProductFieldPolicy{
+		Write: map[string][]ProductField{
+			RoleEditor:         {ProductFieldName},
+			RolePricingManager: {ProductFieldPrice},
+		},
+	}
+}
+
+// editableProductFields são os campos que uma atualização pode mudar; os demais são imutáveis ou mantidos
+// pelo serviço.
+var editableProductFields = []ProductField{ProductFieldName, ProductFieldPrice}
+
+// Validate verifica se a política cita apenas campos conhecidos e, em Write, apenas campos editáveis.
+func (p ProductFieldPolicy) Validate() error {
+	for role, fields := range p.Write {
+		for _, field := range fields {
+			if !slices.Contains(editableProductFields, field) {
+				return fmt.Errorf("%w: role %q cannot be granted writes to %q", ErrInvalidRolePermissions, role, field)
+			}
+		}
+	}
+	for role, fields := range p.Read {
+		for _, field := range fields {
+			if !slices.Contains(ProductFields, field) {
+				return fmt.Errorf("%w: role %q has unknown readable field %q", ErrInvalidRolePermissions, role, field)
+			}
+		}
+	}
+	return nil
+}
+
+// WritableFields retorna os campos editáveis que os papéis permitem alterar na operação da permissão.
+func (p ProductFieldPolicy) WritableFields(matrix RolePermissions, roles []string, permission Permission) []ProductField {
+	return grantedFields(p.Write, editableProductFields, matrix, roles, permission)
+}
+
+// ReadableFields retorna os campos que os papéis permitem ler, sempre incluindo o ID, que identifica o produto.
+func (p ProductFieldPolicy) ReadableFields(matrix RolePermissions, roles []string) []ProductField {
+	fields := grantedFields(p.Read, ProductFields, matrix, roles, PermissionProductsRead)
+	if len(fields) > 0 && !slices.Contains(fields, ProductFieldID) {
+		fields = append([]ProductField{ProductFieldID}, fields...)
+	}
+	return fields
+}
+
+// grantedFields une os campos liberados pelos papéis que concedem a permissão, na ordem de all. Um papel
+// sem restrições na política libera todos os campos.
+func grantedFields(policy map[string][]ProductField, all []ProductField, matrix RolePermissions, roles []string, permission Permission) []ProductField {
+	var granted []ProductField
+	for _, field := range all {
+		for _, role := range roles {
+			if !matrix.Allows([]string{role}, permission) {
+				continue
+			}
+			if restricted, ok := policy[role]; !ok || slices.Contains(restricted, field) {
+				granted = append(granted, field)
+				break
+			}
+		}
+	}
+	return granted
+}
+
+// ChangedProductFields lista os campos editáveis que diferem entre dois estados do produto.
+func ChangedProductFields(before, after *Product) []ProductField {
+	var changed []ProductField
+	if before.Name != after.Name {
+		changed = append(changed, ProductFieldName)
+	}
+	if before.Price != after.Price {
+		changed = append(changed, ProductFieldPrice)
+	}
+	return changed
+}
+
+// FieldAccess é o tipo de acesso a campos de produtos negado por um ForbiddenFieldsError.
+type FieldAccess string
+
+// Acessos a campos de produtos.
+const (
+	FieldAccessRead  FieldAccess = "read"
+	FieldAccessWrite FieldAccess = "write"
+)
+
+// ForbiddenFieldsError lista os campos de produtos que os papéis do principal não permitem ler ou alterar.
+// errors.Is o reconhece como ErrForbiddenFields.
+type ForbiddenFieldsError struct {
+	Access FieldAccess
+	Fields []ProductField
+}
+
+// NewForbiddenFieldsError retorna um ForbiddenFieldsError com os campos de requested ausentes de allowed,
+// ou nil se todos são permitidos.
+func NewForbiddenFieldsError(access FieldAccess, requested, allowed []ProductField) error {
+	var forbidden []ProductField
+	for _, field := range requested {
+		if !slices.Contains(allowed, field) && !slices.Contains(forbidden, field) {
+			forbidden = append(forbidden, field)
+		}
+	}
+	if len(forbidden) == 0 {
+		return nil
+	}
+	return &ForbiddenFieldsError{Access: access, Fields: forbidden}
+}
+
+func (e *ForbiddenFieldsError) Error() string {
+	names := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		names[i] = string(field)
+	}
+	return fmt.Sprintf("%v: cannot %s %s", ErrForbiddenFields, e.Access, strings.Join(names, ", "))
+}
+
+func (e *ForbiddenFieldsError) Unwrap() error {
+	return ErrForbiddenFields
+}
+
+// RedactProduct retorna uma cópia do produto com os campos fora de readable zerados, como nas projeções.
+func RedactProduct(product *Product, readable []ProductField) *Product {
+	redacted := *product
+	for _, field := range ProductFields {
+		if slices.Contains(readable, field) {
+			continue
+		}
+		switch field {
+		case ProductFieldName:
+			redacted.Name = ""
+		case ProductFieldPrice:
+			redacted.Price = 0
+		case ProductFieldCreatedAt:
+			redacted.CreatedAt = time.Time{}
+		case ProductFieldUpdatedAt:
+			redacted.UpdatedAt = time.Time{}
+		}
+	}
+	return &redacted
+}
+
+// RestoreUnreadableFields retorna uma cópia do produto com os campos editáveis fora de readable copiados
+// de current. É o inverso de RedactProduct: o principal recebe zerados os campos que não pode ler, e
+// reenviá-los assim em uma atualização não os altera.
+func RestoreUnreadableFields(product, current *Product, readable []ProductField) *Product {
+	restored := *product
+	if !slices.Contains(readable, ProductFieldName) {
+		restored.Name = current.Name
+	}
+	if !slices.Contains(readable, ProductFieldPrice) {
+		restored.Price = current.Price
+	}
+	return &restored
+}
@@ -0,0 +1,73 @@
+package models_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+func TestRolePermissions(t *testing.T) {
+	roles := models.DefaultRolePermissions()
+	if err := roles.Validate(); err != nil {
+		t.Fatalf("Expected the default matrix to be valid, got %v", err)
+	}
+	if !roles.Allows([]string{"unknown", models.RoleEditor}, models.PermissionProductsDelete) {
+		t.Error("Expected the editor role to grant products:delete")
+	}
+	if roles.Allows([]string{models.RoleViewer}, models.PermissionProductsUpdate) || roles.Allows(nil, models.PermissionProductsRead) {
+		t.Error("Expected only the granted permissions to be allowed")
+	}
+
+	for name, invalid := range map[string]models.RolePermissions{
+		"Empty":              {},
+		"Unknown Permission": {"auditor": {"products:approve"}},
+		"Empty Role":         {"": {models.PermissionProductsRead}},
+	} {
+		if err := invalid.Validate(); !errors.Is(err, models.ErrInvalidRolePermissions) {
+			t.Errorf("%s: expected ErrInvalidRolePermissions, got %v", name, err)
+		}
+	}
+}
+
+func TestProductFieldPolicy(t *testing.T) {
+	roles := models.DefaultRolePermissions()
+	policy := models.DefaultProductFieldPolicy()
+	if err := policy.Validate(); err != nil {
+		t.Fatalf("Expected the default policy to be valid, got %v", err)
+	}
+
+	writable := policy.WritableFields(roles, []string{models.RoleEditor, models.RolePricingManager}, models.PermissionProductsUpdate)
+	if len(writable) != 2 {
+		t.Errorf("Expected the roles to combine their writable fields, got %v", writable)
+	}
+	if writable := policy.WritableFields(roles, []string{models.RoleAdmin}, models.PermissionProductsUpdate); len(writable) != 2 {
+		t.Errorf("Expected a role absent from the policy to write every editable field, got %v", writable)
+	}
+	if writable := policy.WritableFields(roles, []string{models.RoleViewer}, models.PermissionProductsUpdate); len(writable) != 0 {
+		t.Errorf("Expected no writable fields without the permission, got %v", writable)
+	}
+
+	changed := models.ChangedProductFields(&models.Product{ID: "1", Name: "Mouse", Price: 10}, &models.Product{ID: "1", Name: "Mouse", Price: 12})
+	err := models.NewForbiddenFieldsError(models.FieldAccessWrite, changed, policy.WritableFields(roles, []string{models.RoleEditor}, models.PermissionProductsUpdate))
+	var forbidden *models.ForbiddenFieldsError
+	if !errors.As(err, &forbidden) || !errors.Is(err, models.ErrForbiddenFields) || len(forbidden.Fields) != 1 || forbidden.Fields[0] != models.ProductFieldPrice {
+		t.Errorf("Expected the price change to be forbidden for an editor, got %v", err)
+	}
+
+	restricted := models.ProductFieldPolicy{Read: map[string][]models.ProductField{models.RoleViewer: {models.ProductFieldName}}}
+	readable := restricted.ReadableFields(roles, []string{models.RoleViewer})
+	redacted := models.RedactProduct(&models.Product{ID: "1", Name: "Mouse", Price: 10}, readable)
+	if redacted.ID != "1" || redacted.Name != "Mouse" || redacted.Price != 0 {
+		t.Errorf("Expected only the ID and the name to remain, got %+v", redacted)
+	}
+
+	for name, invalid := range map[string]models.ProductFieldPolicy{
+		"Non Editable Field": {Write: map[string][]models.ProductField{models.RoleEditor: {models.ProductFieldID}}},
+		"Unknown Field":      {Read: map[string][]models.ProductField{models.RoleViewer: {"Cost"}}},
+	} {
+		if err := invalid.Validate(); !errors.Is(err, models.ErrInvalidRolePermissions) {
+			t.Errorf("%s: expected ErrInvalidRolePermissions, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// ProductChange é um registro do feed de alterações, numerado por uma sequência monotonicamente crescente.
+// Exclusões são registradas como tombstones: Deleted é verdadeiro e Product é nulo.
+type ProductChange struct {
+	Sequence  int64
+	Type      EventType
+	ProductID string
+	Product   *Product
+	Deleted   bool
+	ChangedAt time.Time
+}
+
+// NewProductChange cria o registro do feed correspondente ao evento, com a sequência informada.
+func NewProductChange(sequence int64, event ProductEvent) *ProductChange {
+	change := &ProductChange{
+		Sequence:  sequence,
+		Type:      event.Type,
+		ProductID: event.ProductID,
+		Product:   event.Product,
+		ChangedAt: event.OccurredAt,
+	}
+	if event.Type == EventProductDeleted {
+		change.Product = nil
+		change.Deleted = true
+	}
+	return change
+}
@@ -2,9 +2,118 @@ package models
 
 import "errors"
 
+// Erros na leitura do corpo das requisições.
+var (
+	ErrInvalidRequestBody     = errors.New("invalid request body")
+	ErrRequestBodyTooLarge    = errors.New("request body too large")
+	ErrUnsupportedContentType = errors.New("request content type must be JSON, XML, CSV or MessagePack")
+)
+
+// Erros da compressão dos corpos de requisição.
+var (
+	ErrUnsupportedContentEncoding = errors.New("request content encoding must be gzip or identity")
+	ErrDecompressionLimitExceeded = errors.New("decompressed request body exceeds the allowed limits")
+)
+
+// Erros da autenticação das requisições.
+var (
+	ErrUnauthenticated     = errors.New("authentication is required")
+	ErrInvalidCredentials  = errors.New("invalid credentials")
+	ErrUnsupportedAuthType = errors.New("unsupported authorization scheme")
+	ErrInsufficientScope   = errors.New("the credentials do not grant the scope required by this operation")
+)
+
+// Erros da autorização por papéis.
+var (
+	ErrForbidden              = errors.New("the principal's roles do not grant the permission required by this operation")
+	ErrForbiddenFields        = errors.New("the principal's roles do not allow access to these product fields")
+	ErrInvalidRolePermissions = errors.New("invalid role permissions")
+)
+
+// Erros do gerenciamento de chaves de API.
+var (
+	ErrAPIKeyNotFound = errors.New("API key not found")
+	ErrInvalidAPIKey  = errors.New("API keys need a name, at least one known scope, at least one known role and, if they expire, a future expiration date")
+)
+
+// ErrNotAcceptable indica que nenhum dos formatos de resposta disponíveis é aceito pelo cliente.
+var ErrNotAcceptable = errors.New("none of the available response formats is acceptable")
+
 // Erros comuns de domínio para Product.
 var (
 	ErrProductNotFound      = errors.New("product not found")
 	ErrInvalidProductID     = errors.New("invalid product ID")
 	ErrProductAlreadyExists = errors.New("product with this ID already exists")
+	ErrProductIDMismatch    = errors.New("product ID in path does not match ID in body")
+	ErrProductModified      = errors.New("product was modified concurrently")
+)
+
+// Erros de domínio para webhooks.
+var (
+	ErrWebhookNotFound         = errors.New("webhook subscription not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrInvalidWebhookURL       = errors.New("webhook URL must be an absolute http or https URL")
+	ErrInvalidWebhookSecret    = errors.New("webhook secret cannot be empty")
+	ErrInvalidEventType        = errors.New("invalid event type")
+	ErrInvalidDeliveryQuery    = errors.New("limit must be between 1 and 500")
+)
+
+// ErrInvalidChangeID indica um identificador de notificação de alteração (Last-Event-ID) malformado.
+var ErrInvalidChangeID = errors.New("invalid change notification ID")
+
+// ErrInvalidChangeQuery indica parâmetros inválidos na consulta ao feed de alterações.
+var ErrInvalidChangeQuery = errors.New("since must be a non-negative integer and limit must be between 1 and 1000")
+
+// ErrInvalidAuditQuery indica parâmetros inválidos na consulta ao log de auditoria.
+var ErrInvalidAuditQuery = errors.New("invalid audit query: use RFC 3339 timestamps, a known operation and a limit between 1 and 1000")
+
+// Erros do histórico de revisões de produtos.
+var (
+	ErrInvalidAsOf          = errors.New("as_of must be an RFC 3339 timestamp")
+	ErrHistoryNotConfigured = errors.New("product history is not available")
+)
+
+// Erros da aplicação de patches parciais em produtos.
+var (
+	ErrInvalidPatch          = errors.New("invalid patch document")
+	ErrPatchConflict         = errors.New("patch cannot be applied to the current product")
+	ErrUnsupportedPatchType  = errors.New("unsupported patch media type")
+	ErrImmutableProductField = errors.New("product ID and CreatedAt cannot be changed")
+)
+
+// Erros do controle de idempotência das requisições.
+var (
+	ErrInvalidIdempotencyKey      = errors.New("Idempotency-Key must have between 1 and 255 characters")
+	ErrIdempotencyKeyReused       = errors.New("Idempotency-Key was already used with a different request")
+	ErrIdempotencyRequestInFlight = errors.New("a request with this Idempotency-Key is still being processed")
+	ErrIdempotencyKeyNotFound     = errors.New("idempotency key not found")
+)
+
+// ErrInvalidProductQuery indica filtros ou agrupamento inválidos na consulta de produtos.
+var ErrInvalidProductQuery = errors.New("invalid product query: use non-negative prices with min_price <= max_price, RFC 3339 timestamps with created_from < created_to and group_by day or month")
+
+// Erros das representações parciais de produtos (?fields= e ?expand=).
+var (
+	ErrInvalidProductFields    = errors.New("invalid product fields")
+	ErrInvalidProductExpansion = errors.New("invalid product expansion")
+)
+
+// ErrInvalidExportFormat indica um formato de exportação de produtos desconhecido.
+var ErrInvalidExportFormat = errors.New("format must be csv, ndjson or json")
+
+// Erros da importação de produtos em lote.
+var (
+	ErrInvalidImportOptions = errors.New("invalid import options: use mode create or upsert, a single-character delimiter, decimal dot or comma and distinct column names")
+	ErrInvalidImportFile    = errors.New("invalid import file")
+	ErrImportNotConfigured  = errors.New("product import is not available")
+)
+
+// Erros dos jobs assíncronos.
+var (
+	ErrJobNotFound        = errors.New("job not found")
+	ErrJobAlreadyFinished = errors.New("job has already finished")
+	ErrUnknownJobType     = errors.New("unknown job type")
+	ErrJobsNotConfigured  = errors.New("asynchronous jobs are not available")
+	ErrJobCancelled       = errors.New("job cancelled")
+	ErrJobInterrupted     = errors.New("job interrupted by shutdown")
 )
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// EventType identifica o tipo de alteração ocorrida em um produto.
+type EventType string
+
+// Tipos de eventos emitidos pelas mutações de produtos.
+const (
+	EventProductCreated EventType = "product.created"
+	EventProductUpdated EventType = "product.updated"
+	EventProductDeleted EventType = "product.deleted"
+)
+
+// IsValid informa se o tipo de evento é conhecido.
+func (t EventType) IsValid() bool {
+	switch t {
+	case EventProductCreated, EventProductUpdated, EventProductDeleted:
+		return true
+	}
+	return false
+}
+
+// ProductEvent representa uma alteração ocorrida em um produto.
+// Nas exclusões, Product contém o último estado conhecido do produto removido.
+type ProductEvent struct {
+	ID         string
+	Type       EventType
+	ProductID  string
+	Product    *Product
+	OccurredAt time.Time
+}
+
+// NewProductEvent cria um novo evento para o produto informado.
+func NewProductEvent(eventType EventType, product *Product) ProductEvent {
+	snapshot := *product
+	return ProductEvent{
+		ID:         NewID(),
+		Type:       eventType,
+		ProductID:  product.ID,
+		Product:    &snapshot,
+		OccurredAt: time.Now(),
+	}
+}
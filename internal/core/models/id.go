@@ -0,0 +1,15 @@
+package models
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// NewID gera um identificador aleatório de 128 bits codificado em hexadecimal.
+func NewID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic("models: não foi possível gerar um identificador aleatório: " + err.Error())
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"net/http"
+	"time"
+)
+
+// IdempotencyStatus indica se a requisição associada a uma chave de idempotência já foi concluída.
+type IdempotencyStatus string
+
+// Estados de uma chave de idempotência.
+const (
+	IdempotencyInProgress IdempotencyStatus = "in_progress"
+	IdempotencyCompleted  IdempotencyStatus = "completed"
+)
+
+// IdempotencyRecord associa uma chave de idempotência à requisição original e à resposta produzida.
+// Enquanto a requisição está em andamento, ExpiresAt marca o fim da reserva da chave, permitindo que
+// outra tentativa a assuma caso o processo tenha sido interrompido; após a conclusão, marca o fim do TTL.
+type IdempotencyRecord struct {
+	Key             string
+	Fingerprint     string
+	Status          IdempotencyStatus
+	ResponseStatus  int
+	ResponseHeaders http.Header
+	ResponseBody    []byte
+	CreatedAt       time.Time
+	ExpiresAt       time.Time
+}
+
+// IsExpired informa se o registro já pode ser descartado ou substituído no instante informado.
+func (r *IdempotencyRecord) IsExpired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// JobType identifica a operação executada por um job assíncrono.
+type JobType string
+
+// JobStatus indica a situação de um job assíncrono.
+type JobStatus string
+
+// Situações possíveis de um job. Succeeded, Failed e Cancelled são finais.
+const (
+	JobQueued    JobStatus = "queued"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+	JobCancelled JobStatus = "cancelled"
+)
+
+// IsFinal informa se o job já terminou e não muda mais de situação.
+func (s JobStatus) IsFinal() bool {
+	switch s {
+	case JobSucceeded, JobFailed, JobCancelled:
+		return true
+	}
+	return false
+}
+
+// JobProgress indica quantos itens o job já processou. Total é zero enquanto for desconhecido.
+type JobProgress struct {
+	Processed int
+	Total     int
+}
+
+// Job é uma operação de longa duração executada em segundo plano.
+// Parameters são as opções informadas na submissão e Input, os dados de entrada (como um arquivo CSV);
+// Result e Error trazem o resultado ao final. Enquanto o job está em execução, LeaseExpiresAt marca o fim
+// da reserva do worker, renovada periodicamente; se o processo for interrompido, o job volta a ser
+// reservado após esse instante. Attempts conta as reservas.
+type Job struct {
+	ID              string
+	Type            JobType
+	Status          JobStatus
+	Parameters      json.RawMessage
+	Input           []byte
+	Progress        JobProgress
+	Result          json.RawMessage
+	Error           string
+	Actor           string
+	RequestID       string
+	Attempts        int
+	CancelRequested bool
+	LeaseExpiresAt  *time.Time
+	CreatedAt       time.Time
+	StartedAt       *time.Time
+	FinishedAt      *time.Time
+}
+
+// NewJob cria um job na fila, submetido pelo autor e pela requisição informados.
+func NewJob(jobType JobType, parameters json.RawMessage, input []byte, actor, requestID string) *Job {
+	return &Job{
+		ID:         NewID(),
+		Type:       jobType,
+		Status:     JobQueued,
+		Parameters: parameters,
+		Input:      input,
+		Actor:      actor,
+		RequestID:  requestID,
+		CreatedAt:  time.Now(),
+	}
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// OutboxMessage representa um evento gravado no outbox, aguardando entrega.
+type OutboxMessage struct {
+	ID            int64
+	Event         ProductEvent
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+}
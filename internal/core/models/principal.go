@@ -0,0 +1,32 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// AuthMethod identifica como o principal de uma requisição foi autenticado.
+type AuthMethod string
+
+// Métodos de autenticação aceitos pela API.
+const (
+	AuthMethodJWT    AuthMethod = "jwt"
+	AuthMethodAPIKey AuthMethod = "api-key"
+)
+
+// Principal é a identidade autenticada de uma requisição. Subject é o identificador estável do cliente
+// (a claim sub de um JWT) e também identifica o autor das mutações no log de auditoria. Os escopos limitam
+// o que a credencial pode fazer; os papéis, o que o principal pode fazer no catálogo.
+type Principal struct {
+	Subject   string
+	Method    AuthMethod
+	Issuer    string
+	Scopes    []string
+	Roles     []string
+	ExpiresAt time.Time
+}
+
+// HasScope informa se o principal recebeu o escopo informado ou ScopeAdmin.
+func (p *Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope) || slices.Contains(p.Scopes, ScopeAdmin)
+}
@@ -10,6 +10,7 @@ type Product struct {
 	Name      string
 	Price     float64
 	CreatedAt time.Time
+	UpdatedAt time.Time
 }
 
 func NewProduct(id, name string, price float64) (*Product, error) {
@@ -23,9 +24,19 @@ func NewProduct(id, name string, price float64) (*Product, error) {
 		Name:      name,
 		Price:     price,
 		CreatedAt: now,
+		UpdatedAt: now,
 	}, nil
 }
 func (p Product) String() string {
 	return fmt.Sprintf("Product(ID: %s, Name: %s, Price: %.2f, CreatedAt: %s)",
 		p.ID, p.Name, p.Price, p.CreatedAt.Format(time.RFC3339))
 }
+
+// ProductModified informa se o produto mudou entre o estado esperado e o atual: se foi criado ou excluído
+// (um dos estados é nulo) ou se o nome ou o preço foram alterados.
+func ProductModified(expected, current *Product) bool {
+	if expected == nil || current == nil {
+		return expected != current
+	}
+	return len(ChangedProductFields(expected, current)) > 0
+}
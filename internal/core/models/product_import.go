@@ -0,0 +1,69 @@
+package models
+
+// ImportMode define como a importação em lote trata os produtos que já existem.
+type ImportMode string
+
+// Modos de importação suportados.
+const (
+	ImportModeCreate ImportMode = "create" // Apenas cria; IDs já existentes são rejeitados.
+	ImportModeUpsert ImportMode = "upsert" // Cria os novos e atualiza nome e preço dos existentes.
+)
+
+// IsValid informa se o modo de importação é conhecido.
+func (m ImportMode) IsValid() bool {
+	switch m {
+	case ImportModeCreate, ImportModeUpsert:
+		return true
+	}
+	return false
+}
+
+// MaxImportReportErrors limita a quantidade de erros detalhados no relatório de importação;
+// os demais são apenas contados em ErrorCount.
+const MaxImportReportErrors = 1000
+
+// ImportRowError descreve um problema em uma linha do arquivo importado. Row é o número da linha no
+// arquivo, contando o cabeçalho como linha 1, e Field é a coluna do produto afetada, quando houver.
+type ImportRowError struct {
+	Row     int
+	Field   string
+	Message string
+}
+
+// ImportReport resume a validação e, se aplicada, o resultado de uma importação em lote.
+// A importação é tudo ou nada: Applied só é verdadeiro quando nenhuma linha tem erros e o modo
+// não é de simulação. Created e Updated trazem o que foi (ou, na simulação, seria) gravado.
+type ImportReport struct {
+	Mode       ImportMode
+	DryRun     bool
+	Applied    bool
+	TotalRows  int
+	ValidRows  int
+	Created    int
+	Updated    int
+	ErrorCount int
+	Errors     []ImportRowError
+}
+
+// NewImportReport cria um relatório vazio para o modo informado.
+func NewImportReport(mode ImportMode, dryRun bool) *ImportReport {
+	return &ImportReport{
+		Mode:   mode,
+		DryRun: dryRun,
+		Errors: []ImportRowError{},
+	}
+}
+
+// AddError registra um erro de linha, guardando os detalhes apenas dos primeiros MaxImportReportErrors.
+func (r *ImportReport) AddError(row int, field, message string) {
+	r.ErrorCount++
+	if len(r.Errors) < MaxImportReportErrors {
+		r.Errors = append(r.Errors, ImportRowError{Row: row, Field: field, Message: message})
+	}
+}
+
+// ImportedProduct é o resultado da gravação de um produto importado. Before é nulo quando o produto foi criado.
+type ImportedProduct struct {
+	Before *Product
+	After  *Product
+}
@@ -0,0 +1,231 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ProductFilter seleciona produtos nas listagens e estatísticas. Campos vazios não restringem o resultado.
+// O intervalo de criação é fechado em CreatedFrom e aberto em CreatedTo.
+type ProductFilter struct {
+	NameContains string // Trecho do nome, sem diferenciar maiúsculas de minúsculas.
+	MinPrice     *float64
+	MaxPrice     *float64
+	CreatedFrom  time.Time
+	CreatedTo    time.Time
+}
+
+// ProductField identifica um campo de produto pelo nome no modelo de domínio.
+type ProductField string
+
+const (
+	ProductFieldID        ProductField = "ID"
+	ProductFieldName      ProductField = "Name"
+	ProductFieldPrice     ProductField = "Price"
+	ProductFieldCreatedAt ProductField = "CreatedAt"
+	ProductFieldUpdatedAt ProductField = "UpdatedAt"
+)
+
+// ProductFields lista os campos de produto na ordem do modelo.
+var ProductFields = []ProductField{ProductFieldID, ProductFieldName, ProductFieldPrice, ProductFieldCreatedAt, ProductFieldUpdatedAt}
+
+// ProductProjection seleciona os campos de produto que precisam ser lidos do armazenamento. Uma projeção
+// vazia inclui todos os campos, e o ID é sempre lido, pois identifica o produto. Os adaptadores podem
+// preencher mais campos que os pedidos; os demais ficam com o valor zero.
+type ProductProjection []ProductField
+
+// Includes informa se o campo faz parte da projeção.
+func (p ProductProjection) Includes(field ProductField) bool {
+	return len(p) == 0 || field == ProductFieldID || slices.Contains(p, field)
+}
+
+// Validate verifica se todos os campos da projeção existem.
+func (p ProductProjection) Validate() error {
+	for _, field := range p {
+		if !slices.Contains(ProductFields, field) {
+			return fmt.Errorf("%w: unknown field %q", ErrInvalidProductFields, field)
+		}
+	}
+	return nil
+}
+
+// Fields lista os campos de produto usados pelo filtro.
+func (f ProductFilter) Fields() []ProductField {
+	var fields []ProductField
+	if f.NameContains != "" {
+		fields = append(fields, ProductFieldName)
+	}
+	if f.MinPrice != nil || f.MaxPrice != nil {
+		fields = append(fields, ProductFieldPrice)
+	}
+	if !f.CreatedFrom.IsZero() || !f.CreatedTo.IsZero() {
+		fields = append(fields, ProductFieldCreatedAt)
+	}
+	return fields
+}
+
+// IsEmpty informa se o filtro não impõe nenhuma restrição.
+func (f ProductFilter) IsEmpty() bool {
+	return f == ProductFilter{}
+}
+
+// Validate verifica se as faixas de preço e de datas são coerentes.
+func (f ProductFilter) Validate() error {
+	if (f.MinPrice != nil && *f.MinPrice < 0) || (f.MaxPrice != nil && *f.MaxPrice < 0) {
+		return ErrInvalidProductQuery
+	}
+	if f.MinPrice != nil && f.MaxPrice != nil && *f.MinPrice > *f.MaxPrice {
+		return ErrInvalidProductQuery
+	}
+	if !f.CreatedFrom.IsZero() && !f.CreatedTo.IsZero() && !f.CreatedFrom.Before(f.CreatedTo) {
+		return ErrInvalidProductQuery
+	}
+	return nil
+}
+
+// Matches informa se o produto atende ao filtro.
+func (f ProductFilter) Matches(p *Product) bool {
+	if f.NameContains != "" && !strings.Contains(strings.ToLower(p.Name), strings.ToLower(f.NameContains)) {
+		return false
+	}
+	if f.MinPrice != nil && p.Price < *f.MinPrice {
+		return false
+	}
+	if f.MaxPrice != nil && p.Price > *f.MaxPrice {
+		return false
+	}
+	if !f.CreatedFrom.IsZero() && p.CreatedAt.Before(f.CreatedFrom) {
+		return false
+	}
+	if !f.CreatedTo.IsZero() && !p.CreatedAt.Before(f.CreatedTo) {
+		return false
+	}
+	return true
+}
+
+// StatsGrouping define o agrupamento das estatísticas de produtos pela data de criação (em UTC).
+type StatsGrouping string
+
+// Agrupamentos suportados. StatsGroupNone calcula apenas os totais.
+const (
+	StatsGroupNone  StatsGrouping = ""
+	StatsGroupDay   StatsGrouping = "day"
+	StatsGroupMonth StatsGrouping = "month"
+)
+
+// IsValid informa se o agrupamento é conhecido.
+func (g StatsGrouping) IsValid() bool {
+	switch g {
+	case StatsGroupNone, StatsGroupDay, StatsGroupMonth:
+		return true
+	}
+	return false
+}
+
+// Period retorna o rótulo do período que contém o instante: AAAA-MM-DD por dia ou AAAA-MM por mês.
+func (g StatsGrouping) Period(t time.Time) string {
+	switch g {
+	case StatsGroupDay:
+		return t.UTC().Format("2006-01-02")
+	case StatsGroupMonth:
+		return t.UTC().Format("2006-01")
+	}
+	return ""
+}
+
+// PriceSummary resume a quantidade e os preços de um conjunto de produtos.
+// Os preços são nulos quando o conjunto está vazio.
+type PriceSummary struct {
+	Count       int
+	MinPrice    *float64
+	MaxPrice    *float64
+	AvgPrice    *float64
+	MedianPrice *float64
+}
+
+// ProductStatsGroup é o resumo dos produtos criados em um período.
+type ProductStatsGroup struct {
+	Period string
+	PriceSummary
+}
+
+// ProductStats reúne o resumo de todos os produtos filtrados e, se houver agrupamento,
+// o resumo de cada período, em ordem cronológica.
+type ProductStats struct {
+	PriceSummary
+	GroupBy StatsGrouping
+	Groups  []ProductStatsGroup
+}
+
+// ProductStatsBuilder acumula produtos, um a um, para calcular suas estatísticas.
+// Apenas os preços são guardados, o que permite agregar sem copiar os produtos.
+type ProductStatsBuilder struct {
+	groupBy StatsGrouping
+	prices  []float64
+	groups  map[string][]float64
+}
+
+// NewProductStatsBuilder cria um acumulador de estatísticas com o agrupamento informado.
+func NewProductStatsBuilder(groupBy StatsGrouping) *ProductStatsBuilder {
+	return &ProductStatsBuilder{
+		groupBy: groupBy,
+		groups:  make(map[string][]float64),
+	}
+}
+
+// Add acumula um produto.
+func (b *ProductStatsBuilder) Add(p *Product) {
+	b.prices = append(b.prices, p.Price)
+	if b.groupBy != StatsGroupNone {
+		period := b.groupBy.Period(p.CreatedAt)
+		b.groups[period] = append(b.groups[period], p.Price)
+	}
+}
+
+// Build calcula as estatísticas dos produtos acumulados.
+func (b *ProductStatsBuilder) Build() *ProductStats {
+	stats := &ProductStats{
+		PriceSummary: summarizePrices(b.prices),
+		GroupBy:      b.groupBy,
+	}
+	if b.groupBy == StatsGroupNone {
+		return stats
+	}
+
+	stats.Groups = make([]ProductStatsGroup, 0, len(b.groups))
+	for period, prices := range b.groups {
+		stats.Groups = append(stats.Groups, ProductStatsGroup{Period: period, PriceSummary: summarizePrices(prices)})
+	}
+	// Os rótulos AAAA-MM-DD e AAAA-MM ordenam-se cronologicamente como texto.
+	sort.Slice(stats.Groups, func(i, j int) bool { return stats.Groups[i].Period < stats.Groups[j].Period })
+	return stats
+}
+
+// summarizePrices calcula o resumo dos preços; a mediana de uma quantidade par é a média dos dois valores centrais.
+func summarizePrices(prices []float64) PriceSummary {
+	summary := PriceSummary{Count: len(prices)}
+	if len(prices) == 0 {
+		return summary
+	}
+
+	sort.Float64s(prices)
+	var sum float64
+	for _, price := range prices {
+		sum += price
+	}
+	minPrice, maxPrice := prices[0], prices[len(prices)-1]
+	avgPrice := sum / float64(len(prices))
+	medianPrice := prices[len(prices)/2]
+	if len(prices)%2 == 0 {
+		medianPrice = (prices[len(prices)/2-1] + medianPrice) / 2
+	}
+
+	summary.MinPrice = &minPrice
+	summary.MaxPrice = &maxPrice
+	summary.AvgPrice = &avgPrice
+	summary.MedianPrice = &medianPrice
+	return summary
+}
@@ -0,0 +1,107 @@
+package models_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+func productCreatedAt(id string, price float64, createdAt string) *models.Product {
+	created, _ := time.Parse(time.RFC3339, createdAt)
+	return &models.Product{ID: id, Name: "Product " + id, Price: price, CreatedAt: created}
+}
+
+func TestProductStatsBuilder(t *testing.T) {
+	t.Run("Summary And Median", func(t *testing.T) {
+		builder := models.NewProductStatsBuilder(models.StatsGroupNone)
+		for i, price := range []float64{40, 10, 30, 20} {
+			builder.Add(productCreatedAt(string(rune('a'+i)), price, "2025-01-01T00:00:00Z"))
+		}
+
+		stats := builder.Build()
+
+		if stats.Count != 4 || *stats.MinPrice != 10 || *stats.MaxPrice != 40 || *stats.AvgPrice != 25 || *stats.MedianPrice != 25 {
+			t.Errorf("Unexpected summary: %+v", stats.PriceSummary)
+		}
+		if stats.Groups != nil {
+			t.Errorf("Expected no groups, got %+v", stats.Groups)
+		}
+	})
+
+	t.Run("Groups By Month In Chronological Order", func(t *testing.T) {
+		builder := models.NewProductStatsBuilder(models.StatsGroupMonth)
+		builder.Add(productCreatedAt("1", 30, "2025-02-10T12:00:00Z"))
+		builder.Add(productCreatedAt("2", 10, "2025-01-31T23:30:00-03:00")) // 2025-02-01 em UTC
+		builder.Add(productCreatedAt("3", 20, "2024-12-05T08:00:00Z"))
+
+		stats := builder.Build()
+
+		if len(stats.Groups) != 2 {
+			t.Fatalf("Expected 2 groups, got %+v", stats.Groups)
+		}
+		if stats.Groups[0].Period != "2024-12" || stats.Groups[0].Count != 1 {
+			t.Errorf("Unexpected first group: %+v", stats.Groups[0])
+		}
+		if stats.Groups[1].Period != "2025-02" || stats.Groups[1].Count != 2 || *stats.Groups[1].MedianPrice != 20 {
+			t.Errorf("Unexpected second group: %+v", stats.Groups[1])
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		stats := models.NewProductStatsBuilder(models.StatsGroupDay).Build()
+
+		if stats.Count != 0 || stats.MinPrice != nil || stats.MedianPrice != nil || len(stats.Groups) != 0 {
+			t.Errorf("Expected an empty summary, got %+v", stats)
+		}
+	})
+}
+
+func TestProductFilter(t *testing.T) {
+	minPrice, maxPrice := 10.0, 20.0
+	filter := models.ProductFilter{
+		NameContains: "PROD",
+		MinPrice:     &minPrice,
+		MaxPrice:     &maxPrice,
+		CreatedFrom:  time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		CreatedTo:    time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	cases := map[*models.Product]bool{
+		productCreatedAt("1", 15, "2025-01-15T00:00:00Z"): true,
+		productCreatedAt("2", 20, "2025-01-01T00:00:00Z"): true,
+		productCreatedAt("3", 25, "2025-01-15T00:00:00Z"): false,
+		productCreatedAt("4", 15, "2025-02-01T00:00:00Z"): false,
+	}
+	for product, expected := range cases {
+		if filter.Matches(product) != expected {
+			t.Errorf("Expected Matches(%s) to be %v", product, expected)
+		}
+	}
+
+	inverted := models.ProductFilter{MinPrice: &maxPrice, MaxPrice: &minPrice}
+	if err := inverted.Validate(); !errors.Is(err, models.ErrInvalidProductQuery) {
+		t.Errorf("Expected ErrInvalidProductQuery, got %v", err)
+	}
+}
+
+func TestProductProjection(t *testing.T) {
+	projection := models.ProductProjection{models.ProductFieldName}
+	for _, field := range models.ProductFields {
+		expected := field == models.ProductFieldID || field == models.ProductFieldName
+		if projection.Includes(field) != expected {
+			t.Errorf("Expected Includes(%s) to be %v", field, expected)
+		}
+		if !models.ProductProjection(nil).Includes(field) {
+			t.Errorf("Expected an empty projection to include %s", field)
+		}
+	}
+
+	if err := projection.Validate(); err != nil {
+		t.Errorf("Expected a valid projection, got %v", err)
+	}
+	if err := (models.ProductProjection{"Color"}).Validate(); !errors.Is(err, models.ErrInvalidProductFields) {
+		t.Errorf("Expected ErrInvalidProductFields, got %v", err)
+	}
+}
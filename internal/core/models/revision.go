@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// ProductRevision é uma versão de um produto, válida de ValidFrom até ValidTo (nulo para a versão corrente).
+// A exclusão gera uma revisão com Deleted verdadeiro e Product nulo; se o produto for recriado com o mesmo ID,
+// a numeração continua a partir dela.
+type ProductRevision struct {
+	ProductID string
+	Revision  int
+	Product   *Product
+	Deleted   bool
+	ValidFrom time.Time
+	ValidTo   *time.Time
+}
+
+// NewProductRevision cria a revisão com o número informado correspondente ao evento.
+func NewProductRevision(revision int, event ProductEvent) *ProductRevision {
+	rev := &ProductRevision{
+		ProductID: event.ProductID,
+		Revision:  revision,
+		Product:   event.Product,
+		ValidFrom: event.OccurredAt,
+	}
+	if event.Type == EventProductDeleted {
+		rev.Product = nil
+		rev.Deleted = true
+	}
+	return rev
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"net/url"
+	"slices"
+	"time"
+)
+
+// WebhookSubscription representa a inscrição de um parceiro para receber callbacks HTTP.
+// Uma lista de EventTypes vazia indica interesse em todos os tipos de eventos.
+type WebhookSubscription struct {
+	ID         string
+	URL        string
+	EventTypes []EventType
+	Secret     string
+	CreatedAt  time.Time
+}
+
+// NewWebhookSubscription valida os dados e cria uma nova inscrição.
+// Quando secret é vazio, um segredo aleatório é gerado.
+func NewWebhookSubscription(rawURL string, eventTypes []EventType, secret string) (*WebhookSubscription, error) {
+	sub := &WebhookSubscription{
+		ID:         NewID(),
+		URL:        rawURL,
+		EventTypes: eventTypes,
+		Secret:     secret,
+		CreatedAt:  time.Now(),
+	}
+	if sub.Secret == "" {
+		sub.Secret = NewID()
+	}
+	if err := sub.Validate(); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// Validate verifica se a URL é absoluta (http ou https) e se os tipos de eventos são conhecidos.
+func (s *WebhookSubscription) Validate() error {
+	u, err := url.Parse(s.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidWebhookURL
+	}
+	for _, eventType := range s.EventTypes {
+		if !eventType.IsValid() {
+			return ErrInvalidEventType
+		}
+	}
+	if s.Secret == "" {
+		return ErrInvalidWebhookSecret
+	}
+	return nil
+}
+
+// Matches informa se a inscrição deve receber eventos do tipo informado.
+func (s *WebhookSubscription) Matches(eventType EventType) bool {
+	return len(s.EventTypes) == 0 || slices.Contains(s.EventTypes, eventType)
+}
+
+// DeliveryStatus indica a situação de uma entrega de webhook.
+type DeliveryStatus string
+
+// Situações possíveis de uma entrega de webhook.
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryDead      DeliveryStatus = "dead" // Tentativas esgotadas: a entrega foi para a fila de mensagens mortas.
+)
+
+// WebhookDelivery representa a entrega de um evento a uma inscrição, com o histórico da última tentativa.
+type WebhookDelivery struct {
+	ID             string
+	SubscriptionID string
+	Event          ProductEvent
+	Status         DeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastAttemptAt  *time.Time
+	ResponseStatus int
+	LastError      string
+	CreatedAt      time.Time
+}
+
+// NewWebhookDelivery cria uma entrega pendente do evento para a inscrição.
+func NewWebhookDelivery(subscriptionID string, event ProductEvent) *WebhookDelivery {
+	now := time.Now()
+	return &WebhookDelivery{
+		ID:             NewID(),
+		SubscriptionID: subscriptionID,
+		Event:          event,
+		Status:         DeliveryPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+	}
+}
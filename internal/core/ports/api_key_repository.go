@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+// APIKeyRepository define a porta de persistência das chaves de API. As chaves revogadas são mantidas,
+// para que continuem aparecendo na listagem e na auditoria.
+type APIKeyRepository interface {
+	AddAPIKey(key *models.APIKey) error
+	GetAPIKey(id string) (*models.APIKey, error)
+	// ListAPIKeys retorna todas as chaves, inclusive as revogadas e as expiradas, pela data de criação.
+	ListAPIKeys() ([]*models.APIKey, error)
+	// RevokeAPIKey marca a chave como revogada no instante informado. Revogar novamente não altera a data.
+	RevokeAPIKey(id string, at time.Time) error
+	// TouchAPIKey registra o último uso da chave.
+	TouchAPIKey(id string, at time.Time) error
+}
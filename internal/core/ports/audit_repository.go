@@ -0,0 +1,11 @@
+package ports
+
+import "github.com/danielrios/product-service-go/internal/core/models"
+
+// AuditRepository define a porta de consulta ao log de auditoria.
+// O log é apenas de inserção e é gravado pelos próprios repositórios de produtos, na mesma transação de
+// cada mutação: não há operações de alteração ou remoção de registros.
+type AuditRepository interface {
+	// ListAuditEntries retorna os registros que atendem ao filtro, dos mais recentes para os mais antigos.
+	ListAuditEntries(filter models.AuditFilter) ([]*models.AuditEntry, error)
+}
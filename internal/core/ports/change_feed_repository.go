@@ -0,0 +1,11 @@
+package ports
+
+import "github.com/danielrios/product-service-go/internal/core/models"
+
+// ChangeFeedRepository define a porta de leitura do feed de alterações de produtos.
+// Cada mutação recebe uma sequência maior que todas as já confirmadas, de modo que um consumidor
+// pode retomar a leitura com segurança a partir da última sequência processada.
+type ChangeFeedRepository interface {
+	// ChangesSince retorna, em ordem crescente, até limit alterações com sequência maior que since.
+	ChangesSince(since int64, limit int) ([]*models.ProductChange, error)
+}
@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+// CredentialVerifier define a porta para a verificação das credenciais de um esquema de autenticação,
+// como o token de "Authorization: Bearer <token>". Credenciais inválidas, expiradas ou de outro emissor
+// devem resultar em um erro que embrulhe models.ErrInvalidCredentials.
+type CredentialVerifier interface {
+	Verify(ctx context.Context, credentials string) (*models.Principal, error)
+}
@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+// EventSink define a porta para o destino final dos eventos de produtos.
+// Implementações devem ser idempotentes em relação ao ID do evento, pois a entrega é "pelo menos uma vez".
+type EventSink interface {
+	Publish(ctx context.Context, event models.ProductEvent) error
+}
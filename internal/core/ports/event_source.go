@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+// ProductEventSource define a porta para acompanhar, em tempo real, as alterações de produtos.
+// Diferente do outbox, não há garantia de entrega: eventos ocorridos enquanto ninguém escuta são perdidos.
+type ProductEventSource interface {
+	// Listen invoca handle para cada evento, na ordem em que as alterações foram confirmadas,
+	// até que o contexto seja cancelado ou ocorra um erro. handle não deve bloquear.
+	Listen(ctx context.Context, handle func(event models.ProductEvent)) error
+}
@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+// IdempotencyRepository define a porta de persistência das chaves de idempotência.
+type IdempotencyRepository interface {
+	// Reserve grava o registro se a chave não existir ou se o registro existente tiver expirado.
+	// Caso contrário, não altera nada e retorna o registro existente.
+	Reserve(record *models.IdempotencyRecord) (existing *models.IdempotencyRecord, err error)
+	// Complete grava a resposta de uma chave reservada e estende sua validade.
+	Complete(record *models.IdempotencyRecord) error
+	// Release remove uma chave reservada, permitindo que a requisição seja repetida.
+	Release(key string) error
+	// DeleteExpired remove os registros expirados até o instante informado, retornando quantos foram removidos.
+	DeleteExpired(now time.Time) (int, error)
+}
@@ -0,0 +1,27 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+// JobRepository define a porta de persistência da fila de jobs assíncronos.
+type JobRepository interface {
+	AddJob(job *models.Job) error
+	GetJob(id string) (*models.Job, error)
+	// ClaimNextJob reserva o job mais antigo na fila ou, se houver, um job em execução cujo lease expirou
+	// (deixado para trás por um processo interrompido). O job passa a running, com lease até now+lease e
+	// Attempts incrementado. Retorna nil quando não há jobs a executar.
+	ClaimNextJob(lease time.Duration) (*models.Job, error)
+	// HeartbeatJob renova o lease de um job em execução e grava seu progresso,
+	// informando se o cancelamento foi solicitado.
+	HeartbeatJob(id string, progress models.JobProgress, lease time.Duration) (cancelRequested bool, err error)
+	// UpdateJob grava a situação, o progresso, o resultado e as datas do job.
+	UpdateJob(job *models.Job) error
+	// RequestJobCancellation cancela imediatamente um job na fila ou marca um job em execução para
+	// cancelamento, retornando o job atualizado. Jobs já finalizados resultam em ErrJobAlreadyFinished.
+	RequestJobCancellation(id string) (*models.Job, error)
+	// DeleteFinishedJobs remove os jobs finalizados antes do instante informado, retornando quantos foram removidos.
+	DeleteFinishedJobs(before time.Time) (int, error)
+}
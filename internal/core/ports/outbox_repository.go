@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+// OutboxRepository define a porta para leitura das mensagens do outbox transacional.
+// As mensagens são gravadas pelo próprio adaptador de persistência, na mesma transação
+// da alteração do produto, garantindo que nenhum evento seja perdido.
+type OutboxRepository interface {
+	// ProcessPending reserva até limit mensagens pendentes cuja próxima tentativa já venceu
+	// e invoca deliver para cada uma, em ordem de gravação. Mensagens entregues com sucesso
+	// são marcadas como despachadas; as que falharem são reagendadas para daqui a
+	// retryAfter(tentativas). Retorna a quantidade de mensagens processadas.
+	ProcessPending(limit int, deliver func(msg *models.OutboxMessage) error, retryAfter func(attempts int) time.Duration) (int, error)
+}
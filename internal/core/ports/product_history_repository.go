@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+// ProductHistoryRepository define a porta de leitura do histórico de revisões de produtos.
+// As revisões são gravadas pelo adaptador de persistência junto com cada mutação.
+type ProductHistoryRepository interface {
+	// ListRevisions retorna as revisões do produto em ordem crescente, ou models.ErrProductNotFound se não houver nenhuma.
+	ListRevisions(productID string) ([]*models.ProductRevision, error)
+	// GetAsOf retorna o produto como estava no instante informado, ou models.ErrProductNotFound
+	// se ele ainda não existia ou estava excluído naquele instante.
+	GetAsOf(productID string, at time.Time) (*models.Product, error)
+}
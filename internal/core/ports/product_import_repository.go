@@ -0,0 +1,17 @@
+package ports
+
+import "github.com/danielrios/product-service-go/internal/core/models"
+
+// ProductImportRepository define a porta de gravação de produtos em lote.
+type ProductImportRepository interface {
+	// ExistingProducts retorna o estado atual dos produtos que já existem, dentre os IDs informados,
+	// indexados pelo ID.
+	ExistingProducts(ids []string) (map[string]*models.Product, error)
+	// ImportProducts grava todos os produtos atomicamente, com os mesmos efeitos derivados (outbox, feed de
+	// alterações, revisões, auditoria e notificações) das mutações individuais. No modo create, falha com
+	// ErrProductAlreadyExists se algum ID já existir; no modo upsert, atualiza nome e preço dos existentes,
+	// preservando a data de criação. Quando expected é informado, é o estado lido por ExistingProducts: se
+	// algum produto tiver sido criado, excluído ou tiver o nome ou o preço alterados desde a leitura, nada
+	// é gravado e o resultado é ErrProductModified. O resultado segue a ordem dos produtos informados.
+	ImportProducts(products []*models.Product, expected map[string]*models.Product, mode models.ImportMode, mutation models.Mutation) ([]models.ImportedProduct, error)
+}
@@ -0,0 +1,16 @@
+package ports
+
+import "github.com/danielrios/product-service-go/internal/core/models"
+
+// ProductQueryRepository define a porta de consultas filtradas e agregadas sobre os produtos,
+// permitindo que cada adaptador as resolva da forma mais eficiente para o seu armazenamento.
+type ProductQueryRepository interface {
+	// Find retorna os produtos que atendem ao filtro, ordenados pela data de criação, com pelo menos os
+	// campos da projeção preenchidos.
+	Find(filter models.ProductFilter, projection models.ProductProjection) ([]*models.Product, error)
+	// ForEach percorre, na mesma ordem de Find, os produtos que atendem ao filtro, sem carregá-los todos
+	// em memória. A iteração é interrompida no primeiro erro retornado por fn, que é então devolvido.
+	ForEach(filter models.ProductFilter, fn func(product *models.Product) error) error
+	// Stats calcula as estatísticas dos produtos que atendem ao filtro.
+	Stats(filter models.ProductFilter, groupBy models.StatsGrouping) (*models.ProductStats, error)
+}
@@ -5,10 +5,14 @@ import "github.com/danielrios/product-service-go/internal/core/models"
 // ProductRepository define a porta (interface) para operações de persistência de produtos.
 // Esta interface é agnóstica a qualquer tecnologia de banco de dados ou forma de armazenamento.
 // Ela representa o contrato que o domínio espera de qualquer adaptador de persistência.
+// As mutações registram no log de auditoria, atomicamente com a alteração, o autor e a requisição informados.
 type ProductRepository interface {
 	GetAll() ([]*models.Product, error)
 	GetByID(id string) (*models.Product, error)
-	Add(product *models.Product) error
-	Update(product *models.Product) error
-	Delete(id string) error
+	Add(product *models.Product, mutation models.Mutation) error
+	// Update grava o nome e o preço do produto. Quando expected é informado, a gravação só ocorre se o
+	// nome e o preço atuais forem os de expected, verificados sob o mesmo lock da gravação; caso
+	// contrário, retorna ErrProductModified.
+	Update(product, expected *models.Product, mutation models.Mutation) error
+	Delete(id string, mutation models.Mutation) error
 }
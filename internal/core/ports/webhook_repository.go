@@ -0,0 +1,41 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/danielrios/product-service-go/internal/core/models"
+)
+
+// WebhookRepository define a porta de persistência das inscrições de webhooks e de suas entregas.
+type WebhookRepository interface {
+	AddSubscription(sub *models.WebhookSubscription) error
+	GetSubscription(id string) (*models.WebhookSubscription, error)
+	ListSubscriptions() ([]*models.WebhookSubscription, error)
+	UpdateSubscription(sub *models.WebhookSubscription) error
+	DeleteSubscription(id string) error
+
+	// EnqueueDeliveries grava as entregas informadas. Entregas de um mesmo evento para uma
+	// mesma inscrição são ignoradas, tornando o enfileiramento idempotente.
+	EnqueueDeliveries(deliveries []*models.WebhookDelivery) error
+	// ClaimDueDeliveries reserva até limit entregas pendentes e vencidas, adiando a próxima
+	// tentativa de cada uma em lease para que não sejam reservadas novamente enquanto em curso.
+	ClaimDueDeliveries(limit int, lease time.Duration) ([]*models.WebhookDelivery, error)
+	GetDelivery(id string) (*models.WebhookDelivery, error)
+	UpdateDelivery(delivery *models.WebhookDelivery) error
+	// ListDeliveries retorna o histórico de entregas da inscrição, das mais recentes para as mais antigas.
+	ListDeliveries(subscriptionID string, limit int) ([]*models.WebhookDelivery, error)
+}
+
+// WebhookRequest representa uma requisição de callback a ser enviada a um parceiro.
+type WebhookRequest struct {
+	URL     string
+	Headers map[string]string
+	Body    []byte
+}
+
+// WebhookSender define a porta para o envio de callbacks HTTP.
+// Retorna o código de status da resposta ou um erro de transporte.
+type WebhookSender interface {
+	Send(ctx context.Context, req WebhookRequest) (statusCode int, err error)
+}